@@ -0,0 +1,138 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/fnoutil"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+// loadAddressLabels loads the address labels registry from the labels file at
+// path, if path is not empty. The project fund and burn addresses are labeled
+// unless the file labels them.
+func loadAddressLabels(path string, params *chaincfg.Params) (*dbtypes.AddressLabels, error) {
+	labels := &dbtypes.AddressLabels{Labels: []*dbtypes.AddressLabel{}}
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open address labels file: %v", err)
+		}
+		defer f.Close()
+		labels, err = parseAddressLabels(f, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address labels file %s: %v", path, err)
+		}
+	}
+
+	labeled := make(map[string]bool, len(labels.Labels))
+	for _, label := range labels.Labels {
+		labeled[label.Address] = true
+	}
+	for _, label := range builtinAddressLabels(params) {
+		if !labeled[label.Address] {
+			labels.Labels = append(labels.Labels, label)
+		}
+	}
+	return labels, nil
+}
+
+// reloadAddressLabels loads the address labels registry from the labels file at
+// path, like loadAddressLabels, to replace the loaded labels of version
+// loadedVersion. The labels are rejected unless their version is greater than
+// loadedVersion.
+func reloadAddressLabels(path string, params *chaincfg.Params, loadedVersion int64) (*dbtypes.AddressLabels, error) {
+	labels, err := loadAddressLabels(path, params)
+	if err != nil {
+		return nil, err
+	}
+	if labels.Version <= loadedVersion {
+		return nil, fmt.Errorf("version %d of the address labels file %s is "+
+			"not greater than the loaded version %d", labels.Version, path,
+			loadedVersion)
+	}
+	return labels, nil
+}
+
+// parseAddressLabels decodes a JSON address labels file of the form
+// {"version": N, "labels": [{"address": ..., "label": ..., "category": ...,
+// "url": ...}, ...]}. The version must be positive, each address must be
+// valid for the network and labeled only once, and the categories must be one
+// of dbtypes.AddressLabelCategories.
+func parseAddressLabels(r io.Reader, params *chaincfg.Params) (*dbtypes.AddressLabels, error) {
+	var labels dbtypes.AddressLabels
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&labels); err != nil {
+		return nil, err
+	}
+	if labels.Version < 1 {
+		return nil, fmt.Errorf("invalid version %d", labels.Version)
+	}
+
+	categories := make(map[string]bool, len(dbtypes.AddressLabelCategories))
+	for _, category := range dbtypes.AddressLabelCategories {
+		categories[category] = true
+	}
+	addresses := make(map[string]bool, len(labels.Labels))
+	for _, label := range labels.Labels {
+		if label == nil {
+			return nil, fmt.Errorf("null label")
+		}
+		label.Address = strings.TrimSpace(label.Address)
+		label.Label = strings.TrimSpace(label.Label)
+		if label.Label == "" {
+			return nil, fmt.Errorf("empty label for address %q", label.Address)
+		}
+		if !categories[label.Category] {
+			return nil, fmt.Errorf("invalid category %q for address %q, expected one of %s",
+				label.Category, label.Address,
+				strings.Join(dbtypes.AddressLabelCategories, ", "))
+		}
+		addr, err := fnoutil.DecodeAddress(label.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %v", label.Address, err)
+		}
+		if !addr.IsForNet(params) {
+			return nil, fmt.Errorf("address %q is not for %s", label.Address,
+				params.Name)
+		}
+		if addresses[label.Address] {
+			return nil, fmt.Errorf("address %q is labeled more than once", label.Address)
+		}
+		addresses[label.Address] = true
+	}
+	if labels.Labels == nil {
+		labels.Labels = []*dbtypes.AddressLabel{}
+	}
+	return &labels, nil
+}
+
+// builtinAddressLabels are the labels of the network's project fund address
+// and the zero pubkey hash address, to which coins are burned.
+func builtinAddressLabels(params *chaincfg.Params) []*dbtypes.AddressLabel {
+	var labels []*dbtypes.AddressLabel
+	if devAddress, err := dbtypes.DevSubsidyAddress(params); err == nil {
+		labels = append(labels, &dbtypes.AddressLabel{
+			Address:  devAddress,
+			Label:    "Project Fund",
+			Category: dbtypes.LabelTreasury,
+		})
+	}
+	if zeroAddress, err := txhelpers.ZeroHashP2PHKAddress(params); err == nil {
+		labels = append(labels, &dbtypes.AddressLabel{
+			Address:  zeroAddress,
+			Label:    "Burn Address",
+			Category: dbtypes.LabelBurn,
+		})
+	}
+	return labels
+}
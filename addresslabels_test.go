@@ -0,0 +1,105 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/fnoutil"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+func TestParseAddressLabels(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	addr, _ := fnoutil.NewAddressPubKeyHash([]byte("01234567890123456789"), params, 0)
+	address := addr.EncodeAddress()
+
+	labels, err := parseAddressLabels(strings.NewReader(`{"version": 3, "labels": [
+		{"address": " `+address+`", "label": "Exchange ", "category": "exchange", "url": "https://example.com"}
+	]}`), params)
+	if err != nil {
+		t.Fatalf("parseAddressLabels: %v", err)
+	}
+	if labels.Version != 3 || len(labels.Labels) != 1 {
+		t.Fatalf("unexpected labels %+v", labels)
+	}
+	label := labels.Labels[0]
+	if label.Address != address || label.Label != "Exchange" ||
+		label.Category != dbtypes.LabelExchange || label.URL != "https://example.com" {
+		t.Errorf("unexpected label %+v", label)
+	}
+
+	testAddr, _ := fnoutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.TestNet3Params, 0)
+	invalid := []string{
+		`{"labels": []}`,
+		`{"version": 1, "labels": [null]}`,
+		`{"version": 1, "labels": [{"address": "` + address + `", "label": "", "category": "pool"}]}`,
+		`{"version": 1, "labels": [{"address": "` + address + `", "label": "a", "category": "shop"}]}`,
+		`{"version": 1, "labels": [{"address": "Dsx", "label": "a", "category": "pool"}]}`,
+		`{"version": 1, "labels": [{"address": "` + testAddr.EncodeAddress() + `", "label": "a", "category": "pool"}]}`,
+		`{"version": 1, "labels": [{"address": "` + address + `", "label": "a", "category": "pool"},
+			{"address": "` + address + `", "label": "b", "category": "pool"}]}`,
+		`{"version": 1, "labels": [], "extra": true}`,
+	}
+	for _, file := range invalid {
+		if _, err = parseAddressLabels(strings.NewReader(file), params); err == nil {
+			t.Errorf("expected an error for %s", file)
+		}
+	}
+}
+
+func TestLoadAddressLabels(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	zeroAddress, _ := txhelpers.ZeroHashP2PHKAddress(params)
+	devAddress, _ := dbtypes.DevSubsidyAddress(params)
+
+	labels, err := loadAddressLabels("", params)
+	if err != nil {
+		t.Fatalf("loadAddressLabels: %v", err)
+	}
+	if labels.Version != 0 || len(labels.Labels) != 2 ||
+		labels.Labels[0].Address != devAddress || labels.Labels[1].Address != zeroAddress {
+		t.Fatalf("unexpected built-in labels %+v", labels)
+	}
+
+	// A label in the file replaces the built-in label.
+	f, err := ioutil.TempFile("", "fnodata_labels.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`{"version": 1, "labels": [{"address": "` + zeroAddress +
+		`", "label": "Dead End", "category": "burn"}]}`)
+	f.Close()
+
+	labels, err = loadAddressLabels(f.Name(), params)
+	if err != nil {
+		t.Fatalf("loadAddressLabels: %v", err)
+	}
+	if labels.Version != 1 || len(labels.Labels) != 2 ||
+		labels.Labels[0].Label != "Dead End" || labels.Labels[1].Address != devAddress {
+		t.Errorf("unexpected labels %+v", labels)
+	}
+
+	if _, err = loadAddressLabels(f.Name()+".missing", params); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+
+	// A reload must increase the version.
+	if _, err = reloadAddressLabels(f.Name(), params, 1); err == nil {
+		t.Errorf("expected an error reloading the same version")
+	}
+	labels, err = reloadAddressLabels(f.Name(), params, 0)
+	if err != nil {
+		t.Fatalf("reloadAddressLabels: %v", err)
+	}
+	if labels.Version != 1 {
+		t.Errorf("unexpected labels version %d", labels.Version)
+	}
+}
@@ -32,6 +32,7 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 
 	mux.Get("/status", app.status)
 	mux.Get("/supply", app.coinSupply)
+	mux.Get("/supply/breakdown", app.getSupplyBreakdown)
 
 	compMiddleware := m.Next
 	if compressLarge {
@@ -50,6 +51,8 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 				rt.Get("/raw", app.getBlockHeaderRaw)
 			})
 			rd.Get("/raw", app.getBlockRaw)
+			rd.Get("/cfilter", app.getBlockFilter)
+			rd.Get("/miner", app.getBlockMiner)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
@@ -69,6 +72,8 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 				rt.Get("/raw", app.getBlockHeaderRaw)
 			})
 			rd.Get("/raw", app.getBlockRaw)
+			rd.Get("/cfilter", app.getBlockFilter)
+			rd.Get("/miner", app.getBlockMiner)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
@@ -88,6 +93,8 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 			})
 			rd.Get("/hash", app.getBlockHash)
 			rd.Get("/raw", app.getBlockRaw)
+			rd.Get("/cfilter", app.getBlockFilter)
+			rd.Get("/miner", app.getBlockMiner)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
@@ -103,6 +110,7 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 			rd.Use(compMiddleware)
 			rd.Get("/", app.getBlockRangeSummary)
 			rd.Get("/size", app.getBlockRangeSize)
+			rd.Get("/cfheaders", app.getFilterHeaders)
 			rd.Route("/{step}", func(rs chi.Router) {
 				rs.Use(m.BlockStepPathCtx)
 				rs.Get("/", app.getBlockRangeSteppedSummary)
@@ -124,6 +132,7 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 			rd.With(m.BlockIndexPathCtx).Get("/b/{idx}", app.getTicketPoolInfo)
 			rd.With(m.BlockIndexOrHashPathCtx).Get("/b/{idxorhash}/full", app.getTicketPool)
 			rd.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).Get("/r/{idx0}/{idx}", app.getTicketPoolInfoRange)
+			rd.Get("/forecast", app.getTicketPoolForecast)
 		})
 		r.Route("/diff", func(rd chi.Router) {
 			rd.Get("/", app.getStakeDiffSummary)
@@ -133,6 +142,9 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 			rd.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).Get("/r/{idx0}/{idx}", app.getStakeDiffRange)
 		})
 		r.Get("/powerless", app.getPowerlessTickets)
+		r.Get("/versions", app.getVersionHistory)
+		r.With(m.ChartGroupingCtx).Get("/misses/{chartgrouping}", app.getMissRates)
+		r.With(m.PaginationCtx).Get("/unrevoked", app.getUnrevokedTickets)
 	})
 
 	mux.Route("/tx", func(r chi.Router) {
@@ -151,6 +163,7 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 				})
 				rd.Get("/vinfo", app.getTxVoteInfo)
 				rd.Get("/tinfo", app.getTxTicketInfo)
+				rd.Get("/merkleproof", app.getTxMerkleProof)
 			})
 		})
 		r.With(m.TransactionHashCtx).Get("/hex/{txid}", app.getTransactionHex)
@@ -168,6 +181,10 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 		r.Route("/{address}", func(rd chi.Router) {
 			rd.Use(m.AddressPathCtx)
 			rd.Get("/totals", app.addressTotals)
+			rd.Get("/balance", app.addressBalanceAt)
+			rd.Get("/script", app.addressScript)
+			rd.Get("/label", app.addressLabel)
+			rd.With(m.PaginationCtx).Get("/commitments", app.addressTicketCommitments)
 			rd.Get("/", app.getAddressTransactions)
 			rd.With(m.ChartGroupingCtx).Get("/types/{chartgrouping}", app.getAddressTxTypesData)
 			rd.With(m.ChartGroupingCtx).Get("/amountflow/{chartgrouping}", app.getAddressTxAmountFlowData)
@@ -185,6 +202,39 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 		})
 	})
 
+	mux.Route("/xpub/{xpub}", func(r chi.Router) {
+		r.Use(m.XpubPathCtx)
+		r.Get("/", app.getXpubBalance)
+		r.Get("/addresses", app.getXpubAddresses)
+		r.With(m.PaginationCtx).Get("/txs", app.getXpubTransactions)
+		r.Get("/utxos", app.getXpubUTXOs)
+	})
+
+	mux.With(m.PaginationCtx).Get("/nulldata/search", app.searchNullData)
+
+	// Stake pool (voting service provider) statistics.
+	mux.Get("/stakepools", app.getStakePools)
+	mux.Route("/stakepool/{pool}", func(r chi.Router) {
+		r.Use(m.StakePoolCtx)
+		r.Get("/", app.getStakePool)
+		r.Get("/chart", app.getStakePoolChart)
+	})
+
+	// Project fund (treasury) flows.
+	mux.Get("/treasury", app.getTreasuryFlows)
+
+	// Mining pool shares of the blocks mined.
+	mux.Route("/miners", func(r chi.Router) {
+		r.Get("/", app.getMiningPools)
+		r.Get("/chart", app.getMiningPoolChart)
+	})
+
+	// Daily UTXO age band distributions (HODL waves).
+	mux.Get("/utxo/ages", app.getUTXOAges)
+
+	// Known address labels.
+	mux.Get("/labels", app.getAddressLabels)
+
 	// Returns agenda data like; description, name, lockedin activated and other
 	// high level agenda details for all agendas.
 	mux.Route("/agendas", func(r chi.Router) {
@@ -194,6 +244,7 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 	// Returns the charts data for the respective individual agendas.
 	mux.Route("/agenda", func(r chi.Router) {
 		r.With(m.AgendIdCtx).Get("/{agendaId}", app.getAgendaData)
+		r.With(m.AgendIdCtx).Get("/{agendaId}/addresses", app.getAgendaVotesByAddress)
 	})
 
 	mux.Route("/mempool", func(r chi.Router) {
@@ -206,6 +257,13 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 			rd.Get("/details", app.getSSTxDetails)
 			rd.With(m.NPathCtx).Get("/details/{N}", app.getSSTxDetails)
 		})
+		// fee rate estimates
+		r.Get("/fees/estimate", app.getFeeEstimates)
+		// mempool history
+		r.Route("/history", func(rd chi.Router) {
+			rd.With(m.TransactionHashCtx).Get("/tx/{txid}", app.getMempoolTxHistory)
+			rd.Get("/fees", app.getMempoolFeeHistory)
+		})
 	})
 
 	mux.Route("/chart", func(r chi.Router) {
@@ -225,9 +283,16 @@ func NewAPIRouter(app *appContext, useRealIP, compressLarge bool) apiMux {
 	})
 
 	mux.Route("/proposal", func(r chi.Router) {
+		// Alias of /proposals/search.
+		r.With(m.PaginationCtx).Get("/search", app.searchProposals)
 		r.With(m.ProposalTokenCtx).Get("/{token}", app.getProposalChartData)
+		r.With(m.ProposalTokenCtx).Get("/{token}/votes", app.getProposalTicketVotes)
+		r.With(m.ProposalTokenCtx).Get("/{token}/timeline", app.getProposalVotesTimeline)
+		r.With(m.ProposalTokenCtx).Get("/{token}/archive", app.getProposalArchive)
 	})
 
+	mux.With(m.PaginationCtx).Get("/proposals/search", app.searchProposals)
+
 	mux.Route("/exchanges", func(r chi.Router) {
 		r.Get("/", app.getExchanges)
 		r.Get("/codes", app.getCurrencyCodes)
@@ -282,6 +347,12 @@ func NewFileRouter(app *appContext, useRealIP bool) fileMux {
 		rd.With(m.AddressPathCtx).Get("/io/{address}", app.addressIoCsv)
 	})
 
+	mux.Route("/agenda", func(rd chi.Router) {
+		// Allow browser cache for 3 minutes.
+		rd.Use(m.CacheControl(180))
+		rd.With(m.AgendIdCtx).Get("/{agendaId}/votes", app.agendaVotesCsv)
+	})
+
 	return fileMux{mux}
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnod/chaincfg/chainhash"
 	"github.com/fonero-project/fnod/fnojson"
+	"github.com/fonero-project/fnod/fnoutil"
 	"github.com/fonero-project/fnod/rpcclient"
 	"github.com/fonero-project/fnod/wire"
 	apitypes "github.com/fonero-project/fnodata/api/types"
@@ -31,12 +32,68 @@ import (
 	"github.com/fonero-project/fnodata/db/dbtypes"
 	"github.com/fonero-project/fnodata/exchanges"
 	"github.com/fonero-project/fnodata/gov/agendas"
+	"github.com/fonero-project/fnodata/gov/politeia"
+	pitypes "github.com/fonero-project/fnodata/gov/politeia/types"
 	m "github.com/fonero-project/fnodata/middleware"
 	"github.com/fonero-project/fnodata/txhelpers"
 	notify "github.com/fonero-project/fnodata/notification"
 	appver "github.com/fonero-project/fnodata/version"
 )
 
+const (
+	// defaultAgendaVoteAddresses is the number of voting addresses returned by
+	// the agenda votes by address endpoint when no limit is given.
+	defaultAgendaVoteAddresses = 100
+	// maxAgendaVoteAddresses is the maximum number of voting addresses that may
+	// be requested from the agenda votes by address endpoint.
+	maxAgendaVoteAddresses = 1000
+
+	// defaultMempoolFeeHistory is the time range of the mempool fee snapshots
+	// returned when no start time is given.
+	defaultMempoolFeeHistory = 24 * time.Hour
+	// maxMempoolFeeSnapshots is the maximum number of mempool fee snapshots
+	// returned for a time range.
+	maxMempoolFeeSnapshots = 2000
+
+	// defaultXpubGapLimit is the number of consecutive unused addresses after
+	// which extended public key address discovery stops, when no gap limit is
+	// given.
+	defaultXpubGapLimit = 20
+	// maxXpubGapLimit is the largest gap limit that may be requested.
+	maxXpubGapLimit = 200
+
+	// maxBalanceHeights is the maximum number of block heights for which
+	// historical address balances may be requested at once.
+	maxBalanceHeights = 100
+
+	// maxFilterHeaders is the maximum number of block filter headers that may
+	// be requested at once.
+	maxFilterHeaders = 2000
+
+	// maxNullDataResults is the maximum number of nulldata outputs returned by
+	// a single nulldata search.
+	maxNullDataResults = 500
+
+	// maxTicketCommitments is the maximum number of ticket commitments to a
+	// reward address that may be requested at once.
+	maxTicketCommitments = 1000
+
+	// maxUnrevokedTickets is the maximum number of unrevoked tickets that may
+	// be requested at once.
+	maxUnrevokedTickets = 1000
+
+	// maxProposalSearchResults is the maximum number of proposals returned by
+	// a single proposal search.
+	maxProposalSearchResults = 100
+
+	// defaultMiningPoolBlocks is the number of most recent blocks over which
+	// mining pool shares are computed when no block count is given.
+	defaultMiningPoolBlocks = 4032
+	// maxMiningPoolBlocks is the largest block count that may be requested for
+	// mining pool shares.
+	maxMiningPoolBlocks = 100000
+)
+
 // DataSourceLite specifies an interface for collecting data from the built-in
 // databases (i.e. SQLite, badger, ffldb)
 type DataSourceLite interface {
@@ -103,18 +160,56 @@ type DataSourceAux interface {
 	AddressTransactionDetails(addr string, count, skip int64,
 		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
 	AddressTotals(address string) (*apitypes.AddressTotals, error)
+	BlockFilter(blockHash string) (*apitypes.BlockFilter, error)
+	BlockFeeRates(hash string) (*dbtypes.BlockFeeRates, error)
+	FilterHeaders(start, end int64) ([]*apitypes.FilterHeader, error)
+	AddressBalanceAtHeight(address string, height int64) (*apitypes.AddressBalanceAt, error)
+	AddressBalanceAtTime(address string, t time.Time) (*apitypes.AddressBalanceAt, error)
+	SearchNullData(data []byte, prefix bool, limit, offset int) ([]*dbtypes.NullData, error)
+	RedeemScript(address string) (*dbtypes.RedeemScript, error)
+	TicketCommitments(address string, limit, offset int) ([]*dbtypes.TicketCommitment, int64, error)
+	StakePool(nameOrAddress string) *dbtypes.StakePool
+	StakePoolStats() ([]*dbtypes.StakePoolStats, error)
+	StakePoolChart(pool *dbtypes.StakePool) (*dbtypes.StakePoolChart, error)
 	VotesInBlock(hash string) (int16, error)
 	TxHistoryData(address string, addrChart dbtypes.HistoryChart,
 		chartGroupings dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error)
 	TicketPoolVisualization(interval dbtypes.TimeBasedGrouping) (
 		*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error)
 	AgendaVotes(agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error)
+	AgendaVotesByAddress(agendaID string, limit int) ([]*dbtypes.AgendaAddressVotes, error)
+	AgendaVotesCsv(agendaID string) ([][]string, error)
+	XpubAddresses(xpub string, gapLimit int) (used, unused []*dbtypes.XpubAddress, err error)
+	AddressesTransactions(addresses []string, N, offset int64) ([]*apitypes.XpubTx, int64, error)
+	AddressUTXO(address string) ([]apitypes.AddressTxnOutput, bool, error)
+	MempoolTxHistory(txHash string) (*dbtypes.MempoolTxHistory, error)
+	MempoolFeeSnapshots(from, to time.Time, limit int) ([]*dbtypes.MempoolFeeSnapshot, error)
+	MempoolFeeSnapshotAt(t time.Time) (*dbtypes.MempoolFeeSnapshot, error)
 	AddressTxIoCsv(address string) ([][]string, error)
 	Height() int64
 	AllAgendas() (map[string]dbtypes.MileStone, error)
 	GetTicketInfo(txid string) (*apitypes.TicketInfo, error)
 	ProposalVotes(proposalToken string) (*dbtypes.ProposalChartsData, error)
+	ProposalVotesTimeline(proposalToken string) (*dbtypes.ProposalVotesTimeline, error)
 	PowerlessTickets() (*apitypes.PowerlessTickets, error)
+	MissRates(grouping string) ([]*dbtypes.MissRate, error)
+	UnrevokedTickets(votingAddress, rewardAddress string, limit, offset int) ([]*dbtypes.UnrevokedTicket, int64, error)
+	TicketPoolForecast(blocks, binSize int64) (*dbtypes.TicketPoolForecast, error)
+	VersionHistory(intervals int64) (*dbtypes.VersionHistory, error)
+	TreasuryFlows() (*dbtypes.TreasuryFlows, error)
+	BlockMiner(hash string) (*dbtypes.BlockMiner, error)
+	MiningPoolStats(window int64) ([]*dbtypes.MiningPoolStats, error)
+	MiningPoolChart() (*dbtypes.MiningPoolChart, error)
+	UTXOAges() (*dbtypes.UTXOAgeDistribution, error)
+	AddressLabel(address string) *dbtypes.AddressLabel
+	AddressLabels() *dbtypes.AddressLabels
+	SearchAddressLabels(query string) []*dbtypes.AddressLabel
+}
+
+// FeeEstimator specifies an interface for estimating the fee rates required
+// for transactions to be mined within a number of blocks.
+type FeeEstimator interface {
+	FeeEstimates() *apitypes.FeeEstimates
 }
 
 // fnodata application context used by all route handlers
@@ -127,6 +222,9 @@ type appContext struct {
 	JSONIndent    string
 	xcBot         *exchanges.ExchangeBot
 	AgendaDB      *agendas.AgendaDB
+	ProposalsDB   *politeia.ProposalDB
+	FeeEstimator  FeeEstimator
+	VoteTracker   *agendas.VoteTracker
 	maxCSVAddrs   int
 	charts        *cache.ChartData
 }
@@ -141,6 +239,9 @@ type AppContextConfig struct {
 	JsonIndent        string
 	XcBot             *exchanges.ExchangeBot
 	AgendasDBInstance *agendas.AgendaDB
+	ProposalsDB       *politeia.ProposalDB
+	FeeEstimator      FeeEstimator
+	VoteTracker       *agendas.VoteTracker
 	MaxAddrs          int
 	Charts            *cache.ChartData
 }
@@ -164,6 +265,9 @@ func NewContext(cfg *AppContextConfig) *appContext {
 		AuxDataSource: cfg.DBSource,
 		xcBot:         cfg.XcBot,
 		AgendaDB:      cfg.AgendasDBInstance,
+		ProposalsDB:   cfg.ProposalsDB,
+		FeeEstimator:  cfg.FeeEstimator,
+		VoteTracker:   cfg.VoteTracker,
 		Status:        apitypes.NewStatus(uint32(nodeHeight), conns, APIVersion, appver.Version(), cfg.Params.Name),
 		JSONIndent:    cfg.JsonIndent,
 		maxCSVAddrs:   cfg.MaxAddrs,
@@ -348,6 +452,17 @@ func (c *appContext) coinSupply(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, supply, c.getIndentQuery(r))
 }
 
+func (c *appContext) getSupplyBreakdown(w http.ResponseWriter, r *http.Request) {
+	supply := c.charts.SupplyBreakdown()
+	if supply == nil {
+		apiLog.Error("Unable to get supply breakdown.")
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, supply, c.getIndentQuery(r))
+}
+
 func (c *appContext) currentHeight(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	if _, err := io.WriteString(w, strconv.Itoa(int(c.Status.Height()))); err != nil {
@@ -409,6 +524,18 @@ func (c *appContext) getBlockSummary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Add the fee rate statistics of the block to a copy of the summary, which
+	// may be cached.
+	feeRates, err := c.AuxDataSource.BlockFeeRates(hash)
+	if err != nil && err != sql.ErrNoRows {
+		apiLog.Warnf("Unable to get block %s fee rates: %v", hash, err)
+	}
+	if feeRates != nil {
+		summary := *blockSummary
+		summary.FeeRates = feeRates
+		blockSummary = &summary
+	}
+
 	writeJSON(w, blockSummary, c.getIndentQuery(r))
 }
 
@@ -498,6 +625,70 @@ func (c *appContext) getBlockRaw(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, blockRaw, c.getIndentQuery(r))
 }
 
+// getBlockFilter processes a request for the committed filter of a block from
+// /block/{idx}/cfilter, /block/hash/{blockhash}/cfilter, or
+// /block/best/cfilter.
+func (c *appContext) getBlockFilter(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	filter, err := c.AuxDataSource.BlockFilter(hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockFilter: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get block %s filter: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, filter, c.getIndentQuery(r))
+}
+
+// getFilterHeaders processes a request for the committed filter headers of a
+// range of main chain blocks from /block/range/{idx0}/{idx}/cfheaders.
+func (c *appContext) getFilterHeaders(w http.ResponseWriter, r *http.Request) {
+	idx0 := m.GetBlockIndex0Ctx(r)
+	if idx0 < 0 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	idx := m.GetBlockIndexCtx(r)
+	if idx < 0 || idx < idx0 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if idx-idx0 >= maxFilterHeaders {
+		http.Error(w, fmt.Sprintf("too many blocks (max %d)", maxFilterHeaders),
+			http.StatusBadRequest)
+		return
+	}
+
+	headers, err := c.AuxDataSource.FilterHeaders(int64(idx0), int64(idx))
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("FilterHeaders: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get filter headers: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, headers, c.getIndentQuery(r))
+}
+
 func (c *appContext) getBlockHeaderRaw(w http.ResponseWriter, r *http.Request) {
 	hash, err := c.getBlockHashCtx(r)
 	if err != nil {
@@ -635,6 +826,70 @@ func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, tx, c.getIndentQuery(r))
 }
 
+// getTxMerkleProof processes a request for a proof of the inclusion of a mined
+// transaction in its block from /tx/{txid}/merkleproof.
+func (c *appContext) getTxMerkleProof(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	tx := c.BlockData.GetRawTransaction(txid)
+	if tx == nil {
+		apiLog.Errorf("Unable to get transaction %s", txid)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if tx.Block == nil || tx.Block.BlockHash == "" {
+		http.Error(w, "transaction is not mined", http.StatusNotFound)
+		return
+	}
+
+	msgBlock, err := c.BlockData.GetBlockByHash(tx.Block.BlockHash)
+	if err != nil {
+		apiLog.Errorf("Unable to get block %s: %v", tx.Block.BlockHash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	proof, err := txhelpers.BlockTxMerkleProof(msgBlock, txid)
+	if err != nil {
+		apiLog.Errorf("Unable to build merkle proof for %s: %v", txid, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	var header strings.Builder
+	err = msgBlock.Header.Serialize(hex.NewEncoder(&header))
+	if err != nil {
+		apiLog.Errorf("Unable to serialize block header %s: %v", tx.Block.BlockHash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	tree := "regular"
+	if proof.Tree == wire.TxTreeStake {
+		tree = "stake"
+	}
+	path := make([]string, 0, len(proof.Path))
+	for i := range proof.Path {
+		path = append(path, proof.Path[i].String())
+	}
+
+	writeJSON(w, &apitypes.TxMerkleProof{
+		TxID:        txid.String(),
+		BlockHash:   msgBlock.BlockHash().String(),
+		BlockHeight: msgBlock.Header.Height,
+		Header:      header.String(),
+		Tree:        tree,
+		MerkleRoot:  proof.Root.String(),
+		Leaf:        proof.Leaf.String(),
+		Index:       proof.Index,
+		Path:        path,
+	}, c.getIndentQuery(r))
+}
+
 func (c *appContext) getTransactionHex(w http.ResponseWriter, r *http.Request) {
 	txid, err := m.GetTxIDCtx(r)
 	if err != nil {
@@ -948,6 +1203,295 @@ func (c *appContext) getPowerlessTickets(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, tickets, c.getIndentQuery(r))
 }
 
+// getMissRates processes a request for the number of votes and missed votes,
+// and the miss rate, in each ticket price window or time interval from
+// /stake/misses/{chartgrouping}, where the grouping is window, day, week,
+// month, or year.
+func (c *appContext) getMissRates(w http.ResponseWriter, r *http.Request) {
+	grouping := m.GetChartGroupingCtx(r)
+	if grouping != "window" {
+		interval := dbtypes.TimeGroupingFromStr(grouping)
+		if interval == dbtypes.AllGrouping || interval == dbtypes.UnknownGrouping {
+			http.Error(w, fmt.Sprintf("invalid grouping %q", grouping),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	rates, err := c.AuxDataSource.MissRates(grouping)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MissRates: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("MissRates error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if rates == nil {
+		rates = []*dbtypes.MissRate{}
+	}
+	writeJSON(w, rates, c.getIndentQuery(r))
+}
+
+// getUnrevokedTickets processes a request for the missed and expired tickets
+// that are not yet revoked from
+// /stake/unrevoked?voting=A&reward=B&to=N&from=M, where the optional voting
+// and reward addresses filter the tickets, N is the number of tickets, and M
+// is the number to skip.
+func (c *appContext) getUnrevokedTickets(w http.ResponseWriter, r *http.Request) {
+	votingAddress := r.URL.Query().Get("voting")
+	rewardAddress := r.URL.Query().Get("reward")
+	for _, address := range []string{votingAddress, rewardAddress} {
+		if address == "" {
+			continue
+		}
+		if _, _, addrErr := txhelpers.AddressValidation(address, c.Params); addrErr != nil {
+			apiLog.Debugf("Error validating address %s: %v", address, addrErr)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	count := m.GetCountCtx(r)
+	skip := m.GetOffsetCtx(r)
+	if count <= 0 {
+		count = 20
+	} else if count > maxUnrevokedTickets {
+		count = maxUnrevokedTickets
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	tickets, total, err := c.AuxDataSource.UnrevokedTickets(votingAddress,
+		rewardAddress, count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("UnrevokedTickets: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("UnrevokedTickets error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if tickets == nil {
+		tickets = []*dbtypes.UnrevokedTicket{}
+	}
+
+	writeJSON(w, &apitypes.UnrevokedTickets{
+		VotingAddress: votingAddress,
+		RewardAddress: rewardAddress,
+		Total:         total,
+		Tickets:       tickets,
+	}, c.getIndentQuery(r))
+}
+
+// getTicketPoolForecast processes a request for the projected ticket pool
+// from /stake/pool/forecast?blocks=N for each of the next N blocks, or from
+// /stake/pool/forecast?days=D for each of the next D days. By default, the
+// forecast is for each block of the next day.
+func (c *appContext) getTicketPoolForecast(w http.ResponseWriter, r *http.Request) {
+	blocksPerDay := int64(24 * time.Hour / c.Params.TargetTimePerBlock)
+	parseCount := func(param string) (int64, bool) {
+		str := r.URL.Query().Get(param)
+		if str == "" {
+			return 0, true
+		}
+		n, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("invalid %s", param), http.StatusBadRequest)
+			return 0, false
+		}
+		return n, true
+	}
+	blocks, ok := parseCount("blocks")
+	if !ok {
+		return
+	}
+	days, ok := parseCount("days")
+	if !ok {
+		return
+	}
+
+	binSize := int64(1)
+	switch {
+	case days > 0:
+		// Limit days so that the block count cannot overflow. The data source
+		// limits the forecast to the lifetime of a ticket in any case.
+		lifetimeDays := (int64(c.Params.TicketMaturity) +
+			int64(c.Params.TicketExpiry)) / blocksPerDay
+		if days > lifetimeDays+1 {
+			days = lifetimeDays + 1
+		}
+		blocks, binSize = days*blocksPerDay, blocksPerDay
+	case blocks == 0:
+		blocks = blocksPerDay
+	}
+
+	forecast, err := c.AuxDataSource.TicketPoolForecast(blocks, binSize)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketPoolForecast: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TicketPoolForecast error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, forecast, c.getIndentQuery(r))
+}
+
+// getVersionHistory processes a request for the adoption of block, stake, and
+// vote versions in each stake version interval from /stake/versions, or in the
+// last N intervals from /stake/versions?intervals=N.
+func (c *appContext) getVersionHistory(w http.ResponseWriter, r *http.Request) {
+	var intervals int64
+	if str := r.URL.Query().Get("intervals"); str != "" {
+		var err error
+		intervals, err = strconv.ParseInt(str, 10, 64)
+		if err != nil || intervals <= 0 {
+			http.Error(w, "invalid intervals", http.StatusBadRequest)
+			return
+		}
+	}
+
+	history, err := c.AuxDataSource.VersionHistory(intervals)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("VersionHistory: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("VersionHistory error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, history, c.getIndentQuery(r))
+}
+
+// getTreasuryFlows serves the inflows and spends of the project fund and
+// their monthly aggregates.
+func (c *appContext) getTreasuryFlows(w http.ResponseWriter, r *http.Request) {
+	flows, err := c.AuxDataSource.TreasuryFlows()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TreasuryFlows: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TreasuryFlows error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, flows, c.getIndentQuery(r))
+}
+
+// getBlockMiner processes a request for the coinbase text, payout addresses
+// and mining pool of a block.
+func (c *appContext) getBlockMiner(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	miner, err := c.AuxDataSource.BlockMiner(hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockMiner: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get block %s miner: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, miner, c.getIndentQuery(r))
+}
+
+// getMiningPools serves the number of blocks mined and the share of each
+// mining pool in the most recent blocks, given by the blocks query parameter.
+func (c *appContext) getMiningPools(w http.ResponseWriter, r *http.Request) {
+	blocks := int64(defaultMiningPoolBlocks)
+	if str := r.URL.Query().Get("blocks"); str != "" {
+		var err error
+		blocks, err = strconv.ParseInt(str, 10, 64)
+		if err != nil || blocks <= 0 || blocks > maxMiningPoolBlocks {
+			http.Error(w, "invalid blocks", http.StatusBadRequest)
+			return
+		}
+	}
+
+	stats, err := c.AuxDataSource.MiningPoolStats(blocks)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MiningPoolStats: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("MiningPoolStats error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, stats, c.getIndentQuery(r))
+}
+
+// getMiningPoolChart serves the number of blocks mined by each mining pool in
+// each ticket price window.
+func (c *appContext) getMiningPoolChart(w http.ResponseWriter, r *http.Request) {
+	chart, err := c.AuxDataSource.MiningPoolChart()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MiningPoolChart: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("MiningPoolChart error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, chart, c.getIndentQuery(r))
+}
+
+// getUTXOAges serves the daily history of the value and number of the unspent
+// transaction outputs in each age band.
+func (c *appContext) getUTXOAges(w http.ResponseWriter, r *http.Request) {
+	ages, err := c.AuxDataSource.UTXOAges()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("UTXOAges: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("UTXOAges error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, ages, c.getIndentQuery(r))
+}
+
+// getAddressLabels serves the known address labels registry, filtered by the
+// optional search query parameter.
+func (c *appContext) getAddressLabels(w http.ResponseWriter, r *http.Request) {
+	labels := c.AuxDataSource.AddressLabels()
+	if search := strings.TrimSpace(r.URL.Query().Get("search")); search != "" {
+		labels = &dbtypes.AddressLabels{
+			Version: labels.Version,
+			Labels:  c.AuxDataSource.SearchAddressLabels(search),
+		}
+	}
+	writeJSON(w, labels, c.getIndentQuery(r))
+}
+
 func (c *appContext) getStakeDiffCurrent(w http.ResponseWriter, r *http.Request) {
 	stakeDiff := c.BlockData.GetStakeDiffEstimates()
 	if stakeDiff == nil {
@@ -1091,26 +1635,123 @@ func (c *appContext) getProposalChartData(w http.ResponseWriter, r *http.Request
 	writeJSON(w, votesData, c.getIndentQuery(r))
 }
 
-func (c *appContext) getBlockSize(w http.ResponseWriter, r *http.Request) {
-	idx, err := c.getBlockHeightCtx(r)
-	if err != nil {
-		http.Error(w, http.StatusText(422), 422)
-		return
+// getProposalTicketVotes serves the votes cast by tickets on a proposal. The
+// optional ticket URL query parameter selects the vote of a single ticket.
+func (c *appContext) getProposalTicketVotes(w http.ResponseWriter, r *http.Request) {
+	token := m.GetProposalTokenCtx(r)
+	ticket := r.URL.Query().Get("ticket")
+	if ticket != "" {
+		if _, err := chainhash.NewHashFromStr(ticket); err != nil {
+			http.Error(w, "invalid ticket hash", http.StatusBadRequest)
+			return
+		}
 	}
 
-	blockSize, err := c.BlockData.GetBlockSize(int(idx))
+	votes, err := c.ProposalsDB.ProposalTicketVotes(token, ticket)
 	if err != nil {
-		http.Error(w, http.StatusText(422), 422)
+		apiLog.Errorf("Unable to get ticket votes for proposal %s : %v", token, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
 		return
 	}
 
-	writeJSON(w, blockSize, "")
+	writeJSON(w, votes, c.getIndentQuery(r))
 }
 
-func (c *appContext) blockSubsidies(w http.ResponseWriter, r *http.Request) {
-	idx, err := c.getBlockHeightCtx(r)
-	if err != nil {
-		http.Error(w, http.StatusText(422), 422)
+// getProposalVotesTimeline serves the cumulative vote tallies of a proposal at
+// block resolution.
+func (c *appContext) getProposalVotesTimeline(w http.ResponseWriter, r *http.Request) {
+	token := m.GetProposalTokenCtx(r)
+	timeline, err := c.AuxDataSource.ProposalVotesTimeline(token)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("ProposalVotesTimeline: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get proposal votes timeline for token %s : %v", token, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+
+	writeJSON(w, timeline, c.getIndentQuery(r))
+}
+
+// getProposalArchive serves the proposal files archived from Politeia, which
+// remain available when Politeia is down.
+func (c *appContext) getProposalArchive(w http.ResponseWriter, r *http.Request) {
+	token := m.GetProposalTokenCtx(r)
+	archive, err := c.ProposalsDB.ProposalArchive(token)
+	if err != nil {
+		apiLog.Errorf("Unable to get the archive of proposal %s : %v", token, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+	if archive == nil {
+		http.Error(w, "proposal not archived", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, archive, c.getIndentQuery(r))
+}
+
+// searchProposals serves the proposals whose title, archived body, author, or
+// status match the full-text query in the q URL query parameter.
+func (c *appContext) searchProposals(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	count := m.GetCountCtx(r)
+	skip := m.GetOffsetCtx(r)
+	if count <= 0 {
+		count = 20
+	} else if count > maxProposalSearchResults {
+		count = maxProposalSearchResults
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	results, totalCount, err := c.ProposalsDB.SearchProposals(query, skip, count)
+	if err != nil {
+		apiLog.Errorf("SearchProposals error: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+
+	writeJSON(w, &pitypes.ProposalSearchResults{
+		Query:      query,
+		TotalCount: totalCount,
+		Results:    results,
+	}, c.getIndentQuery(r))
+}
+
+func (c *appContext) getBlockSize(w http.ResponseWriter, r *http.Request) {
+	idx, err := c.getBlockHeightCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	blockSize, err := c.BlockData.GetBlockSize(int(idx))
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, blockSize, "")
+}
+
+func (c *appContext) blockSubsidies(w http.ResponseWriter, r *http.Request) {
+	idx, err := c.getBlockHeightCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
 		return
 	}
 	hash, err := c.getBlockHashCtx(r)
@@ -1464,6 +2105,258 @@ func (c *appContext) addressTotals(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, totals, c.getIndentQuery(r))
 }
 
+// addressScript processes a request for the decoded redeem script of a P2SH
+// address from /address/{address}/script. The redeem script is only known
+// once the address has been spent from.
+func (c *appContext) addressScript(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params, 1)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	_, addrType, _ := txhelpers.AddressValidation(address, c.Params)
+	if addrType != txhelpers.AddressTypeP2SH {
+		http.Error(w, "not a P2SH address", http.StatusUnprocessableEntity)
+		return
+	}
+
+	rs, err := c.AuxDataSource.RedeemScript(address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("RedeemScript: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "no spends from this address", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("RedeemScript error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, rs, c.getIndentQuery(r))
+}
+
+// addressLabel processes a request for the label of a known address from
+// /address/{address}/label.
+func (c *appContext) addressLabel(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params, 1)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	label := c.AuxDataSource.AddressLabel(addresses[0])
+	if label == nil {
+		http.Error(w, "address is not labeled", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, label, c.getIndentQuery(r))
+}
+
+// addressTicketCommitments processes a request for the commitments of main
+// chain tickets to a reward address from
+// /address/{address}/commitments?to=N&from=M, where N is the number of
+// commitments and M is the number to skip.
+func (c *appContext) addressTicketCommitments(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params, 1)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	count := m.GetCountCtx(r)
+	skip := m.GetOffsetCtx(r)
+	if count <= 0 {
+		count = 20
+	} else if count > maxTicketCommitments {
+		count = maxTicketCommitments
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	commitments, total, err := c.AuxDataSource.TicketCommitments(address, count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketCommitments: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TicketCommitments error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if commitments == nil {
+		commitments = []*dbtypes.TicketCommitment{}
+	}
+
+	writeJSON(w, &apitypes.TicketCommitments{
+		Address:     address,
+		Total:       total,
+		Commitments: commitments,
+	}, c.getIndentQuery(r))
+}
+
+// getStakePools processes a request for the ticket counts and miss rates of
+// the configured and detected stake pools.
+func (c *appContext) getStakePools(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.AuxDataSource.StakePoolStats()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakePoolStats: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakePoolStats error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, stats, c.getIndentQuery(r))
+}
+
+// getStakePool processes a request for the ticket counts and miss rate of the
+// stake pool with the name or address in the URL path.
+func (c *appContext) getStakePool(w http.ResponseWriter, r *http.Request) {
+	pool := c.AuxDataSource.StakePool(m.GetStakePoolCtx(r))
+	if pool == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	stats, err := c.AuxDataSource.StakePoolStats()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakePoolStats: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakePoolStats error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	for _, ps := range stats {
+		if ps.Name == pool.Name {
+			writeJSON(w, ps, c.getIndentQuery(r))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// getStakePoolChart processes a request for the live tickets, votes, misses,
+// and miss rate of the stake pool with the name or address in the URL path, in
+// each ticket price window.
+func (c *appContext) getStakePoolChart(w http.ResponseWriter, r *http.Request) {
+	pool := c.AuxDataSource.StakePool(m.GetStakePoolCtx(r))
+	if pool == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	chart, err := c.AuxDataSource.StakePoolChart(pool)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakePoolChart: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakePoolChart error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, chart, c.getIndentQuery(r))
+}
+
+// addressBalanceAt processes a request for the balance of an address as of a
+// past block or time from /address/{address}/balance?height=H or ?time=T,
+// where T is a UNIX timestamp. A list of heights may be given as
+// ?heights=H1,H2,... to get the balance as of each of the blocks.
+func (c *appContext) addressBalanceAt(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params, 1)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	// balanceError writes the response for a failed balance query, returning
+	// false if err is nil.
+	balanceError := func(err error) bool {
+		switch {
+		case err == nil:
+			return false
+		case dbtypes.IsTimeoutErr(err):
+			apiLog.Errorf("AddressBalanceAt: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		case err == sql.ErrNoRows:
+			http.Error(w, "no mainchain block at the requested height", http.StatusNotFound)
+		case err == dbtypes.ErrAddressHistoryPruned:
+			http.Error(w, "address history before the requested block or time has been pruned", 422)
+		default:
+			log.Warnf("failed to get address balance (%s): %v", address, err)
+			http.Error(w, http.StatusText(422), 422)
+		}
+		return true
+	}
+
+	query := r.URL.Query()
+	if timeStr := query.Get("time"); timeStr != "" {
+		t, err := strconv.ParseInt(timeStr, 10, 64)
+		if err != nil || t < 0 {
+			http.Error(w, "invalid time", http.StatusBadRequest)
+			return
+		}
+		balance, err := c.AuxDataSource.AddressBalanceAtTime(address, time.Unix(t, 0))
+		if balanceError(err) {
+			return
+		}
+		writeJSON(w, balance, c.getIndentQuery(r))
+		return
+	}
+
+	var heightStrs []string
+	batch := query.Get("heights") != ""
+	if batch {
+		heightStrs = strings.Split(query.Get("heights"), ",")
+		if len(heightStrs) > maxBalanceHeights {
+			http.Error(w, fmt.Sprintf("too many heights (max %d)", maxBalanceHeights),
+				http.StatusBadRequest)
+			return
+		}
+	} else if heightStr := query.Get("height"); heightStr != "" {
+		heightStrs = []string{heightStr}
+	} else {
+		http.Error(w, "height, heights, or time required", http.StatusBadRequest)
+		return
+	}
+
+	balances := make([]*apitypes.AddressBalanceAt, 0, len(heightStrs))
+	for _, heightStr := range heightStrs {
+		height, err := strconv.ParseInt(strings.TrimSpace(heightStr), 10, 64)
+		if err != nil || height < 0 {
+			http.Error(w, "invalid height", http.StatusBadRequest)
+			return
+		}
+		balance, err := c.AuxDataSource.AddressBalanceAtHeight(address, height)
+		if balanceError(err) {
+			return
+		}
+		balances = append(balances, balance)
+	}
+
+	if !batch {
+		writeJSON(w, balances[0], c.getIndentQuery(r))
+		return
+	}
+	writeJSON(w, balances, c.getIndentQuery(r))
+}
+
 // Handler for address activity CSV file download.
 // /download/address/io/{address}?cr=[true|false]
 func (c *appContext) addressIoCsv(w http.ResponseWriter, r *http.Request) {
@@ -1713,6 +2606,398 @@ func (c *appContext) getAgendaData(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// getAgendaVotesByAddress processes a request for the agenda vote choice counts
+// grouped by voting address from /agenda/{agendaId}/addresses?limit=N.
+func (c *appContext) getAgendaVotesByAddress(w http.ResponseWriter, r *http.Request) {
+	agendaId := m.GetAgendaIdCtx(r)
+	if agendaId == "" {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	limit := defaultAgendaVoteAddresses
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		var err error
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxAgendaVoteAddresses {
+			limit = maxAgendaVoteAddresses
+		}
+	}
+
+	addrVotes, err := c.AuxDataSource.AgendaVotesByAddress(agendaId, limit)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AgendaVotesByAddress timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AgendaVotesByAddress error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, addrVotes, c.getIndentQuery(r))
+}
+
+// Handler for agenda per-block vote choice counts CSV file download.
+// /download/agenda/{agendaId}/votes?cr=[true|false]
+func (c *appContext) agendaVotesCsv(w http.ResponseWriter, r *http.Request) {
+	agendaId := m.GetAgendaIdCtx(r)
+	if agendaId == "" {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	rows, err := c.AuxDataSource.AgendaVotesCsv(agendaId)
+	if err != nil {
+		log.Errorf("Failed to fetch AgendaVotesCsv: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("agenda-votes-%s-%d-%s.csv", agendaId,
+		c.Status.Height(), strconv.FormatInt(time.Now().Unix(), 10))
+
+	// Check if ?cr=true was specified.
+	crlfParam := r.URL.Query().Get("cr")
+	useCRLF := crlfParam == "1" || strings.EqualFold(crlfParam, "true")
+
+	writeCSV(w, rows, filename, useCRLF)
+}
+
+// xpubAddresses discovers the addresses of the extended public key in the
+// request path, using the gap limit from the ?gap=N URL query. If the request
+// is invalid or the addresses cannot be retrieved, an error response is
+// written and ok is false.
+func (c *appContext) xpubAddresses(w http.ResponseWriter, r *http.Request) (xpub string,
+	gapLimit int, used, unused []*dbtypes.XpubAddress, ok bool) {
+	xpub = m.GetXpubCtx(r)
+	if _, err := txhelpers.DecodeXpub(xpub, c.Params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	gapLimit = defaultXpubGapLimit
+	if gapParam := r.URL.Query().Get("gap"); gapParam != "" {
+		var err error
+		gapLimit, err = strconv.Atoi(gapParam)
+		if err != nil || gapLimit <= 0 || gapLimit > maxXpubGapLimit {
+			http.Error(w, fmt.Sprintf("gap must be between 1 and %d",
+				maxXpubGapLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	used, unused, err := c.AuxDataSource.XpubAddresses(xpub, gapLimit)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("XpubAddresses timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("XpubAddresses error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	ok = true
+	return
+}
+
+// getXpubBalance processes a request for the balance of the used addresses of
+// an extended public key from /xpub/{xpub}.
+func (c *appContext) getXpubBalance(w http.ResponseWriter, r *http.Request) {
+	xpub, gapLimit, used, _, ok := c.xpubAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	balance := &apitypes.XpubBalance{
+		Xpub:         xpub,
+		BlockHeight:  c.AuxDataSource.Height(),
+		GapLimit:     gapLimit,
+		NumUsedAddrs: len(used),
+	}
+	var atomsSpent, atomsUnspent int64
+	for _, xa := range used {
+		balance.NumSpent += xa.Balance.NumSpent
+		balance.NumUnspent += xa.Balance.NumUnspent
+		atomsSpent += xa.Balance.TotalSpent
+		atomsUnspent += xa.Balance.TotalUnspent
+		if xa.Balance.HistoryTruncated() {
+			balance.HistoryTruncated = true
+		}
+	}
+	balance.CoinsSpent = fnoutil.Amount(atomsSpent).ToCoin()
+	balance.CoinsUnspent = fnoutil.Amount(atomsUnspent).ToCoin()
+
+	writeJSON(w, balance, c.getIndentQuery(r))
+}
+
+// getXpubAddresses processes a request for the used and unused addresses of an
+// extended public key from /xpub/{xpub}/addresses.
+func (c *appContext) getXpubAddresses(w http.ResponseWriter, r *http.Request) {
+	xpub, gapLimit, used, unused, ok := c.xpubAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, &apitypes.XpubAddresses{
+		Xpub:     xpub,
+		GapLimit: gapLimit,
+		Used:     used,
+		Unused:   unused,
+	}, c.getIndentQuery(r))
+}
+
+// getXpubTransactions processes a request for the transactions involving the
+// addresses of an extended public key from /xpub/{xpub}/txs?to=N&from=M, where
+// N is the number of transactions and M is the number to skip.
+func (c *appContext) getXpubTransactions(w http.ResponseWriter, r *http.Request) {
+	xpub, _, used, _, ok := c.xpubAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	count := m.GetCountCtx(r)
+	skip := m.GetOffsetCtx(r)
+	if count <= 0 {
+		count = 20
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	addresses := make([]string, 0, len(used))
+	for _, xa := range used {
+		addresses = append(addresses, xa.Address)
+	}
+
+	txns, total, err := c.AuxDataSource.AddressesTransactions(addresses,
+		int64(count), int64(skip))
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressesTransactions timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AddressesTransactions error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, &apitypes.XpubTransactions{
+		Xpub:         xpub,
+		Total:        int(total),
+		Transactions: txns,
+	}, c.getIndentQuery(r))
+}
+
+// getXpubUTXOs processes a request for the unspent outputs paying to the
+// addresses of an extended public key from /xpub/{xpub}/utxos.
+func (c *appContext) getXpubUTXOs(w http.ResponseWriter, r *http.Request) {
+	_, _, used, _, ok := c.xpubAddresses(w, r)
+	if !ok {
+		return
+	}
+
+	utxos := []apitypes.AddressTxnOutput{}
+	for _, xa := range used {
+		if xa.Balance.NumUnspent == 0 {
+			continue
+		}
+		addrUTXOs, _, err := c.AuxDataSource.AddressUTXO(xa.Address)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("AddressUTXO timeout error: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("AddressUTXO error: %v", err)
+			http.Error(w, http.StatusText(422), 422)
+			return
+		}
+		utxos = append(utxos, addrUTXOs...)
+	}
+
+	writeJSON(w, utxos, c.getIndentQuery(r))
+}
+
+// getFeeEstimates processes a request for fee rate estimates from
+// /mempool/fees/estimate.
+func (c *appContext) getFeeEstimates(w http.ResponseWriter, r *http.Request) {
+	if c.FeeEstimator == nil {
+		http.Error(w, "Fee estimation unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, c.FeeEstimator.FeeEstimates(), c.getIndentQuery(r))
+}
+
+// getMempoolTxHistory processes a request for the mempool entry and exit times
+// of a transaction from /mempool/history/tx/{txid}.
+func (c *appContext) getMempoolTxHistory(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	history, err := c.AuxDataSource.MempoolTxHistory(txid.String())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MempoolTxHistory timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("MempoolTxHistory error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, history, c.getIndentQuery(r))
+}
+
+// getMempoolFeeHistory processes a request for mempool fee rate snapshots from
+// /mempool/history/fees. With the ?at=T query, the most recent snapshot taken
+// at or before UNIX time T is returned. Otherwise, the snapshots taken in the
+// time range given by the ?from and ?to queries are returned. The range
+// defaults to the last 24 hours.
+func (c *appContext) getMempoolFeeHistory(w http.ResponseWriter, r *http.Request) {
+	parseTime := func(param string, defaultTime time.Time) (time.Time, bool) {
+		str := r.URL.Query().Get(param)
+		if str == "" {
+			return defaultTime, true
+		}
+		t, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || t < 0 {
+			http.Error(w, fmt.Sprintf("invalid %s time", param), http.StatusBadRequest)
+			return time.Time{}, false
+		}
+		return time.Unix(t, 0), true
+	}
+
+	if r.URL.Query().Get("at") != "" {
+		at, ok := parseTime("at", time.Now())
+		if !ok {
+			return
+		}
+		snap, err := c.AuxDataSource.MempoolFeeSnapshotAt(at)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("MempoolFeeSnapshotAt timeout error: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err == sql.ErrNoRows {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("MempoolFeeSnapshotAt error: %v", err)
+			http.Error(w, http.StatusText(422), 422)
+			return
+		}
+		writeJSON(w, snap, c.getIndentQuery(r))
+		return
+	}
+
+	to, ok := parseTime("to", time.Now())
+	if !ok {
+		return
+	}
+	from, ok := parseTime("from", to.Add(-defaultMempoolFeeHistory))
+	if !ok {
+		return
+	}
+
+	snaps, err := c.AuxDataSource.MempoolFeeSnapshots(from, to, maxMempoolFeeSnapshots)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MempoolFeeSnapshots timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("MempoolFeeSnapshots error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, snaps, c.getIndentQuery(r))
+}
+
+// searchNullData processes a request for the main chain nulldata (OP_RETURN)
+// outputs with payloads matching one of the following queries:
+// ?prefix=HEX, for payloads beginning with the hex-encoded bytes,
+// ?hex=HEX, for payloads containing the hex-encoded bytes, or
+// ?text=STR, for payloads containing the UTF-8 string.
+// The results are paginated with ?to=N&from=M, where N is the number of outputs
+// and M is the number to skip.
+func (c *appContext) searchNullData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var data []byte
+	var prefix bool
+	var err error
+	switch {
+	case query.Get("prefix") != "":
+		prefix = true
+		data, err = hex.DecodeString(query.Get("prefix"))
+	case query.Get("hex") != "":
+		data, err = hex.DecodeString(query.Get("hex"))
+	case query.Get("text") != "":
+		data = []byte(query.Get("text"))
+	default:
+		http.Error(w, "one of prefix, hex, or text is required", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid hex data", http.StatusBadRequest)
+		return
+	}
+
+	count := m.GetCountCtx(r)
+	skip := m.GetOffsetCtx(r)
+	if count <= 0 {
+		count = 20
+	} else if count > maxNullDataResults {
+		count = maxNullDataResults
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	outputs, err := c.AuxDataSource.SearchNullData(data, prefix, count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("SearchNullData timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == dbtypes.ErrNullDataSubstringsUnindexed {
+		http.Error(w, "Substring searches are not available. Use the prefix search.",
+			http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("SearchNullData error: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if outputs == nil {
+		outputs = []*dbtypes.NullData{}
+	}
+
+	writeJSON(w, outputs, c.getIndentQuery(r))
+}
+
 func (c *appContext) getExchanges(w http.ResponseWriter, r *http.Request) {
 	if c.xcBot == nil {
 		http.Error(w, "Exchange monitoring disabled.", http.StatusServiceUnavailable)
@@ -1775,13 +3060,17 @@ func (c *appContext) getAgendasData(w http.ResponseWriter, _ *http.Request) {
 		agendaMilestone.StartTime = time.Unix(int64(val.StartTime), 0).UTC()
 		agendaMilestone.ExpireTime = time.Unix(int64(val.ExpireTime), 0).UTC()
 
-		data = append(data, apitypes.AgendasInfo{
+		info := apitypes.AgendasInfo{
 			Name:        val.ID,
 			Description: val.Description,
 			VoteVersion: val.VoteVersion,
 			MileStone:   &agendaMilestone,
 			Mask:        val.Mask,
-		})
+		}
+		if c.VoteTracker != nil {
+			info.Prediction = c.VoteTracker.Prediction(val.ID)
+		}
+		data = append(data, info)
 	}
 	writeJSON(w, data, "")
 }
@@ -100,13 +100,15 @@ type TxShort struct {
 	Vout     []Vout        `json:"vout"`
 }
 
-// AgendasInfo holds the high level details about an agenda.
+// AgendasInfo holds the high level details about an agenda. Prediction is the
+// projected outcome of an agenda being voted on.
 type AgendasInfo struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	*dbtypes.MileStone
-	VoteVersion uint32 `json:"voteversion"`
-	Mask        uint16 `json:"mask"`
+	VoteVersion uint32                    `json:"voteversion"`
+	Mask        uint16                    `json:"mask"`
+	Prediction  *dbtypes.AgendaPrediction `json:"prediction,omitempty"`
 }
 
 // AgendaAPIResponse holds two sets of AgendaVoteChoices charts data.
@@ -161,6 +163,40 @@ type BlockRaw struct {
 	Hex    string `json:"hex"`
 }
 
+// BlockFilter is the committed filter of a block, used by light wallets to
+// find relevant blocks. Filter is the hexadecimal encoded serialized filter.
+// Header commits to the filter and to the previous block's filter header, and
+// is omitted if it is not known.
+type BlockFilter struct {
+	BlockHash string `json:"blockhash"`
+	Height    uint32 `json:"height"`
+	Filter    string `json:"filter"`
+	Header    string `json:"header,omitempty"`
+}
+
+// FilterHeader is the committed filter header of a block.
+type FilterHeader struct {
+	BlockHash string `json:"blockhash"`
+	Height    uint32 `json:"height"`
+	Header    string `json:"header"`
+}
+
+// TxMerkleProof proves the inclusion of a transaction in a block. Header is
+// the hexadecimal encoded serialized block header, and Tree is "regular" or
+// "stake". Hashing Leaf at position Index with each of the Path hashes in turn
+// yields MerkleRoot, the header's merkle root or stake root for the tree.
+type TxMerkleProof struct {
+	TxID        string   `json:"txid"`
+	BlockHash   string   `json:"blockhash"`
+	BlockHeight uint32   `json:"blockheight"`
+	Header      string   `json:"header"`
+	Tree        string   `json:"tree"`
+	MerkleRoot  string   `json:"merkleroot"`
+	Leaf        string   `json:"leaf"`
+	Index       uint32   `json:"index"`
+	Path        []string `json:"path"`
+}
+
 // VoutMined appends a best block hash, number of confimations and if a
 // transaction is a coinbase to a transaction output
 type VoutMined struct {
@@ -316,6 +352,9 @@ type OutPoint struct {
 type Address struct {
 	Address      string            `json:"address"`
 	Transactions []*AddressTxShort `json:"address_transactions"`
+	// HistoryTruncated indicates that older transactions have been removed by
+	// archival pruning and are not listed.
+	HistoryTruncated bool `json:"history_truncated,omitempty"`
 }
 
 // AddressTxRaw is modeled from SearchRawTransactionsResult but with size in
@@ -353,6 +392,20 @@ type AddressTotals struct {
 	NumUnspent   int64   `json:"num_utxos"`
 	CoinsSpent   float64 `json:"fno_spent"`
 	CoinsUnspent float64 `json:"fno_unspent"`
+	// HistoryTruncated indicates that part of the history has been aggregated
+	// by archival pruning. The totals remain complete.
+	HistoryTruncated bool `json:"history_truncated,omitempty"`
+}
+
+// AddressBalanceAt is the balance of an address as of the end of a mainchain
+// block, or as of a time. BlockHeight is omitted for time queries.
+type AddressBalanceAt struct {
+	Address       string          `json:"address"`
+	BlockHeight   int64           `json:"blockheight,omitempty"`
+	Time          dbtypes.TimeDef `json:"time"`
+	CoinsReceived float64         `json:"fno_received"`
+	CoinsSent     float64         `json:"fno_sent"`
+	Balance       float64         `json:"fno_balance"`
 }
 
 // BlockDataWithTxType adds an array of TxRawWithTxType to
@@ -543,6 +596,8 @@ type BlockDataBasic struct {
 	TotalSent  *int64  `json:"total_sent,omitempty"`
 	// TicketPoolInfo may be nil for side chain blocks.
 	PoolInfo *TicketPoolInfo `json:"ticket_pool,omitempty"`
+	// FeeRates may be nil for blocks stored before fee rates were introduced.
+	FeeRates *dbtypes.BlockFeeRates `json:"fee_rates,omitempty"`
 }
 
 // NewBlockDataBasic constructs a *BlockDataBasic with pointer fields allocated.
@@ -671,6 +726,83 @@ type MempoolTicketDetails struct {
 // MempoolTicketDetails
 type TicketsDetails []*TicketDetails
 
+// XpubBalance represents the number and value of spent and unspent outputs
+// for the used addresses of an extended public key.
+type XpubBalance struct {
+	Xpub             string  `json:"xpub"`
+	BlockHeight      int64   `json:"blockheight"`
+	GapLimit         int     `json:"gap_limit"`
+	NumUsedAddrs     int     `json:"num_used_addresses"`
+	NumSpent         int64   `json:"num_stxos"`
+	NumUnspent       int64   `json:"num_utxos"`
+	CoinsSpent       float64 `json:"fno_spent"`
+	CoinsUnspent     float64 `json:"fno_unspent"`
+	HistoryTruncated bool    `json:"history_truncated,omitempty"`
+}
+
+// XpubAddresses lists the used addresses of an extended public key, and the
+// unused addresses within the gap limit of each branch.
+type XpubAddresses struct {
+	Xpub     string                 `json:"xpub"`
+	GapLimit int                    `json:"gap_limit"`
+	Used     []*dbtypes.XpubAddress `json:"used"`
+	Unused   []*dbtypes.XpubAddress `json:"unused"`
+}
+
+// XpubTx is a transaction involving the addresses of an extended public key.
+// Sent and Received are the totals debited from and credited to the addresses.
+type XpubTx struct {
+	TxID      string          `json:"txid"`
+	Time      dbtypes.TimeDef `json:"time"`
+	Sent      float64         `json:"sent"`
+	Received  float64         `json:"received"`
+	Addresses []string        `json:"addresses"`
+}
+
+// XpubTransactions is a page of the transactions involving the addresses of
+// an extended public key, most recent first.
+type XpubTransactions struct {
+	Xpub         string    `json:"xpub"`
+	Total        int       `json:"total"`
+	Transactions []*XpubTx `json:"transactions"`
+}
+
+// TicketCommitments is a page of the commitments of main chain tickets to a
+// reward address, most recent first.
+type TicketCommitments struct {
+	Address     string                      `json:"address"`
+	Total       int64                       `json:"total"`
+	Commitments []*dbtypes.TicketCommitment `json:"commitments"`
+}
+
+// UnrevokedTickets is a page of the missed and expired tickets that are not
+// yet revoked, most recently purchased first, optionally filtered by voting
+// address and reward address.
+type UnrevokedTickets struct {
+	VotingAddress string                     `json:"voting_address,omitempty"`
+	RewardAddress string                     `json:"reward_address,omitempty"`
+	Total         int64                      `json:"total"`
+	Tickets       []*dbtypes.UnrevokedTicket `json:"tickets"`
+}
+
+// FeeEstimate is the fee rate, in FNO/kB, at which a transaction is expected
+// to be mined within Blocks blocks, based on the observed confirmation times
+// of SampleSize recent transactions. FeeRate is 0 when there is insufficient
+// data for an estimate.
+type FeeEstimate struct {
+	Blocks     int     `json:"blocks"`
+	FeeRate    float64 `json:"fee_rate"`
+	SampleSize int     `json:"sample_size"`
+}
+
+// FeeEstimates models the fee rate estimates for regular and ticket purchase
+// transactions at block height Height.
+type FeeEstimates struct {
+	Height  int64         `json:"height"`
+	Regular []FeeEstimate `json:"regular"`
+	Tickets []FeeEstimate `json:"tickets"`
+}
+
 // TicketInfo combines spend and pool statuses and relevant block and spending
 // transaction IDs.
 type TicketInfo struct {
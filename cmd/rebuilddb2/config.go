@@ -67,6 +67,8 @@ type config struct {
 	ForceReindex           bool   `long:"reindex" short:"R" description:"Drop indexes prior to sync and recreate after sync, with insertion conflict checks disabled in absence of constraints."`
 	AddrSpendInfoOnline    bool   `short:"a" long:"addrspends-no-batch" description:"Continually update the address table spending transaction info during rebuild (instead of full table update at end).  SLOW if doing full rebuild!"`
 	TicketSpendInfoBatch   bool   `short:"T" long:"ticketspends-batch" description:"Batch update the tickets table spending transaction info after rebuild (instead of during the rebuild)."`
+	RebuildCFilters        bool   `long:"cfilters" description:"Rebuild the committed filters and filter headers of all main chain blocks in the DB, then exit."`
+	RebuildFeeRates        bool   `long:"feerates" description:"Compute the fee rate statistics of all main chain blocks in the DB, then exit."`
 
 	// RPC client options
 	FnodUser         string `long:"fnoduser" description:"Daemon RPC user name"`
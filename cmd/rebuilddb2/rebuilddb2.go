@@ -179,6 +179,14 @@ func mainCore() error {
 		return db.DeleteDuplicatesRecovery(nil)
 	}
 
+	if cfg.RebuildCFilters {
+		return rebuildCFilters(db, client)
+	}
+
+	if cfg.RebuildFeeRates {
+		return rebuildFeeRates(db, client)
+	}
+
 	// Ctrl-C to shut down.
 	// Nothing should be sent the quit channel.  It should only be closed.
 	quit := make(chan struct{})
@@ -444,6 +452,85 @@ func mainCore() error {
 	return err
 }
 
+// rebuildCFilters rebuilds the committed filters and filter header chain of
+// the main chain blocks in the DB, starting from the genesis block.
+func rebuildCFilters(db *fnopg.ChainDB, client *rpcclient.Client) error {
+	height, err := db.HeightDB()
+	if err != nil {
+		return fmt.Errorf("HeightDB failed: %v", err)
+	}
+
+	// Ctrl-C to stop.
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	defer signal.Stop(c)
+
+	log.Infof("Rebuilding committed filters for blocks 0 to %d...", height)
+	for ib := int64(0); ib <= height; ib++ {
+		select {
+		case <-c:
+			log.Infof("Filter rebuild cancelled at height %d.", ib)
+			return nil
+		default:
+		}
+
+		block, blockHash, err := rpcutils.GetBlock(ib, client)
+		if err != nil {
+			return fmt.Errorf("GetBlock failed (%s): %v", blockHash, err)
+		}
+		if err = db.StoreBlockFilter(block.MsgBlock()); err != nil {
+			return fmt.Errorf("StoreBlockFilter failed (%s): %v", blockHash, err)
+		}
+
+		if ib%rescanLogBlockChunk == 0 && ib > 0 {
+			log.Infof("Rebuilt filters through height %d.", ib)
+		}
+	}
+
+	log.Infof("Rebuilt committed filters for %d blocks.", height+1)
+	return nil
+}
+
+// rebuildFeeRates computes and stores the fee rate statistics of the main
+// chain blocks in the DB, starting from the genesis block.
+func rebuildFeeRates(db *fnopg.ChainDB, client *rpcclient.Client) error {
+	height, err := db.HeightDB()
+	if err != nil {
+		return fmt.Errorf("HeightDB failed: %v", err)
+	}
+
+	// Ctrl-C to stop.
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	defer signal.Stop(c)
+
+	log.Infof("Computing fee rates for blocks 0 to %d...", height)
+	for ib := int64(0); ib <= height; ib++ {
+		select {
+		case <-c:
+			log.Infof("Fee rate computation cancelled at height %d.", ib)
+			return nil
+		default:
+		}
+
+		block, blockHash, err := rpcutils.GetBlock(ib, client)
+		if err != nil {
+			return fmt.Errorf("GetBlock failed (%s): %v", blockHash, err)
+		}
+		if err = db.StoreBlockFeeRates(block.MsgBlock()); err != nil {
+			return fmt.Errorf("StoreBlockFeeRates failed (%s): %v", blockHash, err)
+		}
+
+		if ib%rescanLogBlockChunk == 0 && ib > 0 {
+			log.Infof("Computed fee rates through height %d.", ib)
+		}
+	}
+
+	log.Infof("Computed fee rates for %d blocks. Restart fnodata to reload "+
+		"its fee rate charts.", height+1)
+	return nil
+}
+
 func main() {
 	if err := mainCore(); err != nil {
 		log.Error(err)
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/url"
@@ -61,6 +62,8 @@ var (
 	defaultMempoolMinInterval = 2
 	defaultMempoolMaxInterval = 120
 	defaultMPTriggerTickets   = 1
+	defaultMPHistoryDays      = 30
+	defaultMPSnapInterval     = time.Minute
 
 	defaultDBFileName        = "fnodata.sqlt.db"
 	defaultAgendasDBFileName = "agendas.db"
@@ -83,6 +86,11 @@ var (
 	defaultTestnetLink = "https://testnet.fnodata.org/"
 
 	maxSyncStatusLimit = 5000
+
+	// minPruneHistory is the smallest number of recent blocks for which full
+	// history must be kept when archival pruning is enabled, so that chain
+	// reorganizations and block purges do not reach pruned data.
+	minPruneHistory = 4096
 )
 
 type config struct {
@@ -136,6 +144,10 @@ type config struct {
 	PGQueryTimeout time.Duration `short:"T" long:"pgtimeout" description:"Timeout (a time.Duration string) for most PostgreSQL queries used for user initiated queries."`
 	HidePGConfig   bool          `long:"hidepgconfig" description:"Blocks logging of the PostgreSQL db configuration on system start up."`
 	AddrCacheCap   int           `long:"addr-cache-cap" description:"Address cache capacity in bytes."`
+	PruneHistory   int64         `long:"prune-history" description:"Keep full vins, vouts, and addresses table history for only the N most recent blocks, aggregating older address history into per-address summaries. 0 disables pruning." env:"FNODATA_PRUNE_HISTORY"`
+	MempoolHistory bool          `long:"mempool-history" description:"Record the mempool entry and exit times of each transaction, and periodic fee rate histograms of mempool." env:"FNODATA_MEMPOOL_HISTORY"`
+	MPHistoryDays  int           `long:"mp-history-days" description:"Number of days of mempool history to keep with --mempool-history. 0 keeps all history." env:"FNODATA_MEMPOOL_HISTORY_DAYS"`
+	MPSnapInterval time.Duration `long:"mp-snap-interval" description:"Interval (a time.Duration string) between the mempool fee rate histograms recorded with --mempool-history." env:"FNODATA_MEMPOOL_SNAP_INTERVAL"`
 	DropIndexes    bool          `long:"drop-inds" short:"D" description:"Drop all table indexes and exit."`
 
 	NoDevPrefetch    bool `long:"no-dev-prefetch" description:"Disable automatic dev fund balance query on new blocks. When true, the query will still be run on demand, but not automatically after new blocks are connected." env:"FNODATA_DISABLE_DEV_PREFETCH"`
@@ -166,9 +178,34 @@ type config struct {
 	RateMaster        string `long:"ratemaster" description:"The address of a FNORates instance. Exchange monitoring will get all data from a FNORates subscription." env:"FNODATA_RATE_MASTER"`
 	RateCertificate   string `long:"ratecert" description:"File containing FNORates TLS certificate file." env:"FNODATA_RATE_MASTER"`
 
+	// Stake pools
+	StakePools []string `long:"stakepool" description:"A known stake pool (voting service provider) as name:address[,address...], where the addresses are the pool fee address and any voting addresses shared by the pool's tickets. Specify multiple times for multiple pools. Other pools are detected from their pool fee commitments."`
+
+	// Mining pools
+	MiningPools []string `long:"miningpool" description:"A known mining pool as name:tag[,tag...], where each tag is a payout address of the pool or text that the pool puts in its coinbase scripts. Specify multiple times for multiple pools."`
+
+	// Address labels
+	AddressLabelsFile string `long:"addresslabels" description:"A JSON file of labels for known addresses, such as exchange hot wallets and pool payout addresses. The file is reloaded on SIGUSR1." env:"FNODATA_ADDRESS_LABELS"`
+
+	// Treasury
+	TreasurySpends []string `long:"treasuryspend" description:"A spend of the project fund paying for a Politeia proposal as txid:token, where token is the proposal's censorship record token. Specify multiple times for multiple spends."`
+
 	// Links
 	MainnetLink string `long:"mainnet-link" description:"When fnodata is on testnet, this address will be used to direct a user to a fnodata on mainnet when appropriate." env:"FNODATA_MAINNET_LINK"`
 	TestnetLink string `long:"testnet-link" description:"When fnodata is on mainnet, this address will be used to direct a user to a fnodata on testnet when appropriate." env:"FNODATA_TESTNET_LINK"`
+
+	// stakePools is the parsed StakePools registry.
+	stakePools []*dbtypes.StakePool
+
+	// miningPools is the parsed MiningPools registry.
+	miningPools []*dbtypes.MiningPool
+
+	// addressLabels is the address labels registry loaded from
+	// AddressLabelsFile.
+	addressLabels *dbtypes.AddressLabels
+
+	// treasurySpends maps the txids of the TreasurySpends to proposal tokens.
+	treasurySpends map[string]string
 }
 
 var (
@@ -194,6 +231,8 @@ var (
 		MempoolMinInterval:  defaultMempoolMinInterval,
 		MempoolMaxInterval:  defaultMempoolMaxInterval,
 		MPTriggerTickets:    defaultMPTriggerTickets,
+		MPHistoryDays:       defaultMPHistoryDays,
+		MPSnapInterval:      defaultMPSnapInterval,
 		PGDBName:            defaultPGDBName,
 		PGUser:              defaultPGUser,
 		PGPass:              defaultPGPass,
@@ -586,6 +625,50 @@ func loadConfig() (*config, error) {
 		return nil, fmt.Errorf("purge-n-blocks must be non-negative")
 	}
 
+	// Validate archival pruning options.
+	if cfg.PruneHistory != 0 && cfg.PruneHistory < minPruneHistory {
+		return nil, fmt.Errorf("prune-history must be 0 (disabled) or at "+
+			"least %d", minPruneHistory)
+	}
+	if cfg.PruneHistory > 0 && int64(cfg.PurgeNBestBlocks) >= cfg.PruneHistory {
+		return nil, fmt.Errorf("purge-n-blocks may not reach pruned history")
+	}
+
+	// Validate mempool history options.
+	if cfg.MPHistoryDays < 0 {
+		return nil, fmt.Errorf("mp-history-days must be non-negative")
+	}
+	if cfg.MPSnapInterval <= 0 {
+		return nil, fmt.Errorf("mp-snap-interval must be positive")
+	}
+
+	// Parse the stake pool registry.
+	cfg.stakePools, err = parseStakePools(cfg.StakePools, activeChain)
+	if err != nil {
+		return loadConfigError(err)
+	}
+
+	// Parse the mining pool registry.
+	cfg.miningPools, err = parseMiningPools(cfg.MiningPools, activeChain)
+	if err != nil {
+		return loadConfigError(err)
+	}
+
+	// Load the address labels registry.
+	if cfg.AddressLabelsFile != "" {
+		cfg.AddressLabelsFile = cleanAndExpandPath(cfg.AddressLabelsFile)
+	}
+	cfg.addressLabels, err = loadAddressLabels(cfg.AddressLabelsFile, activeChain)
+	if err != nil {
+		return loadConfigError(err)
+	}
+
+	// Parse the treasury spend to proposal mapping.
+	cfg.treasurySpends, err = parseTreasurySpends(cfg.TreasurySpends)
+	if err != nil {
+		return loadConfigError(err)
+	}
+
 	// Set the host names and ports to the default if the user does not specify
 	// them.
 	cfg.FnodServ, err = normalizeNetworkAddress(cfg.FnodServ, defaultHost, activeNet.JSONRPCClientPort)
@@ -657,6 +740,117 @@ func loadConfig() (*config, error) {
 	return &cfg, nil
 }
 
+// parseStakePools parses stake pool specifications of the form
+// name:address[,address...]. Pool names and addresses must be unique, and the
+// addresses must be valid for the network.
+func parseStakePools(specs []string, params *chaincfg.Params) ([]*dbtypes.StakePool, error) {
+	names := make(map[string]bool, len(specs))
+	addresses := make(map[string]bool)
+	pools := make([]*dbtypes.StakePool, 0, len(specs))
+	for _, spec := range specs {
+		i := strings.LastIndex(spec, ":")
+		if i < 1 || i == len(spec)-1 {
+			return nil, fmt.Errorf("invalid stakepool %q, expected name:address", spec)
+		}
+		name := strings.TrimSpace(spec[:i])
+		if names[name] {
+			return nil, fmt.Errorf("duplicate stakepool name %q", name)
+		}
+		names[name] = true
+
+		pool := &dbtypes.StakePool{Name: name}
+		for _, address := range strings.Split(spec[i+1:], ",") {
+			address = strings.TrimSpace(address)
+			addr, err := fnoutil.DecodeAddress(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q for stakepool %q: %v",
+					address, name, err)
+			}
+			if !addr.IsForNet(params) {
+				return nil, fmt.Errorf("address %q for stakepool %q is not for %s",
+					address, name, params.Name)
+			}
+			if addresses[address] {
+				return nil, fmt.Errorf("address %q is in more than one stakepool", address)
+			}
+			addresses[address] = true
+			pool.Addresses = append(pool.Addresses, address)
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// parseMiningPools parses mining pool specifications of the form
+// name:tag[,tag...]. A tag that decodes as an address is a payout address of
+// the pool, and must be valid for the network. Other tags are coinbase text.
+// Pool names and addresses must be unique.
+func parseMiningPools(specs []string, params *chaincfg.Params) ([]*dbtypes.MiningPool, error) {
+	names := make(map[string]bool, len(specs))
+	addresses := make(map[string]bool)
+	pools := make([]*dbtypes.MiningPool, 0, len(specs))
+	for _, spec := range specs {
+		i := strings.Index(spec, ":")
+		if i < 1 || i == len(spec)-1 {
+			return nil, fmt.Errorf("invalid miningpool %q, expected name:tag", spec)
+		}
+		name := strings.TrimSpace(spec[:i])
+		if names[name] {
+			return nil, fmt.Errorf("duplicate miningpool name %q", name)
+		}
+		names[name] = true
+
+		pool := &dbtypes.MiningPool{Name: name}
+		for _, tag := range strings.Split(spec[i+1:], ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				return nil, fmt.Errorf("empty tag for miningpool %q", name)
+			}
+			addr, err := fnoutil.DecodeAddress(tag)
+			if err != nil {
+				pool.Tags = append(pool.Tags, tag)
+				continue
+			}
+			if !addr.IsForNet(params) {
+				return nil, fmt.Errorf("address %q for miningpool %q is not for %s",
+					tag, name, params.Name)
+			}
+			if addresses[tag] {
+				return nil, fmt.Errorf("address %q is in more than one miningpool", tag)
+			}
+			addresses[tag] = true
+			pool.Addresses = append(pool.Addresses, tag)
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// parseTreasurySpends parses project fund spend specifications of the form
+// txid:token, where token is the censorship record token of the Politeia
+// proposal paid by the spend. Each txid may only be mapped once.
+func parseTreasurySpends(specs []string) (map[string]string, error) {
+	spends := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		i := strings.Index(spec, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid treasuryspend %q, expected txid:token", spec)
+		}
+		txid, token := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+		if b, err := hex.DecodeString(txid); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("invalid txid %q for treasuryspend", txid)
+		}
+		if b, err := hex.DecodeString(token); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("invalid proposal token %q for treasuryspend %s", token, txid)
+		}
+		if _, found := spends[txid]; found {
+			return nil, fmt.Errorf("duplicate treasuryspend txid %s", txid)
+		}
+		spends[txid] = token
+	}
+	return spends, nil
+}
+
 // netName returns the name used when referring to a fonero network. TestNet
 // correctly returns "testnet", but not TestNet3. This function may be removed
 // after testnet2 is ancient history.
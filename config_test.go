@@ -5,8 +5,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnod/fnoutil"
 )
 
@@ -304,3 +306,95 @@ func TestNormalizeNetworkAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestParseStakePools(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	feeAddr, _ := fnoutil.NewAddressPubKeyHash(make([]byte, 20), params, 0)
+	fee := feeAddr.EncodeAddress()
+	voteAddr, _ := fnoutil.NewAddressScriptHashFromHash(make([]byte, 20), params)
+	vote := voteAddr.EncodeAddress()
+	testNetAddr, _ := fnoutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.TestNetParams, 0)
+
+	pools, err := parseStakePools([]string{"pool:" + fee + ", " + vote}, params)
+	if err != nil {
+		t.Fatalf("parseStakePools: %v", err)
+	}
+	if len(pools) != 1 || pools[0].Name != "pool" || len(pools[0].Addresses) != 2 ||
+		!pools[0].HasAddress(fee) || !pools[0].HasAddress(vote) {
+		t.Errorf("unexpected pools: %+v", pools)
+	}
+
+	bad := [][]string{
+		{"pool"},
+		{":" + fee},
+		{"pool:"},
+		{"pool:notanaddress"},
+		{"pool:" + testNetAddr.EncodeAddress()},
+		{"pool:" + fee, "pool:" + vote},
+		{"pool1:" + fee, "pool2:" + fee},
+	}
+	for _, specs := range bad {
+		if _, err = parseStakePools(specs, params); err == nil {
+			t.Errorf("expected an error for %v", specs)
+		}
+	}
+}
+
+func TestParseMiningPools(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	payAddr, _ := fnoutil.NewAddressPubKeyHash(make([]byte, 20), params, 0)
+	pay := payAddr.EncodeAddress()
+	testNetAddr, _ := fnoutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.TestNetParams, 0)
+
+	pools, err := parseMiningPools([]string{"pool:/Pool/, " + pay, "other:Other:Mining"}, params)
+	if err != nil {
+		t.Fatalf("parseMiningPools: %v", err)
+	}
+	if len(pools) != 2 || pools[0].Name != "pool" ||
+		!reflect.DeepEqual(pools[0].Tags, []string{"/Pool/"}) ||
+		!reflect.DeepEqual(pools[0].Addresses, []string{pay}) ||
+		pools[1].Name != "other" || !reflect.DeepEqual(pools[1].Tags, []string{"Other:Mining"}) {
+		t.Errorf("unexpected pools: %+v", pools)
+	}
+
+	bad := [][]string{
+		{"pool"},
+		{":tag"},
+		{"pool:"},
+		{"pool:tag,"},
+		{"pool:" + testNetAddr.EncodeAddress()},
+		{"pool:a", "pool:b"},
+		{"pool1:" + pay, "pool2:" + pay},
+	}
+	for _, specs := range bad {
+		if _, err = parseMiningPools(specs, params); err == nil {
+			t.Errorf("expected an error for %v", specs)
+		}
+	}
+}
+
+func TestParseTreasurySpends(t *testing.T) {
+	txid := "0aaab331075d08cb03333d5a1bef04b99a708dcbfebc8f8c94040ceb1676e684"
+	token := "522652954ea7998f3fca95b9c4ca8907820eb785877dcf7fba92307131818c75"
+
+	spends, err := parseTreasurySpends([]string{txid + ": " + token})
+	if err != nil {
+		t.Fatalf("parseTreasurySpends: %v", err)
+	}
+	if len(spends) != 1 || spends[txid] != token {
+		t.Errorf("unexpected spends: %v", spends)
+	}
+
+	bad := [][]string{
+		{txid},
+		{txid + ":"},
+		{"abcd:" + token},
+		{txid + ":notatoken"},
+		{txid + ":" + token, txid + ":" + token},
+	}
+	for _, specs := range bad {
+		if _, err = parseTreasurySpends(specs); err == nil {
+			t.Errorf("expected an error for %v", specs)
+		}
+	}
+}
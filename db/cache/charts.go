@@ -20,18 +20,28 @@ import (
 
 // Keys for specifying chart data type.
 const (
-	BlockSize       = "block-size"
-	BlockChainSize  = "blockchain-size"
-	ChainWork       = "chainwork"
-	CoinSupply      = "coin-supply"
-	DurationBTW     = "duration-btw-blocks"
-	HashRate        = "hashrate"
-	POWDifficulty   = "pow-difficulty"
-	TicketPrice     = "ticket-price"
-	TxCount         = "tx-count"
-	Fees            = "fees"
-	TicketPoolSize  = "ticket-pool-size"
-	TicketPoolValue = "ticket-pool-value"
+	BlockSize         = "block-size"
+	BlockChainSize    = "blockchain-size"
+	ChainWork         = "chainwork"
+	CoinSupply        = "coin-supply"
+	DurationBTW       = "duration-btw-blocks"
+	HashRate          = "hashrate"
+	POWDifficulty     = "pow-difficulty"
+	TicketPrice       = "ticket-price"
+	TxCount           = "tx-count"
+	Fees              = "fees"
+	TicketPoolSize    = "ticket-pool-size"
+	TicketPoolValue   = "ticket-pool-value"
+	NullData          = "nulldata"
+	MissedVotes       = "missed-votes"
+	Supply            = "supply"
+	FeeRates          = "fee-rates"
+	TicketFeeRates    = "ticket-fee-rates"
+	ActiveAddresses   = "active-addresses"
+	NewAddresses      = "new-addresses"
+	UTXOSet           = "utxo-set"
+	AvgTxValue        = "avg-tx-value"
+	CoinDaysDestroyed = "coin-days-destroyed"
 )
 
 // ZoomLevel specifies the granularity of data.
@@ -197,16 +207,43 @@ func newChartUints(size int) ChartUints {
 // cacheID is updated anytime new data is added and validated (see
 // Lengthen), typically once per bin duration.
 type zoomSet struct {
-	cacheID   uint64
-	Height    ChartUints
-	Time      ChartUints
-	PoolSize  ChartUints
-	PoolValue ChartFloats
-	BlockSize ChartUints
-	TxCount   ChartUints
-	NewAtoms  ChartUints
-	Chainwork ChartUints
-	Fees      ChartUints
+	cacheID     uint64
+	Height      ChartUints
+	Time        ChartUints
+	PoolSize    ChartUints
+	PoolValue   ChartFloats
+	BlockSize   ChartUints
+	TxCount     ChartUints
+	NewAtoms    ChartUints
+	Chainwork   ChartUints
+	Fees        ChartUints
+	NullData    ChartUints
+	MissedVotes ChartUints
+	Burned      ChartUints
+	DevFundIn   ChartUints
+	DevFundOut  ChartUints
+	// FeeRates and TicketFeeRates are the fee rate statistics of the regular
+	// transactions and the ticket purchases.
+	FeeRates       feeRateSet
+	TicketFeeRates feeRateSet
+	// ActiveAddresses is the number of distinct addresses funded or spent in
+	// the block or day. DayActiveAddresses, only in the block data, counts the
+	// addresses of a block that were not already active earlier in the same
+	// UTC day, so that its daily sums are the daily ActiveAddresses.
+	ActiveAddresses    ChartUints
+	DayActiveAddresses ChartUints
+	NewAddresses       ChartUints
+	// UTXOCount and UTXOValue are the size and value of the unspent output set
+	// after each block, and are not binned by day.
+	UTXOCount ChartUints
+	UTXOValue ChartUints
+	// TxValue is the total output value of the regular, non-coinbase
+	// transactions, RegularTxCount of them.
+	TxValue        ChartUints
+	RegularTxCount ChartUints
+	// CoinDaysDestroyed is the sum over the outputs spent of their value in
+	// coins times the number of days since they were created.
+	CoinDaysDestroyed ChartFloats
 }
 
 // Snip truncates the zoomSet to a provided length.
@@ -223,20 +260,50 @@ func (set *zoomSet) Snip(length int) {
 	set.NewAtoms = set.NewAtoms.snip(length)
 	set.Chainwork = set.Chainwork.snip(length)
 	set.Fees = set.Fees.snip(length)
+	set.NullData = set.NullData.snip(length)
+	set.MissedVotes = set.MissedVotes.snip(length)
+	set.Burned = set.Burned.snip(length)
+	set.DevFundIn = set.DevFundIn.snip(length)
+	set.DevFundOut = set.DevFundOut.snip(length)
+	set.FeeRates.snip(length)
+	set.TicketFeeRates.snip(length)
+	set.ActiveAddresses = set.ActiveAddresses.snip(length)
+	set.DayActiveAddresses = set.DayActiveAddresses.snip(length)
+	set.NewAddresses = set.NewAddresses.snip(length)
+	set.UTXOCount = set.UTXOCount.snip(length)
+	set.UTXOValue = set.UTXOValue.snip(length)
+	set.TxValue = set.TxValue.snip(length)
+	set.RegularTxCount = set.RegularTxCount.snip(length)
+	set.CoinDaysDestroyed = set.CoinDaysDestroyed.snip(length)
 }
 
 // Constructor for a sized zoomSet for blocks, which has has no Height slice
 // since the height is implicit for block-binned data.
 func newBlockSet(size int) *zoomSet {
 	return &zoomSet{
-		Time:      newChartUints(size),
-		PoolSize:  newChartUints(size),
-		PoolValue: newChartFloats(size),
-		BlockSize: newChartUints(size),
-		TxCount:   newChartUints(size),
-		NewAtoms:  newChartUints(size),
-		Chainwork: newChartUints(size),
-		Fees:      newChartUints(size),
+		Time:               newChartUints(size),
+		PoolSize:           newChartUints(size),
+		PoolValue:          newChartFloats(size),
+		BlockSize:          newChartUints(size),
+		TxCount:            newChartUints(size),
+		NewAtoms:           newChartUints(size),
+		Chainwork:          newChartUints(size),
+		Fees:               newChartUints(size),
+		NullData:           newChartUints(size),
+		MissedVotes:        newChartUints(size),
+		Burned:             newChartUints(size),
+		DevFundIn:          newChartUints(size),
+		DevFundOut:         newChartUints(size),
+		FeeRates:           newFeeRateSet(size),
+		TicketFeeRates:     newFeeRateSet(size),
+		ActiveAddresses:    newChartUints(size),
+		DayActiveAddresses: newChartUints(size),
+		NewAddresses:       newChartUints(size),
+		UTXOCount:          newChartUints(size),
+		UTXOValue:          newChartUints(size),
+		TxValue:            newChartUints(size),
+		RegularTxCount:     newChartUints(size),
+		CoinDaysDestroyed:  newChartFloats(size),
 	}
 }
 
@@ -275,22 +342,44 @@ func newWindowSet(size int) *windowSet {
 	}
 }
 
+// chartCacheVersion is the version of the ChartGobject cache file format. It
+// must be incremented whenever a data set is added to ChartGobject or the way a
+// data set is computed changes, so that older cache files are discarded.
+const chartCacheVersion = 2
+
 // ChartGobject is the storage object for saving to a gob file. ChartData itself
 // has a lot of extraneous fields, and also embeds sync.RWMutex, so is not
 // suitable for gobbing.
 type ChartGobject struct {
-	Height      ChartUints
-	Time        ChartUints
-	PoolSize    ChartUints
-	PoolValue   ChartFloats
-	BlockSize   ChartUints
-	TxCount     ChartUints
-	NewAtoms    ChartUints
-	Chainwork   ChartUints
-	Fees        ChartUints
-	WindowTime  ChartUints
-	PowDiff     ChartFloats
-	TicketPrice ChartUints
+	Version        uint32
+	Height         ChartUints
+	Time           ChartUints
+	PoolSize       ChartUints
+	PoolValue      ChartFloats
+	BlockSize      ChartUints
+	TxCount        ChartUints
+	NewAtoms       ChartUints
+	Chainwork      ChartUints
+	Fees           ChartUints
+	NullData       ChartUints
+	MissedVotes    ChartUints
+	Burned         ChartUints
+	DevFundIn      ChartUints
+	DevFundOut     ChartUints
+	WindowTime     ChartUints
+	PowDiff        ChartFloats
+	TicketPrice    ChartUints
+	FeeRates       feeRateSet
+	TicketFeeRates feeRateSet
+	// Address usage and UTXO set data.
+	ActiveAddresses    ChartUints
+	DayActiveAddresses ChartUints
+	NewAddresses       ChartUints
+	UTXOCount          ChartUints
+	UTXOValue          ChartUints
+	TxValue            ChartUints
+	RegularTxCount     ChartUints
+	CoinDaysDestroyed  ChartFloats
 }
 
 // The chart data is cached with the current cacheID of the zoomSet or windowSet.
@@ -325,11 +414,13 @@ type ChartData struct {
 	ctx          context.Context
 	genesis      uint64
 	DiffInterval int32
+	chainParams  *chaincfg.Params
 	Blocks       *zoomSet
 	Windows      *windowSet
 	Days         *zoomSet
 	cacheMtx     sync.RWMutex
 	cache        map[string]*cachedChart
+	supplyCache  *cachedSupply
 	updaters     []ChartUpdater
 }
 
@@ -407,6 +498,7 @@ func (charts *ChartData) Lengthen() error {
 	// If there is day or more worth of new data, append to the Days zoomSet by
 	// finding the first and last+1 blocks of each new day, and taking averages
 	// or sums of the blocks in the interval.
+	charts.appendDayFeeRates()
 	if end > start+aDay {
 		next := start + aDay
 		startIdx := -1
@@ -434,13 +526,29 @@ func (charts *ChartData) Lengthen() error {
 			days.NewAtoms = append(days.NewAtoms, blocks.NewAtoms.Sum(interval[0], interval[1]))
 			days.Chainwork = append(days.Chainwork, blocks.Chainwork[interval[1]])
 			days.Fees = append(days.Fees, blocks.Fees.Sum(interval[0], interval[1]))
+			days.NullData = append(days.NullData, blocks.NullData.Sum(interval[0], interval[1]))
+			days.MissedVotes = append(days.MissedVotes, blocks.MissedVotes.Sum(interval[0], interval[1]))
+			days.Burned = append(days.Burned, blocks.Burned.Sum(interval[0], interval[1]))
+			days.DevFundIn = append(days.DevFundIn, blocks.DevFundIn.Sum(interval[0], interval[1]))
+			days.DevFundOut = append(days.DevFundOut, blocks.DevFundOut.Sum(interval[0], interval[1]))
+			days.FeeRates.appendAvg(&blocks.FeeRates, interval[0], interval[1])
+			days.TicketFeeRates.appendAvg(&blocks.TicketFeeRates, interval[0], interval[1])
+			days.ActiveAddresses = append(days.ActiveAddresses, blocks.DayActiveAddresses.Sum(interval[0], interval[1]))
+			days.NewAddresses = append(days.NewAddresses, blocks.NewAddresses.Sum(interval[0], interval[1]))
+			days.TxValue = append(days.TxValue, blocks.TxValue.Sum(interval[0], interval[1]))
+			days.RegularTxCount = append(days.RegularTxCount, blocks.RegularTxCount.Sum(interval[0], interval[1]))
+			days.CoinDaysDestroyed = append(days.CoinDaysDestroyed, blocks.CoinDaysDestroyed.Sum(interval[0], interval[1]))
 			days.Height = append(days.Height, uint64(interval[1]-1))
 		}
 	}
 
 	// Check that all relevant datasets have been updated to the same length.
 	daysLen, err := ValidateLengths(days.PoolSize, days.PoolValue, days.BlockSize,
-		days.TxCount, days.NewAtoms, days.Chainwork, days.Fees, days.Height, days.Time)
+		days.TxCount, days.NewAtoms, days.Chainwork, days.Fees, days.NullData,
+		days.MissedVotes, days.Burned, days.DevFundIn, days.DevFundOut,
+		days.FeeRates.Count, days.TicketFeeRates.Count, days.ActiveAddresses,
+		days.NewAddresses, days.TxValue, days.RegularTxCount, days.CoinDaysDestroyed,
+		days.Height, days.Time)
 	if err != nil {
 		return fmt.Errorf("day zoom: %v", err)
 	} else if daysLen == 0 {
@@ -545,6 +653,15 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 		return err
 	}
 
+	// Cache files of other versions may be missing data sets or have data
+	// computed differently, so the whole cache is discarded and the charts are
+	// fetched anew.
+	if gobject.Version != chartCacheVersion {
+		log.Infof("Discarding charts cache file version %d (expected version %d).",
+			gobject.Version, chartCacheVersion)
+		return nil
+	}
+
 	charts.mtx.Lock()
 	charts.Blocks.Height = gobject.Height
 	charts.Blocks.Time = gobject.Time
@@ -555,6 +672,21 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 	charts.Blocks.NewAtoms = gobject.NewAtoms
 	charts.Blocks.Chainwork = gobject.Chainwork
 	charts.Blocks.Fees = gobject.Fees
+	charts.Blocks.NullData = gobject.NullData
+	charts.Blocks.MissedVotes = gobject.MissedVotes
+	charts.Blocks.Burned = gobject.Burned
+	charts.Blocks.DevFundIn = gobject.DevFundIn
+	charts.Blocks.DevFundOut = gobject.DevFundOut
+	charts.Blocks.FeeRates = gobject.FeeRates
+	charts.Blocks.TicketFeeRates = gobject.TicketFeeRates
+	charts.Blocks.ActiveAddresses = gobject.ActiveAddresses
+	charts.Blocks.DayActiveAddresses = gobject.DayActiveAddresses
+	charts.Blocks.NewAddresses = gobject.NewAddresses
+	charts.Blocks.UTXOCount = gobject.UTXOCount
+	charts.Blocks.UTXOValue = gobject.UTXOValue
+	charts.Blocks.TxValue = gobject.TxValue
+	charts.Blocks.RegularTxCount = gobject.RegularTxCount
+	charts.Blocks.CoinDaysDestroyed = gobject.CoinDaysDestroyed
 	charts.Windows.Time = gobject.WindowTime
 	charts.Windows.PowDiff = gobject.PowDiff
 	charts.Windows.TicketPrice = gobject.TicketPrice
@@ -563,9 +695,11 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 	err = charts.Lengthen()
 	if err != nil {
 		log.Warnf("problem detected during (*ChartData).Lengthen. clearing datasets: %v", err)
+		charts.mtx.Lock()
 		charts.Blocks.Snip(0)
 		charts.Windows.Snip(0)
 		charts.Days.Snip(0)
+		charts.mtx.Unlock()
 	}
 
 	return nil
@@ -603,18 +737,34 @@ func (charts *ChartData) TriggerUpdate(_ string, _ uint32) {
 
 func (charts *ChartData) gobject() *ChartGobject {
 	return &ChartGobject{
-		Height:      charts.Blocks.Height,
-		Time:        charts.Blocks.Time,
-		PoolSize:    charts.Blocks.PoolSize,
-		PoolValue:   charts.Blocks.PoolValue,
-		BlockSize:   charts.Blocks.BlockSize,
-		TxCount:     charts.Blocks.TxCount,
-		NewAtoms:    charts.Blocks.NewAtoms,
-		Chainwork:   charts.Blocks.Chainwork,
-		Fees:        charts.Blocks.Fees,
-		WindowTime:  charts.Windows.Time,
-		PowDiff:     charts.Windows.PowDiff,
-		TicketPrice: charts.Windows.TicketPrice,
+		Version:            chartCacheVersion,
+		Height:             charts.Blocks.Height,
+		Time:               charts.Blocks.Time,
+		PoolSize:           charts.Blocks.PoolSize,
+		PoolValue:          charts.Blocks.PoolValue,
+		BlockSize:          charts.Blocks.BlockSize,
+		TxCount:            charts.Blocks.TxCount,
+		NewAtoms:           charts.Blocks.NewAtoms,
+		Chainwork:          charts.Blocks.Chainwork,
+		Fees:               charts.Blocks.Fees,
+		NullData:           charts.Blocks.NullData,
+		MissedVotes:        charts.Blocks.MissedVotes,
+		Burned:             charts.Blocks.Burned,
+		DevFundIn:          charts.Blocks.DevFundIn,
+		DevFundOut:         charts.Blocks.DevFundOut,
+		WindowTime:         charts.Windows.Time,
+		PowDiff:            charts.Windows.PowDiff,
+		TicketPrice:        charts.Windows.TicketPrice,
+		FeeRates:           charts.Blocks.FeeRates,
+		TicketFeeRates:     charts.Blocks.TicketFeeRates,
+		ActiveAddresses:    charts.Blocks.ActiveAddresses,
+		DayActiveAddresses: charts.Blocks.DayActiveAddresses,
+		NewAddresses:       charts.Blocks.NewAddresses,
+		UTXOCount:          charts.Blocks.UTXOCount,
+		UTXOValue:          charts.Blocks.UTXOValue,
+		TxValue:            charts.Blocks.TxValue,
+		RegularTxCount:     charts.Blocks.RegularTxCount,
+		CoinDaysDestroyed:  charts.Blocks.CoinDaysDestroyed,
 	}
 }
 
@@ -659,6 +809,57 @@ func (charts *ChartData) FeesTip() int32 {
 	return int32(len(charts.Blocks.Fees)) - 1
 }
 
+// NullDataTip is the height of the NullData data.
+func (charts *ChartData) NullDataTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.NullData)) - 1
+}
+
+// MissedVotesTip is the height of the MissedVotes data.
+func (charts *ChartData) MissedVotesTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.MissedVotes)) - 1
+}
+
+// SupplyTip is the height of the Burned, DevFundIn and DevFundOut data.
+func (charts *ChartData) SupplyTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.Burned)) - 1
+}
+
+// FeeRatesTip is the height of the FeeRates and TicketFeeRates data.
+func (charts *ChartData) FeeRatesTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(charts.Blocks.FeeRates.Length()) - 1
+}
+
+// AddressUsageTip is the height of the ActiveAddresses, DayActiveAddresses and
+// NewAddresses data.
+func (charts *ChartData) AddressUsageTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.ActiveAddresses)) - 1
+}
+
+// UTXOSetTip is the height of the UTXOCount, UTXOValue, TxValue and
+// RegularTxCount data.
+func (charts *ChartData) UTXOSetTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.UTXOCount)) - 1
+}
+
+// CoinDaysDestroyedTip is the height of the CoinDaysDestroyed data.
+func (charts *ChartData) CoinDaysDestroyedTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.CoinDaysDestroyed)) - 1
+}
+
 // NewAtomsTip is the height of the NewAtoms data.
 func (charts *ChartData) NewAtomsTip() int32 {
 	charts.mtx.RLock()
@@ -737,6 +938,7 @@ func NewChartData(height uint32, genesis time.Time, chainParams *chaincfg.Params
 		ctx:          ctx,
 		genesis:      uint64(genesis.Unix()),
 		DiffInterval: int32(chainParams.StakeDiffWindowSize),
+		chainParams:  chainParams,
 		Blocks:       newBlockSet(size),
 		Windows:      newWindowSet(windows),
 		Days:         newDaySet(days),
@@ -794,18 +996,28 @@ func (charts *ChartData) cacheChart(chartID string, zoom ZoomLevel, data []byte)
 type ChartMaker func(charts *ChartData, zoom ZoomLevel) ([]byte, error)
 
 var chartMakers = map[string]ChartMaker{
-	BlockSize:       blockSizeChart,
-	BlockChainSize:  blockchainSizeChart,
-	ChainWork:       chainWorkChart,
-	CoinSupply:      coinSupplyChart,
-	DurationBTW:     durationBTWChart,
-	HashRate:        hashRateChart,
-	POWDifficulty:   powDifficultyChart,
-	TicketPrice:     ticketPriceChart,
-	TxCount:         txCountChart,
-	Fees:            feesChart,
-	TicketPoolSize:  ticketPoolSizeChart,
-	TicketPoolValue: poolValueChart,
+	BlockSize:         blockSizeChart,
+	BlockChainSize:    blockchainSizeChart,
+	ChainWork:         chainWorkChart,
+	CoinSupply:        coinSupplyChart,
+	DurationBTW:       durationBTWChart,
+	HashRate:          hashRateChart,
+	POWDifficulty:     powDifficultyChart,
+	TicketPrice:       ticketPriceChart,
+	TxCount:           txCountChart,
+	Fees:              feesChart,
+	TicketPoolSize:    ticketPoolSizeChart,
+	TicketPoolValue:   poolValueChart,
+	NullData:          nullDataChart,
+	MissedVotes:       missedVotesChart,
+	Supply:            supplyChart,
+	FeeRates:          feeRatesChart,
+	TicketFeeRates:    ticketFeeRatesChart,
+	ActiveAddresses:   activeAddressesChart,
+	NewAddresses:      newAddressesChart,
+	UTXOSet:           utxoSetChart,
+	AvgTxValue:        avgTxValueChart,
+	CoinDaysDestroyed: coinDaysDestroyedChart,
 }
 
 // Chart will return a JSON-encoded chartResponse of the provided type
@@ -1044,6 +1256,36 @@ func feesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
 	return nil, InvalidZoomErr
 }
 
+func nullDataChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time, charts.Blocks.NullData)
+	case DayZoom:
+		return charts.encode(charts.Days.Time, charts.Days.NullData)
+	}
+	return nil, InvalidZoomErr
+}
+
+func missedVotesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time, charts.Blocks.MissedVotes)
+	case DayZoom:
+		return charts.encode(charts.Days.Time, charts.Days.MissedVotes)
+	}
+	return nil, InvalidZoomErr
+}
+
+func coinDaysDestroyedChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time, charts.Blocks.CoinDaysDestroyed)
+	case DayZoom:
+		return charts.encode(charts.Days.Time, charts.Days.CoinDaysDestroyed)
+	}
+	return nil, InvalidZoomErr
+}
+
 func ticketPoolSizeChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
 	switch zoom {
 	case BlockZoom:
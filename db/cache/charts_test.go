@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -63,6 +64,18 @@ func TestChartsCache(t *testing.T) {
 	seedTimes := func() ChartUints {
 		return ChartUints{1, 2 + aDay, 3 + aDay, 4 + 2*aDay, 5 + 2*aDay, 6 + 3*aDay}
 	}
+	seedFeeRates := func() feeRateSet {
+		return feeRateSet{
+			Count:  seedUints(),
+			Min:    seedUints(),
+			P10:    seedUints(),
+			P25:    seedUints(),
+			Median: seedUints(),
+			P75:    seedUints(),
+			P90:    seedUints(),
+			Max:    seedUints(),
+		}
+	}
 	floatDaysAvg := ChartFloats{1.1, 2.75, 4.95}
 	uintDaysAvg := ChartUints{1, 2, 4}
 	uintDaysSum := ChartUints{1, 5, 9}
@@ -76,6 +89,21 @@ func TestChartsCache(t *testing.T) {
 	charts.Blocks.NewAtoms = seedUints()
 	charts.Blocks.Chainwork = seedUints()
 	charts.Blocks.Fees = seedUints()
+	charts.Blocks.NullData = seedUints()
+	charts.Blocks.MissedVotes = seedUints()
+	charts.Blocks.Burned = seedUints()
+	charts.Blocks.DevFundIn = seedUints()
+	charts.Blocks.DevFundOut = seedUints()
+	charts.Blocks.FeeRates = seedFeeRates()
+	charts.Blocks.TicketFeeRates = seedFeeRates()
+	charts.Blocks.ActiveAddresses = seedUints()
+	charts.Blocks.DayActiveAddresses = seedUints()
+	charts.Blocks.NewAddresses = seedUints()
+	charts.Blocks.UTXOCount = seedUints()
+	charts.Blocks.UTXOValue = seedUints()
+	charts.Blocks.TxValue = seedUints()
+	charts.Blocks.RegularTxCount = seedUints()
+	charts.Blocks.CoinDaysDestroyed = seedFloats()
 	charts.Windows.Time = ChartUints{0}
 	charts.Windows.PowDiff = ChartFloats{0}
 	charts.Windows.TicketPrice = ChartUints{0}
@@ -101,6 +129,30 @@ func TestChartsCache(t *testing.T) {
 		}
 	})
 
+	t.Run("Read_a_gob_dump_of_another_version", func(t *testing.T) {
+		path := filepath.Join(tempDir, "log4.gob")
+
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but found: %v", err)
+		}
+		err = gob.NewEncoder(file).Encode(&ChartGobject{
+			Version: chartCacheVersion + 1,
+			Height:  ChartUints{7},
+			Time:    ChartUints{7},
+		})
+		file.Close()
+		if err != nil {
+			t.Fatalf("expected no error but found: %v", err)
+		}
+
+		err = charts.readCacheFile(path)
+		if err != nil {
+			t.Fatalf("expected no error but found: %v", err)
+		}
+		comp("Height after discarded read", charts.Blocks.Height, seedUints(), true)
+	})
+
 	t.Run("Write_to_existing_non-GOB_file", func(t *testing.T) {
 		path := filepath.Join(tempDir, "log3.txt")
 
@@ -143,6 +195,21 @@ func TestChartsCache(t *testing.T) {
 		comp("NewAtoms before read", charts.Blocks.NewAtoms, compUints, false)
 		comp("Chainwork before read", charts.Blocks.Chainwork, compUints, false)
 		comp("Fees before read", charts.Blocks.Fees, compUints, false)
+		comp("NullData before read", charts.Blocks.NullData, compUints, false)
+		comp("MissedVotes before read", charts.Blocks.MissedVotes, compUints, false)
+		comp("Burned before read", charts.Blocks.Burned, compUints, false)
+		comp("DevFundIn before read", charts.Blocks.DevFundIn, compUints, false)
+		comp("DevFundOut before read", charts.Blocks.DevFundOut, compUints, false)
+		comp("FeeRates before read", charts.Blocks.FeeRates, seedFeeRates(), false)
+		comp("TicketFeeRates before read", charts.Blocks.TicketFeeRates, seedFeeRates(), false)
+		comp("ActiveAddresses before read", charts.Blocks.ActiveAddresses, compUints, false)
+		comp("DayActiveAddresses before read", charts.Blocks.DayActiveAddresses, compUints, false)
+		comp("NewAddresses before read", charts.Blocks.NewAddresses, compUints, false)
+		comp("UTXOCount before read", charts.Blocks.UTXOCount, compUints, false)
+		comp("UTXOValue before read", charts.Blocks.UTXOValue, compUints, false)
+		comp("TxValue before read", charts.Blocks.TxValue, compUints, false)
+		comp("RegularTxCount before read", charts.Blocks.RegularTxCount, compUints, false)
+		comp("CoinDaysDestroyed before read", charts.Blocks.CoinDaysDestroyed, compFloats, false)
 
 		err := charts.readCacheFile(gobPath)
 		if err != nil {
@@ -158,6 +225,21 @@ func TestChartsCache(t *testing.T) {
 		comp("NewAtoms after read", charts.Blocks.NewAtoms, compUints, true)
 		comp("Chainwork after read", charts.Blocks.Chainwork, compUints, true)
 		comp("Fees after read", charts.Blocks.Fees, compUints, true)
+		comp("NullData after read", charts.Blocks.NullData, compUints, true)
+		comp("MissedVotes after read", charts.Blocks.MissedVotes, compUints, true)
+		comp("Burned after read", charts.Blocks.Burned, compUints, true)
+		comp("DevFundIn after read", charts.Blocks.DevFundIn, compUints, true)
+		comp("DevFundOut after read", charts.Blocks.DevFundOut, compUints, true)
+		comp("FeeRates after read", charts.Blocks.FeeRates, seedFeeRates(), true)
+		comp("TicketFeeRates after read", charts.Blocks.TicketFeeRates, seedFeeRates(), true)
+		comp("ActiveAddresses after read", charts.Blocks.ActiveAddresses, compUints, true)
+		comp("DayActiveAddresses after read", charts.Blocks.DayActiveAddresses, compUints, true)
+		comp("NewAddresses after read", charts.Blocks.NewAddresses, compUints, true)
+		comp("UTXOCount after read", charts.Blocks.UTXOCount, compUints, true)
+		comp("UTXOValue after read", charts.Blocks.UTXOValue, compUints, true)
+		comp("TxValue after read", charts.Blocks.TxValue, compUints, true)
+		comp("RegularTxCount after read", charts.Blocks.RegularTxCount, compUints, true)
+		comp("CoinDaysDestroyed after read", charts.Blocks.CoinDaysDestroyed, compFloats, true)
 
 		// Lengthen is called during readCacheFile, so Days should be properly calculated
 		comp("Time after Lengthen", charts.Days.Time, ChartUints{0, aDay, 2 * aDay}, true)
@@ -169,6 +251,23 @@ func TestChartsCache(t *testing.T) {
 		// Chainwork will just be the last entry from each day
 		comp("Chainwork after Lengthen", charts.Days.Chainwork, ChartUints{2, 4, 6}, true)
 		comp("Fees after Lengthen", charts.Days.Fees, uintDaysSum, true)
+		comp("NullData after Lengthen", charts.Days.NullData, uintDaysSum, true)
+		comp("MissedVotes after Lengthen", charts.Days.MissedVotes, uintDaysSum, true)
+		comp("Burned after Lengthen", charts.Days.Burned, uintDaysSum, true)
+		comp("DevFundIn after Lengthen", charts.Days.DevFundIn, uintDaysSum, true)
+		comp("DevFundOut after Lengthen", charts.Days.DevFundOut, uintDaysSum, true)
+		// The fee rate counts are summed, and the statistics are averaged
+		// weighted by the counts.
+		comp("FeeRates count after Lengthen", charts.Days.FeeRates.Count, uintDaysSum, true)
+		comp("FeeRates median after Lengthen", charts.Days.FeeRates.Median, ChartUints{1, 2, 4}, true)
+		comp("TicketFeeRates max after Lengthen", charts.Days.TicketFeeRates.Max, ChartUints{1, 2, 4}, true)
+		// The daily active addresses are the sums of the block addresses not
+		// already active that day.
+		comp("ActiveAddresses after Lengthen", charts.Days.ActiveAddresses, uintDaysSum, true)
+		comp("NewAddresses after Lengthen", charts.Days.NewAddresses, uintDaysSum, true)
+		comp("TxValue after Lengthen", charts.Days.TxValue, uintDaysSum, true)
+		comp("RegularTxCount after Lengthen", charts.Days.RegularTxCount, uintDaysSum, true)
+		comp("CoinDaysDestroyed after Lengthen", charts.Days.CoinDaysDestroyed, ChartFloats{1.1, 5.5, 9.9}, true)
 
 		// An additional call to lengthen should not add any data.
 		timeLen := len(charts.Days.Time)
@@ -260,27 +359,37 @@ func TestChartReorg(t *testing.T) {
 			TicketPrice: newUints(),
 		}
 		charts.Days = &zoomSet{
-			cacheID:   0,
-			Height:    newUints(),
-			Time:      newUints(),
-			PoolSize:  newUints(),
-			PoolValue: newFloats(),
-			BlockSize: newUints(),
-			TxCount:   newUints(),
-			NewAtoms:  newUints(),
-			Chainwork: newUints(),
-			Fees:      newUints(),
+			cacheID:     0,
+			Height:      newUints(),
+			Time:        newUints(),
+			PoolSize:    newUints(),
+			PoolValue:   newFloats(),
+			BlockSize:   newUints(),
+			TxCount:     newUints(),
+			NewAtoms:    newUints(),
+			Chainwork:   newUints(),
+			Fees:        newUints(),
+			NullData:    newUints(),
+			MissedVotes: newUints(),
+			Burned:      newUints(),
+			DevFundIn:   newUints(),
+			DevFundOut:  newUints(),
 		}
 		charts.Blocks = &zoomSet{
-			cacheID:   0,
-			Time:      newUints(),
-			PoolSize:  newUints(),
-			PoolValue: newFloats(),
-			BlockSize: newUints(),
-			TxCount:   newUints(),
-			NewAtoms:  newUints(),
-			Chainwork: newUints(),
-			Fees:      newUints(),
+			cacheID:     0,
+			Time:        newUints(),
+			PoolSize:    newUints(),
+			PoolValue:   newFloats(),
+			BlockSize:   newUints(),
+			TxCount:     newUints(),
+			NewAtoms:    newUints(),
+			Chainwork:   newUints(),
+			Fees:        newUints(),
+			NullData:    newUints(),
+			MissedVotes: newUints(),
+			Burned:      newUints(),
+			DevFundIn:   newUints(),
+			DevFundOut:  newUints(),
 		}
 	}
 	// this test reorg will replace the entire chain.
@@ -0,0 +1,167 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+import (
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// feeRateSet is the fee rate statistics, in atoms/kB, of the transactions of
+// one kind in each bin. See dbtypes.FeeRateStats for the meaning of each
+// field. Blocks without such transactions, or stored before the fee rates were
+// introduced, have zero statistics. The statistics of a day are the averages
+// of the statistics of its blocks, weighted by Count.
+type feeRateSet struct {
+	Count  ChartUints
+	Min    ChartUints
+	P10    ChartUints
+	P25    ChartUints
+	Median ChartUints
+	P75    ChartUints
+	P90    ChartUints
+	Max    ChartUints
+}
+
+// Constructor for a sized feeRateSet.
+func newFeeRateSet(size int) feeRateSet {
+	return feeRateSet{
+		Count:  newChartUints(size),
+		Min:    newChartUints(size),
+		P10:    newChartUints(size),
+		P25:    newChartUints(size),
+		Median: newChartUints(size),
+		P75:    newChartUints(size),
+		P90:    newChartUints(size),
+		Max:    newChartUints(size),
+	}
+}
+
+// series returns pointers to the data sets, in the order of
+// (*dbtypes.FeeRateStats).Array.
+func (set *feeRateSet) series() []*ChartUints {
+	return []*ChartUints{&set.Count, &set.Min, &set.P10, &set.P25,
+		&set.Median, &set.P75, &set.P90, &set.Max}
+}
+
+// Length is the number of bins in the set.
+func (set *feeRateSet) Length() int {
+	return len(set.Count)
+}
+
+// snip truncates the feeRateSet to a provided length.
+func (set *feeRateSet) snip(length int) {
+	for _, s := range set.series() {
+		*s = s.snip(length)
+	}
+}
+
+// Append appends the fee rate statistics of a block. nil statistics are
+// appended as zeros.
+func (set *feeRateSet) Append(stats *dbtypes.FeeRateStats) {
+	series := set.series()
+	a := stats.Array()
+	if a == nil {
+		a = make(dbtypes.UInt64Array, len(series))
+	}
+	for i, s := range series {
+		*s = append(*s, a[i])
+	}
+}
+
+// appendAvg appends the total Count and the Count-weighted average statistics
+// of the blocks in the range [s, e).
+func (set *feeRateSet) appendAvg(blocks *feeRateSet, s, e int) {
+	count := blocks.Count.Sum(s, e)
+	dst, src := set.series(), blocks.series()
+	*dst[0] = append(*dst[0], count)
+	for i := 1; i < len(src); i++ {
+		var avg uint64
+		if count > 0 {
+			var sum uint64
+			for j := s; j < e; j++ {
+				sum += (*src[i])[j] * blocks.Count[j]
+			}
+			avg = sum / count
+		}
+		*dst[i] = append(*dst[i], avg)
+	}
+}
+
+// FeeRatesCount is the height of the FeeRates and TicketFeeRates data and the
+// total number of transactions they describe.
+func (charts *ChartData) FeeRatesCount() (tip int32, count uint64) {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	blocks := charts.Blocks
+	count = blocks.FeeRates.Count.Sum(0, blocks.FeeRates.Length()) +
+		blocks.TicketFeeRates.Count.Sum(0, blocks.TicketFeeRates.Length())
+	return int32(blocks.FeeRates.Length()) - 1, count
+}
+
+// ClearFeeRates clears the FeeRates and TicketFeeRates data so that the next
+// Update fetches them anew, such as after the fee rates of stored blocks were
+// computed by rebuilddb2. The day-binned fee rates are recomputed from the new
+// block data by Lengthen.
+func (charts *ChartData) ClearFeeRates() {
+	charts.mtx.Lock()
+	charts.Blocks.FeeRates.snip(0)
+	charts.Blocks.TicketFeeRates.snip(0)
+	charts.Days.FeeRates.snip(0)
+	charts.Days.TicketFeeRates.snip(0)
+	charts.mtx.Unlock()
+
+	charts.cacheMtx.Lock()
+	defer charts.cacheMtx.Unlock()
+	for _, zoom := range []ZoomLevel{BlockZoom, DayZoom} {
+		delete(charts.cache, cacheKey(FeeRates, zoom))
+		delete(charts.cache, cacheKey(TicketFeeRates, zoom))
+	}
+}
+
+// appendDayFeeRates appends the fee rates of the days of the day-binned data
+// that have none, such as after ClearFeeRates, as far as the block fee rates
+// allow. appendDayFeeRates must be called under (*ChartData).mtx.Lock, before
+// any new days are appended.
+func (charts *ChartData) appendDayFeeRates() {
+	blocks, days := charts.Blocks, charts.Days
+	for i := days.FeeRates.Length(); i < len(days.Height); i++ {
+		var s int
+		if i > 0 {
+			s = int(days.Height[i-1]) + 1
+		}
+		e := int(days.Height[i]) + 1
+		if e > blocks.FeeRates.Length() || e > blocks.TicketFeeRates.Length() {
+			return
+		}
+		days.FeeRates.appendAvg(&blocks.FeeRates, s, e)
+		days.TicketFeeRates.appendAvg(&blocks.TicketFeeRates, s, e)
+	}
+}
+
+// encodeFeeRates encodes the time and the fee rate statistics other than the
+// Count, in the order min, p10, p25, median, p75, p90, max.
+func (charts *ChartData) encodeFeeRates(time ChartUints, set *feeRateSet) ([]byte, error) {
+	return charts.encode(time, set.Min, set.P10, set.P25, set.Median,
+		set.P75, set.P90, set.Max)
+}
+
+func feeRatesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encodeFeeRates(charts.Blocks.Time, &charts.Blocks.FeeRates)
+	case DayZoom:
+		return charts.encodeFeeRates(charts.Days.Time, &charts.Days.FeeRates)
+	}
+	return nil, InvalidZoomErr
+}
+
+func ticketFeeRatesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encodeFeeRates(charts.Blocks.Time, &charts.Blocks.TicketFeeRates)
+	case DayZoom:
+		return charts.encodeFeeRates(charts.Days.Time, &charts.Days.TicketFeeRates)
+	}
+	return nil, InvalidZoomErr
+}
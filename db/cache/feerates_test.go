@@ -0,0 +1,101 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestFeeRateSet(t *testing.T) {
+	blocks := newFeeRateSet(3)
+	blocks.Append(&dbtypes.FeeRateStats{Count: 1, Min: 10, P10: 10, P25: 10,
+		Median: 10, P75: 10, P90: 10, Max: 10})
+	blocks.Append(nil)
+	blocks.Append(&dbtypes.FeeRateStats{Count: 3, Min: 6, P10: 8, P25: 10,
+		Median: 14, P75: 18, P90: 20, Max: 30})
+	if blocks.Length() != 3 {
+		t.Fatalf("expected 3 blocks, got %d", blocks.Length())
+	}
+	if !reflect.DeepEqual(blocks.Median, ChartUints{10, 0, 14}) {
+		t.Errorf("unexpected block medians %v", blocks.Median)
+	}
+
+	days := newFeeRateSet(2)
+	days.appendAvg(&blocks, 0, 3)
+	days.appendAvg(&blocks, 1, 2)
+	comp := func(name string, got, want ChartUints) {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	comp("count", days.Count, ChartUints{4, 0})
+	comp("min", days.Min, ChartUints{7, 0})
+	comp("median", days.Median, ChartUints{13, 0})
+	comp("max", days.Max, ChartUints{25, 0})
+
+	blocks.snip(1)
+	for _, s := range blocks.series() {
+		if len(*s) != 1 {
+			t.Fatalf("expected length 1 after snip, got %d", len(*s))
+		}
+	}
+}
+
+func TestClearFeeRates(t *testing.T) {
+	charts := &ChartData{
+		Blocks: &zoomSet{
+			FeeRates:       newFeeRateSet(3),
+			TicketFeeRates: newFeeRateSet(3),
+		},
+		Days: &zoomSet{
+			// Days of blocks 0-1 and 2.
+			Height:         ChartUints{1, 2},
+			FeeRates:       newFeeRateSet(2),
+			TicketFeeRates: newFeeRateSet(2),
+		},
+		cache: map[string]*cachedChart{
+			cacheKey(FeeRates, DayZoom): {},
+			cacheKey(Fees, DayZoom):     {},
+		},
+	}
+	appendBlocks := func(counts ...uint64) {
+		for _, c := range counts {
+			charts.Blocks.FeeRates.Append(&dbtypes.FeeRateStats{Count: c, Median: 10})
+			charts.Blocks.TicketFeeRates.Append(nil)
+		}
+	}
+
+	// Fee rates not computed for the first blocks.
+	appendBlocks(0, 0, 2)
+	charts.appendDayFeeRates()
+	if tip, count := charts.FeeRatesCount(); tip != 2 || count != 2 {
+		t.Errorf("got tip %d and count %d, want 2 and 2", tip, count)
+	}
+	if !reflect.DeepEqual(charts.Days.FeeRates.Count, ChartUints{0, 2}) {
+		t.Errorf("unexpected day counts %v", charts.Days.FeeRates.Count)
+	}
+
+	charts.ClearFeeRates()
+	if tip, count := charts.FeeRatesCount(); tip != -1 || count != 0 {
+		t.Errorf("got tip %d and count %d after clearing", tip, count)
+	}
+	if charts.Days.FeeRates.Length() != 0 || len(charts.cache) != 1 {
+		t.Errorf("day fee rates or cached fee rate charts not cleared")
+	}
+
+	// The day fee rates are recomputed as far as the block fee rates allow.
+	appendBlocks(1, 3)
+	charts.appendDayFeeRates()
+	if !reflect.DeepEqual(charts.Days.FeeRates.Count, ChartUints{4}) {
+		t.Errorf("unexpected day counts %v", charts.Days.FeeRates.Count)
+	}
+	appendBlocks(2)
+	charts.appendDayFeeRates()
+	if !reflect.DeepEqual(charts.Days.FeeRates.Count, ChartUints{4, 2}) {
+		t.Errorf("unexpected day counts %v", charts.Days.FeeRates.Count)
+	}
+}
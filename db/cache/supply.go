@@ -0,0 +1,187 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+import (
+	"math"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+// supplySet is the coin supply breakdown at each block, in atoms. See
+// dbtypes.SupplyBreakdown for the meaning of each field.
+type supplySet struct {
+	Mined       ChartUints
+	Burned      ChartUints
+	DevFund     ChartUints
+	Immature    ChartUints
+	Circulating ChartUints
+	Staked      ChartUints
+}
+
+// cachedSupply is the supply breakdown of the block-binned data with the
+// cacheID and supply length at which it was computed.
+type cachedSupply struct {
+	cacheID uint64
+	length  int
+	set     *supplySet
+}
+
+// nonNegative converts the amount to a uint64, replacing negative amounts
+// with zero.
+func nonNegative(amt int64) uint64 {
+	if amt < 0 {
+		return 0
+	}
+	return uint64(amt)
+}
+
+// supplyBreakdown computes the coin supply breakdown at each block of the
+// block-binned data, up to the shortest of the data sets used. The coins
+// minted at a height less tax(height), the project fund subsidy, are immature
+// for maturity blocks.
+func supplyBreakdown(blocks *zoomSet, maturity int, tax func(int64) int64) *supplySet {
+	n := supplyLength(blocks)
+	s := &supplySet{
+		Mined:       newChartUints(n),
+		Burned:      newChartUints(n),
+		DevFund:     newChartUints(n),
+		Immature:    newChartUints(n),
+		Circulating: newChartUints(n),
+		Staked:      newChartUints(n),
+	}
+	minted := make([]int64, n)
+	var mined, burned, devFund, immature int64
+	for i := 0; i < n; i++ {
+		mined += int64(blocks.NewAtoms[i])
+		burned += int64(blocks.Burned[i])
+		devFund += int64(blocks.DevFundIn[i]) - int64(blocks.DevFundOut[i])
+		minted[i] = int64(nonNegative(int64(blocks.NewAtoms[i]) - tax(int64(i))))
+		immature += minted[i]
+		if i >= maturity {
+			immature -= minted[i-maturity]
+		}
+		s.Mined = append(s.Mined, uint64(mined))
+		s.Burned = append(s.Burned, uint64(burned))
+		s.DevFund = append(s.DevFund, nonNegative(devFund))
+		s.Immature = append(s.Immature, uint64(immature))
+		s.Circulating = append(s.Circulating,
+			nonNegative(mined-burned-int64(nonNegative(devFund))-immature))
+		s.Staked = append(s.Staked, uint64(math.Round(blocks.PoolValue[i]*1e8)))
+	}
+	return s
+}
+
+// supplyLength is the number of blocks of the block-binned data with supply
+// data, the length of the shortest of the data sets used.
+func supplyLength(blocks *zoomSet) int {
+	n := blocks.NewAtoms.Length()
+	for _, set := range []lengther{blocks.PoolValue, blocks.Burned,
+		blocks.DevFundIn, blocks.DevFundOut} {
+		if l := set.Length(); l < n {
+			n = l
+		}
+	}
+	return n
+}
+
+// projectSubsidy returns a function that computes the project fund subsidy of
+// a block with all votes cast. The subsidy only changes at the subsidy
+// reduction interval, so it is computed once per interval.
+func projectSubsidy(params *chaincfg.Params) func(int64) int64 {
+	subsidies := make(map[int64]int64)
+	return func(height int64) int64 {
+		// The first blocks are special cases, so they are not cached.
+		if height < 2 {
+			_, _, tax := txhelpers.RewardsAtBlock(height, params.TicketsPerBlock, params)
+			return tax
+		}
+		interval := height / params.SubsidyReductionInterval
+		tax, found := subsidies[interval]
+		if !found {
+			_, _, tax = txhelpers.RewardsAtBlock(height, params.TicketsPerBlock, params)
+			subsidies[interval] = tax
+		}
+		return tax
+	}
+}
+
+// supply returns the coin supply breakdown at each block. The breakdown is only
+// recomputed when the block-binned data has changed since it was last
+// computed. supply should be called under at least a (*ChartData).RLock.
+func (charts *ChartData) supply() *supplySet {
+	length := supplyLength(charts.Blocks)
+	charts.cacheMtx.RLock()
+	cacheID := charts.Blocks.cacheID
+	cached := charts.supplyCache
+	charts.cacheMtx.RUnlock()
+	if cached != nil && cached.cacheID == cacheID && cached.length == length {
+		return cached.set
+	}
+
+	s := supplyBreakdown(charts.Blocks, int(charts.chainParams.CoinbaseMaturity),
+		projectSubsidy(charts.chainParams))
+	charts.cacheMtx.Lock()
+	charts.supplyCache = &cachedSupply{
+		cacheID: cacheID,
+		length:  length,
+		set:     s,
+	}
+	charts.cacheMtx.Unlock()
+	return s
+}
+
+// SupplyBreakdown is the coin supply breakdown at the best block of the charts
+// data. nil is returned if there is no supply data yet.
+func (charts *ChartData) SupplyBreakdown() *dbtypes.SupplyBreakdown {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	s := charts.supply()
+	i := s.Mined.Length() - 1
+	if i < 0 || i >= charts.Blocks.Time.Length() {
+		return nil
+	}
+	return &dbtypes.SupplyBreakdown{
+		Height:      int64(i),
+		Time:        int64(charts.Blocks.Time[i]),
+		Mined:       int64(s.Mined[i]),
+		Burned:      int64(s.Burned[i]),
+		DevFund:     int64(s.DevFund[i]),
+		Immature:    int64(s.Immature[i]),
+		Circulating: int64(s.Circulating[i]),
+		Staked:      int64(s.Staked[i]),
+		Liquid:      int64(nonNegative(int64(s.Circulating[i]) - int64(s.Staked[i]))),
+	}
+}
+
+// sampleUints picks the data at the given ascending heights. Heights beyond
+// the end of the data are skipped.
+func sampleUints(data, heights ChartUints) ChartUints {
+	d := make(ChartUints, 0, len(heights))
+	for _, h := range heights {
+		if h >= uint64(len(data)) {
+			break
+		}
+		d = append(d, data[h])
+	}
+	return d
+}
+
+func supplyChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		s := charts.supply()
+		return charts.encode(charts.Blocks.Time, s.Circulating, s.Staked,
+			s.DevFund, s.Burned, s.Immature)
+	case DayZoom:
+		s := charts.supply()
+		heights := charts.Days.Height
+		return charts.encode(charts.Days.Time, sampleUints(s.Circulating, heights),
+			sampleUints(s.Staked, heights), sampleUints(s.DevFund, heights),
+			sampleUints(s.Burned, heights), sampleUints(s.Immature, heights))
+	}
+	return nil, InvalidZoomErr
+}
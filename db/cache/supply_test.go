@@ -0,0 +1,89 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+)
+
+func TestSupplyBreakdown(t *testing.T) {
+	blocks := &zoomSet{
+		NewAtoms:   ChartUints{100, 50, 50, 50, 50},
+		PoolValue:  ChartFloats{0, 0, 1e-7, 2e-7, 1e-7},
+		Burned:     ChartUints{0, 0, 5, 0, 3},
+		DevFundIn:  ChartUints{0, 10, 10, 10, 10},
+		DevFundOut: ChartUints{0, 0, 0, 25, 0},
+	}
+	// The project fund subsidy is 10 atoms, except in the first block.
+	tax := func(height int64) int64 {
+		if height == 0 {
+			return 0
+		}
+		return 10
+	}
+	s := supplyBreakdown(blocks, 2, tax)
+
+	comp := func(name string, got, want ChartUints) {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	comp("mined", s.Mined, ChartUints{100, 150, 200, 250, 300})
+	comp("burned", s.Burned, ChartUints{0, 0, 5, 5, 8})
+	comp("dev fund", s.DevFund, ChartUints{0, 10, 20, 5, 15})
+	comp("immature", s.Immature, ChartUints{100, 140, 80, 80, 80})
+	comp("circulating", s.Circulating, ChartUints{0, 0, 95, 160, 197})
+	comp("staked", s.Staked, ChartUints{0, 0, 10, 20, 10})
+
+	// The breakdown stops at the shortest data set.
+	blocks.Burned = blocks.Burned[:3]
+	if s = supplyBreakdown(blocks, 2, tax); s.Mined.Length() != 3 ||
+		s.Circulating.Length() != 3 {
+		t.Errorf("expected 3 blocks, got %d", s.Mined.Length())
+	}
+}
+
+func TestSupplyCache(t *testing.T) {
+	charts := &ChartData{
+		chainParams: &chaincfg.MainNetParams,
+		Blocks: &zoomSet{
+			NewAtoms:   ChartUints{100, 50},
+			PoolValue:  ChartFloats{0, 0},
+			Burned:     ChartUints{0, 0},
+			DevFundIn:  ChartUints{0, 10},
+			DevFundOut: ChartUints{0, 0},
+			cacheID:    1,
+		},
+	}
+	s := charts.supply()
+	if s.Mined.Length() != 2 {
+		t.Fatalf("expected 2 blocks, got %d", s.Mined.Length())
+	}
+	if charts.supply() != s {
+		t.Errorf("supply recomputed without a data change")
+	}
+
+	// Snipped data is recomputed, even with the same cacheID.
+	charts.Blocks.Burned = charts.Blocks.Burned[:1]
+	if s = charts.supply(); s.Mined.Length() != 1 {
+		t.Errorf("expected 1 block after snip, got %d", s.Mined.Length())
+	}
+
+	// New data is recomputed.
+	charts.Blocks.Burned = append(charts.Blocks.Burned, 2)
+	charts.Blocks.cacheID = 2
+	if s = charts.supply(); s.Burned.Length() != 2 || s.Burned[1] != 2 {
+		t.Errorf("unexpected burned %v", s.Burned)
+	}
+}
+
+func TestSampleUints(t *testing.T) {
+	data := ChartUints{5, 6, 7, 8}
+	if s := sampleUints(data, ChartUints{1, 3, 5}); !reflect.DeepEqual(s, ChartUints{6, 8}) {
+		t.Errorf("unexpected sample %v", s)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+// AppendUTXOChange appends the number and value of the unspent transaction
+// outputs after a block that created and spent the given numbers and values of
+// outputs. The UTXOCount and UTXOValue data are cumulative.
+func (set *zoomSet) AppendUTXOChange(created, createdValue, spent, spentValue uint64) {
+	var count, value uint64
+	if n := len(set.UTXOCount); n > 0 {
+		count = set.UTXOCount[n-1]
+	}
+	if n := len(set.UTXOValue); n > 0 {
+		value = set.UTXOValue[n-1]
+	}
+	set.UTXOCount = append(set.UTXOCount,
+		nonNegative(int64(count)+int64(created)-int64(spent)))
+	set.UTXOValue = append(set.UTXOValue,
+		nonNegative(int64(value)+int64(createdValue)-int64(spentValue)))
+}
+
+// averages divides each of the totals by the corresponding count, up to the
+// shorter of the two. The average is zero where the count is zero.
+func averages(totals, counts ChartUints) ChartUints {
+	n := len(totals)
+	if len(counts) < n {
+		n = len(counts)
+	}
+	avgs := newChartUints(n)
+	for i := 0; i < n; i++ {
+		var avg uint64
+		if counts[i] > 0 {
+			avg = totals[i] / counts[i]
+		}
+		avgs = append(avgs, avg)
+	}
+	return avgs
+}
+
+func activeAddressesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time, charts.Blocks.ActiveAddresses)
+	case DayZoom:
+		return charts.encode(charts.Days.Time, charts.Days.ActiveAddresses)
+	}
+	return nil, InvalidZoomErr
+}
+
+func newAddressesChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time, charts.Blocks.NewAddresses)
+	case DayZoom:
+		return charts.encode(charts.Days.Time, charts.Days.NewAddresses)
+	}
+	return nil, InvalidZoomErr
+}
+
+func utxoSetChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	blocks := charts.Blocks
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(blocks.Time, blocks.UTXOCount, blocks.UTXOValue)
+	case DayZoom:
+		heights := charts.Days.Height
+		return charts.encode(charts.Days.Time, sampleUints(blocks.UTXOCount, heights),
+			sampleUints(blocks.UTXOValue, heights))
+	}
+	return nil, InvalidZoomErr
+}
+
+func avgTxValueChart(charts *ChartData, zoom ZoomLevel) ([]byte, error) {
+	switch zoom {
+	case BlockZoom:
+		return charts.encode(charts.Blocks.Time,
+			averages(charts.Blocks.TxValue, charts.Blocks.RegularTxCount))
+	case DayZoom:
+		return charts.encode(charts.Days.Time,
+			averages(charts.Days.TxValue, charts.Days.RegularTxCount))
+	}
+	return nil, InvalidZoomErr
+}
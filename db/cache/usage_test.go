@@ -0,0 +1,30 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppendUTXOChange(t *testing.T) {
+	set := newBlockSet(3)
+	set.AppendUTXOChange(2, 100, 0, 0)
+	set.AppendUTXOChange(3, 50, 1, 60)
+	// Spending more than the set holds floors at zero.
+	set.AppendUTXOChange(0, 0, 10, 1000)
+	if !reflect.DeepEqual(set.UTXOCount, ChartUints{2, 4, 0}) {
+		t.Errorf("unexpected UTXO counts %v", set.UTXOCount)
+	}
+	if !reflect.DeepEqual(set.UTXOValue, ChartUints{100, 90, 0}) {
+		t.Errorf("unexpected UTXO values %v", set.UTXOValue)
+	}
+}
+
+func TestAverages(t *testing.T) {
+	avgs := averages(ChartUints{10, 9, 0, 7}, ChartUints{2, 0, 5})
+	if !reflect.DeepEqual(avgs, ChartUints{5, 0, 0}) {
+		t.Errorf("unexpected averages %v", avgs)
+	}
+}
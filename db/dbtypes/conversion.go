@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/fonero-project/fnod/blockchain"
+	"github.com/fonero-project/fnod/blockchain/stake"
 	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnod/wire"
 	"github.com/fonero-project/fnodata/txhelpers"
@@ -55,6 +57,40 @@ func MsgBlockToDBBlock(msgBlock *wire.MsgBlock, chainParams *chaincfg.Params, ch
 	}
 }
 
+// MsgBlockFeeRates computes the fee rate statistics of the regular
+// transactions, excluding the coinbase, and the ticket purchases of a
+// wire.MsgBlock. The fees are computed from the input amounts recorded in the
+// block.
+func MsgBlockFeeRates(msgBlock *wire.MsgBlock) *BlockFeeRates {
+	feeRate := func(msgTx *wire.MsgTx) uint64 {
+		_, rate := txhelpers.TxFeeRate(msgTx)
+		if rate < 0 {
+			return 0
+		}
+		return uint64(rate)
+	}
+
+	regular := make([]uint64, 0, len(msgBlock.Transactions))
+	for _, msgTx := range msgBlock.Transactions {
+		if blockchain.IsCoinBaseTx(msgTx) {
+			continue
+		}
+		regular = append(regular, feeRate(msgTx))
+	}
+
+	var tickets []uint64
+	for _, msgTx := range msgBlock.STransactions {
+		if stake.DetermineTxType(msgTx) == stake.TxTypeSStx {
+			tickets = append(tickets, feeRate(msgTx))
+		}
+	}
+
+	return &BlockFeeRates{
+		Regular: NewFeeRateStats(regular),
+		Tickets: NewFeeRateStats(tickets),
+	}
+}
+
 // TimeBasedGroupingToInterval converts the TimeBasedGrouping value to an actual
 // time value in seconds based on the gregorian calendar except AllGrouping that
 // returns 1 while the unknownGrouping returns -1 and an error.
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -173,6 +174,38 @@ func (p TicketSpendType) String() string {
 	}
 }
 
+// MempoolRemovalReason describes why a transaction left mempool.
+type MempoolRemovalReason int16
+
+const (
+	// MempoolTxPending indicates that the transaction has not left mempool.
+	MempoolTxPending MempoolRemovalReason = iota
+	// MempoolTxMined indicates that the transaction was included in a main
+	// chain block.
+	MempoolTxMined
+	// MempoolTxDoubleSpent indicates that a different main chain transaction
+	// spent one of the transaction's previous outpoints.
+	MempoolTxDoubleSpent
+	// MempoolTxExpired indicates that the transaction left mempool without
+	// being mined or double spent, such as when its expiry height passed.
+	MempoolTxExpired
+)
+
+func (r MempoolRemovalReason) String() string {
+	switch r {
+	case MempoolTxPending:
+		return "pending"
+	case MempoolTxMined:
+		return "mined"
+	case MempoolTxDoubleSpent:
+		return "double-spent"
+	case MempoolTxExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
 // AgendaStatusType defines the various agenda statuses.
 type AgendaStatusType int8
 
@@ -555,6 +588,34 @@ type AgendaSummary struct {
 	LockedIn      int64
 }
 
+// AgendaScenario is a projected tally of the votes on an agenda at the end of
+// the current rule change interval. Outcome is "lockedin" or "failed" if the
+// vote would be decided, or "started" if voting would continue into the next
+// rule change interval.
+type AgendaScenario struct {
+	Aye            uint32  `json:"aye"`
+	Nay            uint32  `json:"nay"`
+	Abstain        uint32  `json:"abstain"`
+	Approval       float32 `json:"approval"`
+	QuorumAchieved bool    `json:"quorum_achieved"`
+	Outcome        string  `json:"outcome"`
+}
+
+// AgendaPrediction projects the outcome of the vote on an agenda at the end of
+// the current rule change interval. Expected assumes that the RemainingBlocks
+// are voted on at the participation, abstain, and approval rates seen so far
+// in the interval. Best and Worst assume that every remaining vote is cast,
+// for and against the agenda respectively. Certain is true if the outcome is
+// the same in every scenario.
+type AgendaPrediction struct {
+	RemainingBlocks uint32         `json:"remaining_blocks"`
+	Participation   float32        `json:"participation"`
+	Best            AgendaScenario `json:"best"`
+	Expected        AgendaScenario `json:"expected"`
+	Worst           AgendaScenario `json:"worst"`
+	Certain         bool           `json:"certain"`
+}
+
 // BlockChainData defines data holding the latest block chain state from the
 // getblockchaininfo rpc endpoint.
 type BlockChainData struct {
@@ -753,6 +814,340 @@ type Vout struct {
 	Version          uint16           `json:"version"`
 	ScriptPubKey     []byte           `json:"pkScriptHex"`
 	ScriptPubKeyData ScriptPubKeyData `json:"pkScript"`
+	// Pruned indicates that the spent output was removed by archival pruning,
+	// so only TxIndex is known.
+	Pruned bool `json:"pruned,omitempty"`
+}
+
+// NullData is a nulldata (OP_RETURN) transaction output in a main chain block.
+// Payload is the hexadecimal encoded data carried by the output, and Text is
+// the data as text if it is printable UTF-8.
+type NullData struct {
+	TxHash      string  `json:"txid"`
+	TxIndex     uint32  `json:"vout"`
+	BlockHeight int64   `json:"block_height"`
+	BlockTime   TimeDef `json:"block_time"`
+	Payload     string  `json:"payload"`
+	Text        string  `json:"text,omitempty"`
+}
+
+// TicketCommitment is a commitment output of a main chain ticket purchase to a
+// reward address. Amount is the contribution to the ticket Price by the owner
+// of the reward address. VoteFeeLimit and RevokeFeeLimit are the largest fees
+// that may be paid from the commitment by a vote or revocation. All amounts
+// are in FNO.
+type TicketCommitment struct {
+	TicketHash     string  `json:"ticket"`
+	TxIndex        uint32  `json:"vout"`
+	BlockHeight    int64   `json:"block_height"`
+	Price          float64 `json:"price"`
+	Amount         float64 `json:"amount"`
+	VoteFeeLimit   float64 `json:"vote_fee_limit"`
+	RevokeFeeLimit float64 `json:"revoke_fee_limit"`
+	SpendType      string  `json:"spend_type"`
+	PoolStatus     string  `json:"pool_status"`
+}
+
+// MissRate is the number of votes and missed votes in the main chain blocks
+// from StartHeight through EndHeight, the first of which is at StartTime.
+// MissRate is Misses / (Votes + Misses).
+type MissRate struct {
+	StartHeight int64   `json:"start_height"`
+	EndHeight   int64   `json:"end_height"`
+	StartTime   TimeDef `json:"start_time"`
+	Votes       int64   `json:"votes"`
+	Misses      int64   `json:"misses"`
+	MissRate    float64 `json:"miss_rate"`
+}
+
+// UnrevokedTicket is a main chain ticket that missed or expired, and is not yet
+// revoked. MissHeight is the height of the block in which a missed ticket was
+// called to vote.
+type UnrevokedTicket struct {
+	TicketHash    string  `json:"ticket"`
+	BlockHeight   int64   `json:"block_height"`
+	Price         float64 `json:"price"`
+	PoolStatus    string  `json:"pool_status"`
+	VotingAddress string  `json:"voting_address"`
+	MissHeight    int64   `json:"miss_height,omitempty"`
+}
+
+// StakePool is a voting service provider (VSP). Tickets are attributed to the
+// pool by its Addresses, which may be the reward address of its pool fee
+// commitments or voting addresses shared by its tickets. Detected pools were
+// recognized from their pool fee commitments rather than configured, and are
+// named by their pool fee address.
+type StakePool struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	Detected  bool     `json:"detected"`
+}
+
+// HasAddress checks if the address is one of the pool's addresses.
+func (p *StakePool) HasAddress(address string) bool {
+	for _, a := range p.Addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// StakePoolStats are the main chain ticket counts of a StakePool by pool
+// status. MissRate is the fraction of the pool's called tickets that were
+// missed, Missed / (Voted + Missed).
+type StakePoolStats struct {
+	*StakePool
+	Live     int64   `json:"live"`
+	Voted    int64   `json:"voted"`
+	Missed   int64   `json:"missed"`
+	Expired  int64   `json:"expired"`
+	MissRate float64 `json:"miss_rate"`
+}
+
+// StakePoolChart is the history of a StakePool's tickets binned by ticket
+// price window. Height is the first block height of each window. Live is the
+// number of the pool's live tickets at the end of the window, and Votes and
+// Misses are the number of the pool's tickets that voted or were missed in
+// the window.
+type StakePoolChart struct {
+	Height   []int64   `json:"height"`
+	Live     []int64   `json:"live"`
+	Votes    []int64   `json:"votes"`
+	Misses   []int64   `json:"misses"`
+	MissRate []float64 `json:"miss_rate"`
+}
+
+// MiningPool is a mining pool. Blocks are attributed to the pool if their
+// coinbase text contains any of the pool's Tags, ignoring case, or if their
+// coinbase transaction pays to any of the pool's Addresses.
+type MiningPool struct {
+	Name      string   `json:"name"`
+	Tags      []string `json:"tags"`
+	Addresses []string `json:"addresses"`
+}
+
+// Matches checks if a block with the given coinbase text and payout addresses
+// was mined by the pool.
+func (p *MiningPool) Matches(text string, addresses []string) bool {
+	text = strings.ToLower(text)
+	for _, tag := range p.Tags {
+		if strings.Contains(text, strings.ToLower(tag)) {
+			return true
+		}
+	}
+	for _, a := range p.Addresses {
+		for _, address := range addresses {
+			if a == address {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BlockMiner is the miner of a block. CoinbaseText is the text found in the
+// signature script of the block's coinbase transaction, and PayoutAddresses
+// are the addresses paid by the coinbase, except the project fund. Pool is
+// the name of the mining pool that mined the block, and is empty if the miner
+// is unknown.
+type BlockMiner struct {
+	Hash            string   `json:"hash"`
+	Height          int64    `json:"height"`
+	CoinbaseText    string   `json:"coinbase_text"`
+	PayoutAddresses []string `json:"payout_addresses"`
+	Pool            string   `json:"pool"`
+}
+
+// MiningPoolStats are the number of main chain blocks mined by a pool in the
+// last Window blocks, and the pool's Share of them. Pool is empty for blocks
+// from unknown miners. LastHeight is the height of the last block mined by the
+// pool.
+type MiningPoolStats struct {
+	Pool       string  `json:"pool"`
+	Blocks     int64   `json:"blocks"`
+	Share      float64 `json:"share"`
+	LastHeight int64   `json:"last_height"`
+	Window     int64   `json:"window"`
+}
+
+// MiningPoolChart is the number of main chain blocks mined by each mining pool
+// binned by ticket price window. Height is the first block height of each
+// window, and Blocks[i] is the number of blocks mined by Pools[i] in each
+// window. The pool of blocks from unknown miners is empty.
+type MiningPoolChart struct {
+	Height []int64   `json:"height"`
+	Pools  []string  `json:"pools"`
+	Blocks [][]int64 `json:"blocks"`
+}
+
+// TreasurySpend is a transaction spending from the project fund. Amount is
+// the amount spent less any change returned to the project fund, and Proposal
+// is the token of the Politeia proposal paid by the spend, if it is known.
+type TreasurySpend struct {
+	TxID     string  `json:"txid"`
+	Height   int64   `json:"height"`
+	Time     TimeDef `json:"time"`
+	Amount   int64   `json:"amount"`
+	Proposal string  `json:"proposal,omitempty"`
+}
+
+// TreasuryMonthly is the project fund flows in each calendar month (UTC),
+// starting with the month of the first flow. Subsidy is the project fund
+// subsidy received in coinbase transactions, OtherIn is any other amount
+// received, Spent is the amount spent, of which Proposals paid for known
+// Politeia proposals, and Balance is the balance at the end of the month.
+type TreasuryMonthly struct {
+	Time      []int64 `json:"time"`
+	Subsidy   []int64 `json:"subsidy"`
+	OtherIn   []int64 `json:"other_in"`
+	Spent     []int64 `json:"spent"`
+	Proposals []int64 `json:"proposals"`
+	Balance   []int64 `json:"balance"`
+}
+
+// TreasuryFlows summarizes the flows of the project fund address as of the
+// block at Height. TaxPerBlock is the block subsidy tax of the next block. The
+// Spends are ordered newest first. If the address history was pruned, flows
+// before PrunedBefore are incomplete, but the balances are not affected.
+type TreasuryFlows struct {
+	Address      string           `json:"address"`
+	Height       int64            `json:"height"`
+	Balance      int64            `json:"balance"`
+	TaxPerBlock  int64            `json:"tax_per_block"`
+	TotalSubsidy int64            `json:"total_subsidy"`
+	TotalOtherIn int64            `json:"total_other_in"`
+	TotalSpent   int64            `json:"total_spent"`
+	Monthly      *TreasuryMonthly `json:"monthly"`
+	Spends       []*TreasurySpend `json:"spends"`
+	PrunedBefore *TimeDef         `json:"pruned_before,omitempty"`
+}
+
+// SupplyBreakdown is the coin supply at the block at Height, in atoms, broken
+// down by where the coins are. Mined is all coins created by the block subsidy.
+// Burned coins were sent to nulldata outputs or the zero pubkey hash address,
+// and can never be spent. DevFund is the balance of the project fund address.
+// Immature coins were minted in the last CoinbaseMaturity blocks, excluding
+// the project fund subsidy, and cannot be spent yet. Circulating is Mined less
+// the Burned, DevFund and Immature amounts. Staked coins are locked in live
+// tickets, and Liquid is the Circulating amount that is not Staked.
+type SupplyBreakdown struct {
+	Height      int64 `json:"block_height"`
+	Time        int64 `json:"block_time"`
+	Mined       int64 `json:"supply_mined"`
+	Burned      int64 `json:"burned"`
+	DevFund     int64 `json:"dev_fund"`
+	Immature    int64 `json:"immature"`
+	Circulating int64 `json:"circulating"`
+	Staked      int64 `json:"staked"`
+	Liquid      int64 `json:"liquid"`
+}
+
+// UTXOAgeBandDays are the upper limits, in days, of the age bands of the
+// unspent transaction outputs in a UTXOAges distribution. The last band has no
+// upper limit.
+var UTXOAgeBandDays = []int64{1, 7, 30, 90, 180, 365, 730, 1095, 1825}
+
+// UTXOAgeBandLabels are the names of the age bands of a UTXOAges distribution.
+var UTXOAgeBandLabels = []string{"<1d", "1d-1w", "1w-1m", "1m-3m", "3m-6m",
+	"6m-1y", "1y-2y", "2y-3y", "3y-5y", ">5y"}
+
+// UTXOAges is the age distribution of the unspent transaction outputs at the
+// end of a UTC day, the Time. Counts[i] and Amounts[i] are the number and value
+// in atoms of the outputs in age band i. See UTXOAgeBandDays.
+type UTXOAges struct {
+	Time    int64   `json:"time"`
+	Counts  []int64 `json:"counts"`
+	Amounts []int64 `json:"amounts"`
+}
+
+// UTXOAgeDistribution is the daily history of the UTXO age distribution, also
+// known as HODL waves.
+type UTXOAgeDistribution struct {
+	Bands []string    `json:"bands"`
+	Days  []*UTXOAges `json:"days"`
+}
+
+// The categories of known addresses in the address labels registry.
+const (
+	LabelExchange = "exchange"
+	LabelPool     = "pool"
+	LabelTreasury = "treasury"
+	LabelBurn     = "burn"
+	LabelOther    = "other"
+)
+
+// AddressLabelCategories are the valid AddressLabel categories.
+var AddressLabelCategories = []string{LabelExchange, LabelPool, LabelTreasury,
+	LabelBurn, LabelOther}
+
+// AddressLabel is the label of a known address, such as an exchange hot wallet
+// or a mining pool payout address. URL optionally links to the owner.
+type AddressLabel struct {
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	URL      string `json:"url,omitempty"`
+}
+
+// AddressLabels is the known address labels registry. Version is the revision
+// of the labels file, which is increased whenever the labels are edited.
+type AddressLabels struct {
+	Version int64           `json:"version"`
+	Labels  []*AddressLabel `json:"labels"`
+}
+
+// TicketPoolForecast is the projected ticket pool following the block at
+// TipHeight, in bins of consecutive blocks. For each bin, Height and Time are
+// the height and projected time of the last block, Maturing is the number of
+// tickets that mature into the pool, Votes and Expiring are the expected
+// numbers of tickets that vote and expire, and PoolSize is the expected size
+// of the pool after the last block. Tickets purchased after TipHeight are not
+// projected.
+type TicketPoolForecast struct {
+	TipHeight   int64     `json:"tip_height"`
+	TipPoolSize int64     `json:"tip_pool_size"`
+	Height      []int64   `json:"height"`
+	Time        []int64   `json:"time"`
+	Maturing    []int64   `json:"maturing"`
+	Votes       []float64 `json:"votes"`
+	Expiring    []float64 `json:"expiring"`
+	PoolSize    []float64 `json:"pool_size"`
+}
+
+// VersionShares is the number of blocks or votes with Version in each stake
+// version interval, and their percentage of all the blocks or votes in the
+// interval.
+type VersionShares struct {
+	Version int64     `json:"version"`
+	Count   []int64   `json:"count"`
+	Percent []float64 `json:"percent"`
+}
+
+// VersionHistory is the adoption of block, stake, and vote versions by stake
+// version interval. Height is the height of the first block of each interval
+// of IntervalSize blocks. The shares of each version are aligned with Height.
+type VersionHistory struct {
+	IntervalSize  int64            `json:"interval_size"`
+	Height        []int64          `json:"height"`
+	BlockVersions []*VersionShares `json:"block_versions"`
+	StakeVersions []*VersionShares `json:"stake_versions"`
+	VoteVersions  []*VersionShares `json:"vote_versions"`
+}
+
+// RedeemScript is the decoded redeem script of a P2SH address, revealed by the
+// first transaction input spending from the address. For a multisig script,
+// RequiredSigs of the PubKeys are needed to spend, and Addresses are the P2PKH
+// addresses of the PubKeys. Script is hexadecimal encoded.
+type RedeemScript struct {
+	Address         string   `json:"address"`
+	Script          string   `json:"script"`
+	Class           string   `json:"class"`
+	RequiredSigs    int      `json:"required_sigs"`
+	PubKeys         []string `json:"pubkeys,omitempty"`
+	Addresses       []string `json:"addresses"`
+	SpendingTxHash  string   `json:"spending_txid"`
+	SpendingTxIndex uint32   `json:"spending_vin"`
 }
 
 // UTXOData stores an address and value associated with a transaction output.
@@ -1273,6 +1668,14 @@ type ProposalChartsData struct {
 	Time []TimeDef `json:"time,omitempty"`
 }
 
+// ProposalVotesTimeline defines the cumulative proposal vote tallies at block
+// resolution. Only the heights at which the tallies changed are included.
+type ProposalVotesTimeline struct {
+	Height []uint64 `json:"height"`
+	Yes    []uint64 `json:"yes"`
+	No     []uint64 `json:"no"`
+}
+
 // ScriptPubKeyData is part of the result of decodescript(ScriptPubKeyHex)
 type ScriptPubKeyData struct {
 	ReqSigs   uint32   `json:"reqSigs"`
@@ -1347,6 +1750,64 @@ type AgendaVoteChoices struct {
 	Time    []TimeDef `json:"time,omitempty"`
 }
 
+// AgendaAddressVotes contains the vote choice counts for an agenda cast by
+// tickets with the same voting (stake submission) address. Tickets purchased
+// through a stake pool share the pool's voting address.
+type AgendaAddressVotes struct {
+	Address string `json:"address"`
+	Yes     uint32 `json:"yes"`
+	Abstain uint32 `json:"abstain"`
+	No      uint32 `json:"no"`
+	Total   uint32 `json:"total"`
+}
+
+// MempoolFeeRateBuckets are the upper edges, in FNO/kB, of the fee rate
+// histogram buckets of a MempoolFeeSnapshot. The final histogram bucket holds
+// the transactions with fee rates at or above the last edge.
+var MempoolFeeRateBuckets = []float64{0.0001, 0.0002, 0.0005, 0.001, 0.002,
+	0.005, 0.01, 0.02, 0.05, 0.1}
+
+// MempoolFeeRateBucket returns the index of the MempoolFeeRateBuckets
+// histogram bucket for the given fee rate in FNO/kB.
+func MempoolFeeRateBucket(feeRate float64) int {
+	for i, edge := range MempoolFeeRateBuckets {
+		if feeRate < edge {
+			return i
+		}
+	}
+	return len(MempoolFeeRateBuckets)
+}
+
+// MempoolTxHistory describes when a transaction entered and left mempool.
+// RemovedTime and RemovedHeight are only set if the transaction has left
+// mempool.
+type MempoolTxHistory struct {
+	TxHash        string   `json:"txid"`
+	Type          string   `json:"type"`
+	Size          int32    `json:"size"`
+	Fees          float64  `json:"fees"`
+	FeeRate       float64  `json:"fee_rate"`
+	FirstSeen     TimeDef  `json:"first_seen"`
+	RemovedTime   *TimeDef `json:"removed_time,omitempty"`
+	RemovedHeight int64    `json:"removed_height,omitempty"`
+	Status        string   `json:"status"`
+	WaitSeconds   int64    `json:"wait_seconds,omitempty"`
+}
+
+// MempoolFeeSnapshot is a histogram of the fee rates of the non-vote
+// transactions in mempool at a point in time. Counts and Sizes have one more
+// element than Buckets, which holds the upper edges of the histogram buckets
+// in FNO/kB.
+type MempoolFeeSnapshot struct {
+	Time      TimeDef   `json:"time"`
+	Height    int64     `json:"height"`
+	NumTxs    int64     `json:"num_txs"`
+	TotalSize int64     `json:"total_size"`
+	Buckets   []float64 `json:"buckets"`
+	Counts    []uint64  `json:"counts"`
+	Sizes     []uint64  `json:"sizes"`
+}
+
 // Tx models a Fonero transaction. It is stored in a Block.
 type Tx struct {
 	//blockDbID  int64
@@ -1403,6 +1864,85 @@ type Block struct {
 	StakeVersion uint32  `json:"stakeversion"`
 	PreviousHash string  `json:"previousblockhash"`
 	ChainWork    string  `json:"chainwork"`
+	// FeeRates are the fee rate statistics of the block's transactions. It is
+	// nil for blocks stored before fee rates were introduced.
+	FeeRates *BlockFeeRates `json:"fee_rates,omitempty"`
+}
+
+// FeeRateStats are the minimum, percentiles, and maximum of the fee rates, in
+// atoms/kB, of the transactions of one kind in a block. The pth percentile is
+// the fee rate at index (Count-1)*p/100 of the sorted fee rates.
+type FeeRateStats struct {
+	Count  uint64 `json:"count"`
+	Min    uint64 `json:"min"`
+	P10    uint64 `json:"p10"`
+	P25    uint64 `json:"p25"`
+	Median uint64 `json:"median"`
+	P75    uint64 `json:"p75"`
+	P90    uint64 `json:"p90"`
+	Max    uint64 `json:"max"`
+}
+
+// numFeeRateStats is the number of values in a FeeRateStats.
+const numFeeRateStats = 8
+
+// NewFeeRateStats computes the FeeRateStats of the fee rates, which are sorted
+// in place. If there are no fee rates, nil is returned.
+func NewFeeRateStats(rates []uint64) *FeeRateStats {
+	n := len(rates)
+	if n == 0 {
+		return nil
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i] < rates[j] })
+	percentile := func(p int) uint64 {
+		return rates[(n-1)*p/100]
+	}
+	return &FeeRateStats{
+		Count:  uint64(n),
+		Min:    rates[0],
+		P10:    percentile(10),
+		P25:    percentile(25),
+		Median: percentile(50),
+		P75:    percentile(75),
+		P90:    percentile(90),
+		Max:    rates[n-1],
+	}
+}
+
+// Array returns the statistics in the order they are stored in the blocks
+// table: count, min, p10, p25, median, p75, p90, max. The array of nil
+// statistics is nil, which is stored as NULL.
+func (s *FeeRateStats) Array() UInt64Array {
+	if s == nil {
+		return nil
+	}
+	return UInt64Array{s.Count, s.Min, s.P10, s.P25, s.Median, s.P75, s.P90, s.Max}
+}
+
+// FeeRateStatsFromArray is the inverse of (*FeeRateStats).Array. If the array
+// is not of the stored length, such as a NULL array, nil is returned.
+func FeeRateStatsFromArray(a UInt64Array) *FeeRateStats {
+	if len(a) != numFeeRateStats {
+		return nil
+	}
+	return &FeeRateStats{
+		Count:  a[0],
+		Min:    a[1],
+		P10:    a[2],
+		P25:    a[3],
+		Median: a[4],
+		P75:    a[5],
+		P90:    a[6],
+		Max:    a[7],
+	}
+}
+
+// BlockFeeRates are the fee rate statistics of the regular transactions,
+// excluding the coinbase, and of the ticket purchases in a block. Either is nil
+// if the block has no such transactions.
+type BlockFeeRates struct {
+	Regular *FeeRateStats `json:"regular"`
+	Tickets *FeeRateStats `json:"tickets"`
 }
 
 type BlockDataBasic struct {
@@ -1542,6 +2082,57 @@ type AddressBalance struct {
 	TotalUnspent int64   `json:"amount_unspent"`
 	FromStake    float64 `json:"from_stake"`
 	ToStake      float64 `json:"to_stake"`
+	// Pruned is set when part of the address history has been removed from
+	// the addresses table by archival pruning. The totals above include the
+	// pruned history.
+	Pruned *AddressSummary `json:"pruned,omitempty"`
+}
+
+// XpubAddress is an address derived from an extended public key at child
+// index Index of branch Branch (0 for external, 1 for internal addresses).
+// Balance is only set for used addresses.
+type XpubAddress struct {
+	Address string          `json:"address"`
+	Branch  uint32          `json:"branch"`
+	Index   uint32          `json:"index"`
+	Used    bool            `json:"used"`
+	Balance *AddressBalance `json:"balance,omitempty"`
+}
+
+// AddressSummary holds the aggregated totals of an address' history that was
+// removed from the addresses table by archival pruning. Only spent outpoints
+// are pruned, so the summary has no unspent component.
+type AddressSummary struct {
+	NumSpent     int64   `json:"num_stxos"`
+	TotalSpent   int64   `json:"amount_spent"`
+	FromStake    int64   `json:"-"`
+	ToStake      int64   `json:"-"`
+	FirstSeen    TimeDef `json:"first_seen"`
+	LastSeen     TimeDef `json:"last_seen"`
+	PrunedBefore TimeDef `json:"pruned_before"`
+}
+
+// ErrAddressHistoryPruned is the error returned when an address balance is
+// requested as of a time before the address history was pruned.
+var ErrAddressHistoryPruned = errors.New("address history pruned")
+
+// ErrNullDataSubstringsUnindexed is the error returned when a nulldata
+// substring search is requested but the payload trigram index, which requires
+// the PostgreSQL pg_trgm extension, does not exist.
+var ErrNullDataSubstringsUnindexed = errors.New("nulldata substring searches unavailable")
+
+// HistoryTruncated checks whether any of the address history has been pruned.
+func (balance *AddressBalance) HistoryTruncated() bool {
+	return balance.Pruned != nil
+}
+
+// NumPrunedSpent is the number of spent outpoints whose funding and spending
+// rows were removed by archival pruning.
+func (balance *AddressBalance) NumPrunedSpent() int64 {
+	if balance.Pruned == nil {
+		return 0
+	}
+	return balance.Pruned.NumSpent
 }
 
 // HasStakeOutputs checks whether any of the Address tx outputs were
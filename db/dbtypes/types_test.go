@@ -155,3 +155,56 @@ func TestTimeDef_Scan(t *testing.T) {
 	}
 
 }
+
+func TestMempoolFeeRateBucket(t *testing.T) {
+	tests := []struct {
+		feeRate float64
+		want    int
+	}{
+		{0, 0},
+		{0.00005, 0},
+		{0.0001, 1},
+		{0.00015, 1},
+		{0.001, 4},
+		{0.099, 9},
+		{0.1, 10},
+		{5, 10},
+	}
+	for _, tt := range tests {
+		if got := MempoolFeeRateBucket(tt.feeRate); got != tt.want {
+			t.Errorf("MempoolFeeRateBucket(%v) = %d, want %d", tt.feeRate, got, tt.want)
+		}
+	}
+}
+
+func TestNewFeeRateStats(t *testing.T) {
+	if s := NewFeeRateStats(nil); s != nil {
+		t.Errorf("expected nil stats for no fee rates, got %+v", s)
+	}
+
+	rates := []uint64{900, 100, 500, 300, 700, 200, 800, 400, 1000, 600, 10000}
+	s := NewFeeRateStats(rates)
+	want := FeeRateStats{
+		Count:  11,
+		Min:    100,
+		P10:    200,
+		P25:    300,
+		Median: 600,
+		P75:    800,
+		P90:    1000,
+		Max:    10000,
+	}
+	if *s != want {
+		t.Errorf("got %+v, want %+v", *s, want)
+	}
+
+	if a := FeeRateStatsFromArray(s.Array()); a == nil || *a != want {
+		t.Errorf("array round trip failed: %+v", a)
+	}
+	if a := FeeRateStatsFromArray(nil); a != nil {
+		t.Errorf("expected nil stats for a NULL array, got %+v", a)
+	}
+	if a := (*FeeRateStats)(nil).Array(); a != nil {
+		t.Errorf("expected a nil array for nil stats, got %v", a)
+	}
+}
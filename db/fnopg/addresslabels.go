@@ -0,0 +1,97 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// addressLabelRegistry is the mutex-protected registry of known address
+// labels, indexed by address.
+type addressLabelRegistry struct {
+	mtx       sync.RWMutex
+	labels    *dbtypes.AddressLabels
+	byAddress map[string]*dbtypes.AddressLabel
+}
+
+// SetAddressLabels replaces the known address labels.
+func (pgb *ChainDB) SetAddressLabels(labels *dbtypes.AddressLabels) {
+	if pgb == nil {
+		return
+	}
+	byAddress := make(map[string]*dbtypes.AddressLabel, len(labels.Labels))
+	for _, label := range labels.Labels {
+		byAddress[label.Address] = label
+	}
+	pgb.addressLabels.mtx.Lock()
+	pgb.addressLabels.labels = labels
+	pgb.addressLabels.byAddress = byAddress
+	pgb.addressLabels.mtx.Unlock()
+}
+
+// AddressLabels returns the known address labels registry.
+func (pgb *ChainDB) AddressLabels() *dbtypes.AddressLabels {
+	pgb.addressLabels.mtx.RLock()
+	defer pgb.addressLabels.mtx.RUnlock()
+	if pgb.addressLabels.labels == nil {
+		return &dbtypes.AddressLabels{Labels: []*dbtypes.AddressLabel{}}
+	}
+	return pgb.addressLabels.labels
+}
+
+// AddressLabel returns the label of the address, or nil if it is unlabeled.
+func (pgb *ChainDB) AddressLabel(address string) *dbtypes.AddressLabel {
+	pgb.addressLabels.mtx.RLock()
+	defer pgb.addressLabels.mtx.RUnlock()
+	return pgb.addressLabels.byAddress[address]
+}
+
+// SearchAddressLabels finds the known addresses whose label contains the
+// query, ignoring case, or whose address is the query.
+func (pgb *ChainDB) SearchAddressLabels(query string) []*dbtypes.AddressLabel {
+	return searchAddressLabels(pgb.AddressLabels().Labels, query)
+}
+
+// MatchAddressLabels finds the known addresses whose label is the label,
+// ignoring case and surrounding space.
+func (pgb *ChainDB) MatchAddressLabels(label string) []*dbtypes.AddressLabel {
+	return matchAddressLabels(pgb.AddressLabels().Labels, label)
+}
+
+// matchAddressLabels filters the labels to those that are the label, ignoring
+// case and surrounding space.
+func matchAddressLabels(labels []*dbtypes.AddressLabel, label string) []*dbtypes.AddressLabel {
+	matches := []*dbtypes.AddressLabel{}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return matches
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Label, label) {
+			matches = append(matches, l)
+		}
+	}
+	return matches
+}
+
+// searchAddressLabels filters the labels to those whose label contains the
+// query, ignoring case, or whose address is the query.
+func searchAddressLabels(labels []*dbtypes.AddressLabel, query string) []*dbtypes.AddressLabel {
+	matches := []*dbtypes.AddressLabel{}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return matches
+	}
+	lowerQuery := strings.ToLower(query)
+	for _, label := range labels {
+		if label.Address == query ||
+			strings.Contains(strings.ToLower(label.Label), lowerQuery) {
+			matches = append(matches, label)
+		}
+	}
+	return matches
+}
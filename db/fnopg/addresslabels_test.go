@@ -0,0 +1,78 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestSearchAddressLabels(t *testing.T) {
+	labels := []*dbtypes.AddressLabel{
+		{Address: "Dsa", Label: "Exchange A Hot Wallet", Category: dbtypes.LabelExchange},
+		{Address: "Dsb", Label: "Exchange B", Category: dbtypes.LabelExchange},
+		{Address: "Dsc", Label: "Pool C", Category: dbtypes.LabelPool},
+	}
+	tests := []struct {
+		query     string
+		addresses []string
+	}{
+		{"exchange", []string{"Dsa", "Dsb"}},
+		{" HOT wallet ", []string{"Dsa"}},
+		{"Dsc", []string{"Dsc"}},
+		{"dsc", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		matches := searchAddressLabels(labels, test.query)
+		if matches == nil {
+			t.Fatalf("searchAddressLabels(%q) returned nil", test.query)
+		}
+		if len(matches) != len(test.addresses) {
+			t.Errorf("searchAddressLabels(%q): got %d matches, want %d", test.query,
+				len(matches), len(test.addresses))
+			continue
+		}
+		for i, label := range matches {
+			if label.Address != test.addresses[i] {
+				t.Errorf("searchAddressLabels(%q): got %s, want %s", test.query,
+					label.Address, test.addresses[i])
+			}
+		}
+	}
+}
+
+func TestMatchAddressLabels(t *testing.T) {
+	labels := []*dbtypes.AddressLabel{
+		{Address: "Dsa", Label: "Exchange A", Category: dbtypes.LabelExchange},
+		{Address: "Dsb", Label: "Exchange A", Category: dbtypes.LabelExchange},
+		{Address: "Dsc", Label: "Exchange AB", Category: dbtypes.LabelExchange},
+	}
+	tests := []struct {
+		label     string
+		addresses []string
+	}{
+		{" exchange a ", []string{"Dsa", "Dsb"}},
+		{"EXCHANGE AB", []string{"Dsc"}},
+		{"exchange", nil},
+		{"Dsa", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		matches := matchAddressLabels(labels, test.label)
+		if matches == nil {
+			t.Fatalf("matchAddressLabels(%q) returned nil", test.label)
+		}
+		var addresses []string
+		for _, label := range matches {
+			addresses = append(addresses, label.Address)
+		}
+		if !reflect.DeepEqual(addresses, test.addresses) {
+			t.Errorf("matchAddressLabels(%q): got %v, want %v", test.label,
+				addresses, test.addresses)
+		}
+	}
+}
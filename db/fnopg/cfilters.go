@@ -0,0 +1,141 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/fonero-project/fnod/chaincfg/chainhash"
+	"github.com/fonero-project/fnod/gcs"
+	"github.com/fonero-project/fnod/gcs/blockcf"
+	"github.com/fonero-project/fnod/wire"
+	apitypes "github.com/fonero-project/fnodata/api/types"
+)
+
+// StoreBlockFilter builds the regular committed filter of the block, and
+// stores it with its filter header. The filter header chain is extended from
+// the filter header of the previous block. If the previous block's filter
+// header is not known, such as after the filters of earlier blocks failed to
+// be stored, the missing headers are first computed from blocks fetched from
+// the node. Without a node, the filter is stored without a header until the
+// filters are rebuilt (see rebuilddb2's --cfilters option).
+func (pgb *ChainDB) StoreBlockFilter(msgBlock *wire.MsgBlock) error {
+	prevHash := msgBlock.Header.PrevBlock
+	prevHeader, err := pgb.filterHeader(prevHash)
+	if err != nil {
+		return fmt.Errorf("failed to get previous filter header: %v", err)
+	}
+	if prevHeader == nil {
+		log.Debugf("Filter header of block %v unknown. Storing filter of block "+
+			"%v without a header.", prevHash, msgBlock.BlockHash())
+	}
+
+	_, err = storeBlockFilterWithHeader(pgb.db, msgBlock, prevHeader)
+	return err
+}
+
+// filterHeader gets the filter header of the block with the given hash. If
+// the filter headers of the block and its most recent ancestors are not
+// stored, they are computed from blocks fetched from the node and stored. A
+// nil header is returned if the header is unknown and there is no node to
+// fetch the blocks from.
+func (pgb *ChainDB) filterHeader(hash chainhash.Hash) (*chainhash.Hash, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	// Walk back through the stored blocks to the most recent one with a known
+	// filter header, or to the genesis block.
+	var missing []chainhash.Hash
+	header := new(chainhash.Hash)
+	for hash != zeroHash {
+		headerStr, err := retrieveBlockFilterHeader(ctx, pgb.db, hash.String())
+		if err != nil && err != sql.ErrNoRows {
+			return nil, pgb.replaceCancelError(err)
+		}
+		if headerStr != "" {
+			if header, err = chainhash.NewHashFromStr(headerStr); err != nil {
+				return nil, fmt.Errorf("invalid filter header: %v", err)
+			}
+			break
+		}
+
+		missing = append(missing, hash)
+		prevHashStr, err := RetrievePreviousHashByBlockHash(ctx, pgb.db, hash.String())
+		if err == sql.ErrNoRows {
+			// The chain of stored blocks is broken.
+			return nil, nil
+		}
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+		prevHash, err := chainhash.NewHashFromStr(prevHashStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous block hash: %v", err)
+		}
+		hash = *prevHash
+	}
+	if len(missing) == 0 {
+		return header, nil
+	}
+	if pgb.bg == nil {
+		return nil, nil
+	}
+
+	log.Infof("Computing %d missing filter headers.", len(missing))
+	for i := len(missing) - 1; i >= 0; i-- {
+		msgBlock, err := pgb.bg.GetBlock(&missing[i])
+		if err != nil {
+			return nil, fmt.Errorf("GetBlock(%v): %v", missing[i], err)
+		}
+		header, err = storeBlockFilterWithHeader(pgb.db, msgBlock, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return header, nil
+}
+
+// storeBlockFilterWithHeader builds the regular committed filter of the
+// block, and stores it with the filter header that extends prevHeader. The
+// filter is stored without a header if prevHeader is nil. The stored header is
+// returned.
+func storeBlockFilterWithHeader(db *sql.DB, msgBlock *wire.MsgBlock, prevHeader *chainhash.Hash) (*chainhash.Hash, error) {
+	filter, err := blockcf.Regular(msgBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build filter: %v", err)
+	}
+
+	var header *chainhash.Hash
+	var headerStr sql.NullString
+	if prevHeader != nil {
+		h := gcs.MakeHeaderForFilter(filter, prevHeader)
+		header = &h
+		headerStr = sql.NullString{String: h.String(), Valid: true}
+	}
+
+	err = storeBlockFilter(db, msgBlock.BlockHash().String(),
+		msgBlock.Header.Height, filter.NBytes(), headerStr)
+	return header, err
+}
+
+// BlockFilter retrieves the committed filter and filter header of the block
+// with the given hash. sql.ErrNoRows is returned if the block's filter is not
+// stored.
+func (pgb *ChainDB) BlockFilter(blockHash string) (*apitypes.BlockFilter, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	bf, err := retrieveBlockFilter(ctx, pgb.db, blockHash)
+	return bf, pgb.replaceCancelError(err)
+}
+
+// FilterHeaders retrieves the filter headers of the main chain blocks in the
+// height range [start, end].
+func (pgb *ChainDB) FilterHeaders(start, end int64) ([]*apitypes.FilterHeader, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	headers, err := retrieveMainchainFilterHeaders(ctx, pgb.db, start, end)
+	return headers, pgb.replaceCancelError(err)
+}
@@ -85,6 +85,12 @@ func (p *ChainMonitor) switchToSideChain(reorgData *txhelpers.ReorgData) (int32,
 			mainTip, commonAncestorHeight))
 	}
 
+	// The UTXO age distributions measured after the common ancestor are
+	// computed again from the new main chain.
+	if err = p.db.RewindUTXOAges(commonAncestorHeight); err != nil {
+		log.Errorf("RewindUTXOAges: %v", err)
+	}
+
 	// Connect blocks in side chain onto main chain
 	log.Debugf("Connecting %d blocks", len(newChain))
 	currentHeight := commonAncestorHeight + 1
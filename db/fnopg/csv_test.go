@@ -0,0 +1,48 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestMakeCsvAgendaVotes(t *testing.T) {
+	header := []string{"height", "yes", "abstain", "no", "total"}
+	tests := []struct {
+		name string
+		avc  *dbtypes.AgendaVoteChoices
+		want [][]string
+	}{
+		{
+			name: "nil",
+			avc:  nil,
+			want: [][]string{header},
+		},
+		{
+			name: "ok",
+			avc: &dbtypes.AgendaVoteChoices{
+				Height:  []uint64{4096, 4097},
+				Yes:     []uint64{3, 5},
+				Abstain: []uint64{1, 0},
+				No:      []uint64{1, 0},
+				Total:   []uint64{5, 5},
+			},
+			want: [][]string{
+				header,
+				{"4096", "3", "1", "1", "5"},
+				{"4097", "5", "0", "0", "5"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MakeCsvAgendaVotes(tt.avc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MakeCsvAgendaVotes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+
+	"github.com/fonero-project/fnod/wire"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// StoreBlockFeeRates computes and stores the fee rate statistics of a block
+// that is already in the blocks table. StoreBlock stores the fee rates of new
+// blocks, so this is only needed for blocks stored before the fee rates were
+// introduced (see rebuilddb2's --feerates option).
+func (pgb *ChainDB) StoreBlockFeeRates(msgBlock *wire.MsgBlock) error {
+	return updateBlockFeeRates(pgb.db, msgBlock.BlockHash().String(),
+		dbtypes.MsgBlockFeeRates(msgBlock))
+}
+
+// BlockFeeRates retrieves the fee rate statistics of the block with the given
+// hash. sql.ErrNoRows is returned if the block is not stored, and nil
+// statistics if they were not computed for the block.
+func (pgb *ChainDB) BlockFeeRates(hash string) (*dbtypes.BlockFeeRates, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	feeRates, err := retrieveBlockFeeRates(ctx, pgb.db, hash)
+	return feeRates, pgb.replaceCancelError(err)
+}
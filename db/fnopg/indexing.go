@@ -324,6 +324,82 @@ func DeindexProposalVotesTableOnProposalsID(db *sql.DB) (err error) {
 	return
 }
 
+// nulldata table indexes
+
+func IndexNullDataTableOnPayloadHex(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexNullDataTableOnPayloadHex)
+	return
+}
+
+func DeindexNullDataTableOnPayloadHex(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexNullDataTableOnPayloadHex)
+	return
+}
+
+// IndexNullDataTableOnPayloadTrgm creates the trigram index of the nulldata
+// payloads. The pg_trgm extension is created if needed. If the database user
+// cannot create the extension, a warning is logged and the index is skipped,
+// and the nulldata substring searches are disabled until the extension is
+// created and the index is built.
+func IndexNullDataTableOnPayloadTrgm(db *sql.DB) (err error) {
+	if _, err = db.Exec(internal.CreateTrigramExtension); err != nil {
+		log.Warnf("Unable to create the pg_trgm extension, so nulldata substring "+
+			"searches are disabled: %v", err)
+		return nil
+	}
+	_, err = db.Exec(internal.IndexNullDataTableOnPayloadTrgm)
+	return
+}
+
+func DeindexNullDataTableOnPayloadTrgm(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexNullDataTableOnPayloadTrgm)
+	return
+}
+
+// address_summary table indexes
+
+func IndexAddressSummaryOnPrunedBefore(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexAddressSummaryOnPrunedBefore)
+	return
+}
+
+func DeindexAddressSummaryOnPrunedBefore(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexAddressSummaryOnPrunedBefore)
+	return
+}
+
+// mempool_txs and mempool_fee_snapshots table indexes
+
+func IndexMempoolTxsTableOnRemovalReason(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexMempoolTxsTableOnRemovalReason)
+	return
+}
+
+func DeindexMempoolTxsTableOnRemovalReason(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexMempoolTxsTableOnRemovalReason)
+	return
+}
+
+func IndexMempoolTxsTableOnRemovedTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexMempoolTxsTableOnRemovedTime)
+	return
+}
+
+func DeindexMempoolTxsTableOnRemovedTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexMempoolTxsTableOnRemovedTime)
+	return
+}
+
+func IndexMempoolFeeSnapshotsTableOnTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexMempoolFeeSnapshotsTableOnTime)
+	return
+}
+
+func DeindexMempoolFeeSnapshotsTableOnTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexMempoolFeeSnapshotsTableOnTime)
+	return
+}
+
 // Delete duplicates
 
 func (pgb *ChainDB) DeleteDuplicateVins() (int64, error) {
@@ -449,6 +525,18 @@ func (pgb *ChainDB) DeindexAll() error {
 
 		// proposal votes table
 		{DeindexProposalVotesTableOnProposalsID},
+
+		// nulldata table
+		{DeindexNullDataTableOnPayloadHex},
+		{DeindexNullDataTableOnPayloadTrgm},
+
+		// address_summary table
+		{DeindexAddressSummaryOnPrunedBefore},
+
+		// mempool_txs and mempool_fee_snapshots tables
+		{DeindexMempoolTxsTableOnRemovalReason},
+		{DeindexMempoolTxsTableOnRemovedTime},
+		{DeindexMempoolFeeSnapshotsTableOnTime},
 	}
 
 	var err error
@@ -515,6 +603,18 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 
 		// Proposals votes table
 		{Msg: "Proposals votes table on Proposals ID", IndexFunc: IndexProposalVotesTableOnProposalsID},
+
+		// nulldata table
+		{Msg: "nulldata table on hex payload", IndexFunc: IndexNullDataTableOnPayloadHex},
+		{Msg: "nulldata table on hex payload trigrams", IndexFunc: IndexNullDataTableOnPayloadTrgm},
+
+		// address_summary table
+		{Msg: "address_summary table on pruned before time", IndexFunc: IndexAddressSummaryOnPrunedBefore},
+
+		// mempool_txs and mempool_fee_snapshots tables
+		{Msg: "mempool_txs table on removal reason", IndexFunc: IndexMempoolTxsTableOnRemovalReason},
+		{Msg: "mempool_txs table on removed time", IndexFunc: IndexMempoolTxsTableOnRemovedTime},
+		{Msg: "mempool_fee_snapshots table on time", IndexFunc: IndexMempoolFeeSnapshotsTableOnTime},
 	}
 
 	for _, val := range allIndexes {
@@ -74,6 +74,122 @@ const (
 		` ON addresses(tx_hash);`
 	DeindexAddressTableOnTxHash = `DROP INDEX ` + IndexOfAddressTableOnTx + `;`
 
+	// CreateAddressSummaryTable creates the address_summary table, which holds
+	// the aggregated totals of address history removed by archival pruning.
+	CreateAddressSummaryTable = `CREATE TABLE IF NOT EXISTS address_summary (
+		address TEXT PRIMARY KEY,
+		num_spent INT8,
+		amount_spent INT8,
+		from_stake INT8,
+		to_stake INT8,
+		first_seen TIMESTAMPTZ,
+		last_seen TIMESTAMPTZ,
+		pruned_before TIMESTAMPTZ
+	);`
+
+	// SelectAddressSummary gets the summary of the pruned history of an
+	// address. The pruned_before of each summary is the cutoff time of the
+	// pruning that last updated it, while the history of every address is
+	// pruned through the latest cutoff time, so that is selected instead.
+	SelectAddressSummary = `SELECT num_spent, amount_spent, from_stake, to_stake,
+			first_seen, last_seen,
+			(SELECT MAX(pruned_before) FROM address_summary)
+		FROM address_summary
+		WHERE address = $1;`
+
+	// PruneAddressHistory deletes the spending rows of the addresses table
+	// with block_time before $1, the funding rows and vouts of the outpoints
+	// they spend, and adds the totals of the deleted rows to address_summary.
+	// The vout_db_ids of the transactions are left intact, so the ids of the
+	// deleted vouts keep their positions. Unspent outpoints are never removed,
+	// so the balance computed from the remaining addresses rows plus
+	// address_summary does not change. Deleted rows that are not
+	// valid_mainchain are not summarized. The number of address summaries
+	// created or updated and the number of deleted rows that were not
+	// valid_mainchain are returned.
+	PruneAddressHistory = `WITH spending AS (
+			DELETE FROM addresses
+			WHERE is_funding = FALSE AND block_time < $1
+			RETURNING address, tx_hash, matching_tx_hash, value, block_time,
+				tx_type, valid_mainchain
+		), funding AS (
+			DELETE FROM addresses
+			USING spending
+			WHERE addresses.is_funding
+				AND addresses.address = spending.address
+				AND addresses.tx_hash = spending.matching_tx_hash
+				AND addresses.matching_tx_hash = spending.tx_hash
+			RETURNING addresses.address, addresses.value, addresses.block_time,
+				addresses.tx_type, addresses.valid_mainchain,
+				addresses.tx_vin_vout_row_id
+		), pruned_vouts AS (
+			DELETE FROM vouts
+			WHERE id IN (SELECT tx_vin_vout_row_id FROM funding)
+		), spent AS (
+			SELECT address, COUNT(*) AS num, SUM(value) AS amount,
+				SUM(CASE WHEN tx_type = 0 THEN 0 ELSE value END) AS to_stake,
+				MAX(block_time) AS last_seen
+			FROM spending
+			WHERE valid_mainchain
+			GROUP BY address
+		), funded AS (
+			SELECT address,
+				SUM(CASE WHEN tx_type = 0 THEN 0 ELSE value END) AS from_stake,
+				MIN(block_time) AS first_seen
+			FROM funding
+			WHERE valid_mainchain
+			GROUP BY address
+		), summarized AS (
+			INSERT INTO address_summary AS s (address, num_spent, amount_spent,
+				from_stake, to_stake, first_seen, last_seen, pruned_before)
+			SELECT spent.address, spent.num, spent.amount,
+				COALESCE(funded.from_stake, 0), spent.to_stake,
+				COALESCE(funded.first_seen, spent.last_seen), spent.last_seen, $1
+			FROM spent
+			LEFT JOIN funded ON funded.address = spent.address
+			ON CONFLICT (address) DO UPDATE SET
+				num_spent = s.num_spent + EXCLUDED.num_spent,
+				amount_spent = s.amount_spent + EXCLUDED.amount_spent,
+				from_stake = s.from_stake + EXCLUDED.from_stake,
+				to_stake = s.to_stake + EXCLUDED.to_stake,
+				first_seen = LEAST(s.first_seen, EXCLUDED.first_seen),
+				last_seen = GREATEST(s.last_seen, EXCLUDED.last_seen),
+				pruned_before = EXCLUDED.pruned_before
+			RETURNING address
+		)
+		SELECT (SELECT COUNT(*) FROM summarized),
+			(SELECT COUNT(*) FROM spending WHERE NOT valid_mainchain) +
+			(SELECT COUNT(*) FROM funding WHERE NOT valid_mainchain);`
+
+	// IndexAddressSummaryOnPrunedBefore indexes the address summaries on
+	// the cutoff time of the pruning that last updated them, so that the
+	// latest cutoff time is found quickly.
+	IndexAddressSummaryOnPrunedBefore = `CREATE INDEX ` + IndexOfAddressSummaryOnPrunedBefore +
+		` ON address_summary(pruned_before);`
+	DeindexAddressSummaryOnPrunedBefore = `DROP INDEX ` + IndexOfAddressSummaryOnPrunedBefore + `;`
+
+	// SelectAddressReceivedSentAtTime gets the total amounts received and sent
+	// by the given address in mainchain transactions in blocks with times no
+	// later than the given time.
+	SelectAddressReceivedSentAtTime = `SELECT
+			COALESCE(SUM(CASE WHEN is_funding THEN value ELSE 0 END), 0) AS received,
+			COALESCE(SUM(CASE WHEN is_funding THEN 0 ELSE value END), 0) AS sent
+		FROM addresses
+		WHERE address = $1 AND valid_mainchain = TRUE AND block_time <= $2;`
+
+	// SelectAddressReceivedSentAtHeight gets the total amounts received and
+	// sent by the given address in valid mainchain transactions in blocks with
+	// heights no greater than the given height.
+	SelectAddressReceivedSentAtHeight = `SELECT
+			COALESCE(SUM(CASE WHEN addresses.is_funding THEN addresses.value ELSE 0 END), 0) AS received,
+			COALESCE(SUM(CASE WHEN addresses.is_funding THEN 0 ELSE addresses.value END), 0) AS sent
+		FROM addresses
+		INNER JOIN transactions
+			ON addresses.tx_hash = transactions.tx_hash
+				AND is_mainchain AND is_valid
+		WHERE address = $1 AND valid_mainchain = TRUE
+			AND transactions.block_height <= $2;`
+
 	// SelectSpendingTxsByPrevTx = `SELECT id, tx_hash, tx_index, prev_tx_index FROM vins WHERE prev_tx_hash=$1;`
 	// SelectSpendingTxByPrevOut = `SELECT id, tx_hash, tx_index FROM vins WHERE prev_tx_hash=$1 AND prev_tx_index=$2;`
 	// SelectFundingTxsByTx      = `SELECT id, prev_tx_hash FROM vins WHERE tx_hash=$1;`
@@ -107,6 +223,35 @@ const (
 		WHERE address = ANY($1) AND valid_mainchain
 		ORDER BY block_time DESC, tx_hash ASC;`
 
+	// SelectUsedAddresses selects the given addresses that have mainchain
+	// history, including history pruned into the address summaries.
+	SelectUsedAddresses = `SELECT address FROM addresses
+		WHERE address = ANY($1) AND valid_mainchain
+		UNION
+		SELECT address FROM address_summary
+		WHERE address = ANY($1);`
+
+	// SelectAddressesMergedTxns merges the rows of the addresses $1 for each
+	// valid mainchain transaction, getting the amounts sent from and received
+	// by the addresses and the addresses involved. The transactions are
+	// ordered by block time, most recent first, with a limit of $2 and an
+	// offset of $3.
+	SelectAddressesMergedTxns = `SELECT tx_hash, block_time,
+			SUM(CASE WHEN is_funding THEN 0 ELSE value END),
+			SUM(CASE WHEN is_funding THEN value ELSE 0 END),
+			array_agg(DISTINCT address)
+		FROM addresses
+		WHERE address = ANY($1) AND valid_mainchain
+		GROUP BY tx_hash, block_time
+		ORDER BY block_time DESC, tx_hash ASC
+		LIMIT $2 OFFSET $3;`
+
+	// SelectAddressesMergedTxnsCount counts the valid mainchain transactions
+	// of the addresses $1.
+	SelectAddressesMergedTxnsCount = `SELECT COUNT(DISTINCT tx_hash)
+		FROM addresses
+		WHERE address = ANY($1) AND valid_mainchain;`
+
 	// selectAddressTimeGroupingCount return the count of record groups,
 	// where grouping is done by a specified time interval, for an addresss.
 	selectAddressTimeGroupingCount = `SELECT COUNT(DISTINCT %s) FROM addresses WHERE address=$1;`
@@ -34,7 +34,9 @@ const (
 		difficulty FLOAT8,
 		stake_version INT4,
 		previous_hash TEXT,
-		chainwork TEXT
+		chainwork TEXT,
+		fee_rates INT8[],
+		ticket_fee_rates INT8[]
 	);`
 
 	// Block inserts. is_valid refers to blocks that have been validated by
@@ -48,12 +50,14 @@ const (
 		numtx, num_rtx, tx, txDbIDs, num_stx, stx, stxDbIDs,
 		time, nonce, vote_bits, voters,
 		fresh_stake, revocations, pool_size, bits, sbits,
-		difficulty, stake_version, previous_hash, chainwork)
+		difficulty, stake_version, previous_hash, chainwork,
+		fee_rates, ticket_fee_rates)
 	VALUES ($1, $2, $3, $4, $5, $6,
 		$7, $8, %s, %s, $9, %s, %s,
 		$10, $11, $12, $13,
 		$14, $15, $16, $17, $18,
-		$19, $20, $21, $22) `
+		$19, $20, $21, $22,
+		$23, $24) `
 
 	// InsertBlockRow inserts a new block row without checking for unique index
 	// conflicts. This should only be used before the unique indexes are created
@@ -63,7 +67,8 @@ const (
 	// UpsertBlockRow is an upsert (insert or update on conflict), returning
 	// the inserted/updated block row id.
 	UpsertBlockRow = insertBlockRow + `ON CONFLICT (hash) DO UPDATE
-		SET is_valid = $4, is_mainchain = $5 RETURNING id;`
+		SET is_valid = $4, is_mainchain = $5,
+			fee_rates = $23, ticket_fee_rates = $24 RETURNING id;`
 
 	// InsertBlockRowOnConflictDoNothing allows an INSERT with a DO NOTHING on
 	// conflict with blocks' unique tx index, while returning the row id of
@@ -220,6 +225,39 @@ const (
 		AND height > $1
 		ORDER BY height;`
 
+	// Fee rate statistics. The fee_rates and ticket_fee_rates columns are
+	// arrays of count, min, p10, p25, median, p75, p90, and max fee rate in
+	// atoms/kB, which are NULL for blocks stored before the columns were added
+	// until they are backfilled by rebuilddb2.
+
+	// UpdateBlockFeeRates sets the fee rate statistics of the block with hash
+	// $1.
+	UpdateBlockFeeRates = `UPDATE blocks SET fee_rates = $2, ticket_fee_rates = $3
+		WHERE hash = $1;`
+
+	// SelectBlockFeeRates gets the fee rate statistics of the block with hash
+	// $1.
+	SelectBlockFeeRates = `SELECT fee_rates, ticket_fee_rates
+		FROM blocks
+		WHERE hash = $1;`
+
+	// SelectBlocksFeeRates gets the fee rate statistics of the main chain
+	// blocks above height $1.
+	SelectBlocksFeeRates = `SELECT height, fee_rates, ticket_fee_rates
+		FROM blocks
+		WHERE is_mainchain
+		AND height > $1
+		ORDER BY height;`
+
+	// SelectBlocksFeeRatesCount gets the total number of regular transactions
+	// and ticket purchases described by the fee rate statistics of the main
+	// chain blocks at or below height $1.
+	SelectBlocksFeeRatesCount = `SELECT COALESCE(SUM(fee_rates[1]), 0)::INT8
+			+ COALESCE(SUM(ticket_fee_rates[1]), 0)::INT8
+		FROM blocks
+		WHERE is_mainchain
+		AND height <= $1;`
+
 	// TODO: index block_chain where needed
 )
 
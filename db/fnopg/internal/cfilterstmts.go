@@ -0,0 +1,54 @@
+package internal
+
+// The following statements are for the block_filters table, which stores the
+// committed filters used by light wallets to sync without revealing their
+// addresses.
+
+const (
+	// CreateBlockFiltersTable creates the block_filters table. The filter is
+	// the serialized regular committed filter of the block, and the header
+	// commits to the filter and to the header of the previous block's filter.
+	// The header is NULL when the previous block's filter header is unknown.
+	CreateBlockFiltersTable = `CREATE TABLE IF NOT EXISTS block_filters (
+		block_hash TEXT PRIMARY KEY,
+		height INT4,
+		filter BYTEA,
+		header TEXT
+	);`
+
+	// UpsertBlockFilter inserts the filter and filter header of a block, or
+	// replaces them if the block's filter was already stored.
+	UpsertBlockFilter = `INSERT INTO block_filters (block_hash, height, filter, header)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (block_hash) DO UPDATE
+		SET filter = $3, header = $4;`
+
+	// SelectBlockFilter gets the filter and filter header of a block.
+	SelectBlockFilter = `SELECT height, filter, header FROM block_filters
+		WHERE block_hash = $1;`
+
+	// SelectBlockFilterHeader gets the filter header of a block.
+	SelectBlockFilterHeader = `SELECT header FROM block_filters
+		WHERE block_hash = $1;`
+
+	// SelectMainchainBlockFilterHeaders gets the hashes of the main chain
+	// blocks, in order of height, with their filter headers. The header is
+	// NULL if the block's filter or filter header is not stored.
+	SelectMainchainBlockFilterHeaders = `SELECT blocks.hash, block_filters.header
+		FROM blocks
+		LEFT JOIN block_filters ON block_filters.block_hash = blocks.hash
+		WHERE blocks.is_mainchain
+		ORDER BY blocks.height;`
+
+	// DeleteBlockFilter deletes the filter of a block.
+	DeleteBlockFilter = `DELETE FROM block_filters WHERE block_hash = $1;`
+
+	// SelectMainchainFilterHeaders gets the filter headers of the main chain
+	// blocks in the height range [$1, $2].
+	SelectMainchainFilterHeaders = `SELECT blocks.height, blocks.hash,
+			COALESCE(block_filters.header, '')
+		FROM blocks
+		JOIN block_filters ON block_filters.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ORDER BY blocks.height;`
+)
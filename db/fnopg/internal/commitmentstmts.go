@@ -0,0 +1,58 @@
+package internal
+
+// The following statements are for the ticket_commitments table, which holds
+// the decoded commitment outputs of ticket purchases.
+
+const (
+	// CreateTicketCommitmentsTable creates the ticket_commitments table. The
+	// amount contributed to the ticket by the owner of the reward address and
+	// the fee limits of votes and revocations are in atoms. The unique
+	// constraint leads with reward_address for lookups by reward address.
+	CreateTicketCommitmentsTable = `CREATE TABLE IF NOT EXISTS ticket_commitments (
+		id SERIAL8 PRIMARY KEY,
+		ticket_hash TEXT NOT NULL,
+		tx_index INT4 NOT NULL,
+		reward_address TEXT NOT NULL,
+		amount INT8,
+		vote_fee_limit INT8,
+		revoke_fee_limit INT8,
+		UNIQUE (reward_address, ticket_hash, tx_index)
+	);`
+
+	// InsertTicketCommitment inserts a ticket commitment. Commitments of
+	// tickets already stored from another block are ignored.
+	InsertTicketCommitment = `INSERT INTO ticket_commitments (ticket_hash,
+			tx_index, reward_address, amount, vote_fee_limit, revoke_fee_limit)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (reward_address, ticket_hash, tx_index) DO NOTHING;`
+
+	// SelectTicketCommitmentVouts gets the commitment output scripts of all of
+	// the tickets in the tickets table, to populate the ticket_commitments
+	// table.
+	SelectTicketCommitmentVouts = `SELECT DISTINCT vouts.tx_hash, vouts.tx_index, vouts.pkscript
+		FROM tickets
+		JOIN vouts ON vouts.tx_hash = tickets.tx_hash
+		WHERE vouts.tx_index % 2 = 1;`
+
+	// SelectTicketCommitmentsByAddress gets the commitments of main chain
+	// tickets to reward address $1, most recent first, with up to $2
+	// commitments, skipping $3.
+	SelectTicketCommitmentsByAddress = `SELECT ticket_commitments.ticket_hash,
+			ticket_commitments.tx_index, tickets.block_height, tickets.price,
+			ticket_commitments.amount, ticket_commitments.vote_fee_limit,
+			ticket_commitments.revoke_fee_limit, tickets.spend_type,
+			tickets.pool_status
+		FROM ticket_commitments
+		JOIN tickets ON tickets.tx_hash = ticket_commitments.ticket_hash
+		WHERE ticket_commitments.reward_address = $1 AND tickets.is_mainchain
+		ORDER BY tickets.block_height DESC, ticket_commitments.ticket_hash,
+			ticket_commitments.tx_index
+		LIMIT $2 OFFSET $3;`
+
+	// SelectTicketCommitmentsCountByAddress gets the number of commitments of
+	// main chain tickets to reward address $1.
+	SelectTicketCommitmentsCountByAddress = `SELECT COUNT(*)
+		FROM ticket_commitments
+		JOIN tickets ON tickets.tx_hash = ticket_commitments.ticket_hash
+		WHERE ticket_commitments.reward_address = $1 AND tickets.is_mainchain;`
+)
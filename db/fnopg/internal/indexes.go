@@ -62,6 +62,21 @@ const (
 	// proposal votes table
 
 	IndexOfProposalVotesTableOnProposalsID = "uix_proposal_votes"
+
+	// nulldata table
+
+	IndexOfNullDataTableOnPayloadHex  = "uix_nulldata_payload_hex"
+	IndexOfNullDataTableOnPayloadTrgm = "uix_nulldata_payload_trgm"
+
+	// address_summary table
+
+	IndexOfAddressSummaryOnPrunedBefore = "uix_address_summary_pruned_before"
+
+	// mempool_txs and mempool_fee_snapshots tables
+
+	IndexOfMempoolTxsTableOnRemovalReason = "uix_mempool_txs_removal_reason"
+	IndexOfMempoolTxsTableOnRemovedTime   = "uix_mempool_txs_removed_time"
+	IndexOfMempoolFeeSnapshotsTableOnTime = "uix_mempool_fee_snapshots_time"
 )
 
 // AddressesIndexNames are the names of the indexes on the addresses table.
@@ -97,4 +112,10 @@ var IndexDescriptions = map[string]string{
 	IndexOfAgendaVotesTableOnRowIDs:        "agenda_votes on votes table row ID and agendas table row ID",
 	IndexOfProposalsTableOnToken:           "proposals on token and time",
 	IndexOfProposalVotesTableOnProposalsID: "proposal_votes on proposals row ID",
+	IndexOfNullDataTableOnPayloadHex:       "nulldata on hex payload",
+	IndexOfNullDataTableOnPayloadTrgm:      "nulldata on hex payload trigrams",
+	IndexOfAddressSummaryOnPrunedBefore:    "address_summary on pruned before time",
+	IndexOfMempoolTxsTableOnRemovalReason:  "mempool_txs on removal reason",
+	IndexOfMempoolTxsTableOnRemovedTime:    "mempool_txs on removed time",
+	IndexOfMempoolFeeSnapshotsTableOnTime:  "mempool_fee_snapshots on time",
 }
@@ -0,0 +1,132 @@
+package internal
+
+// The following statements are for the mempool_txs and mempool_fee_snapshots
+// tables, which record the history of the node's mempool.
+
+const (
+	// mempool_txs table
+
+	// CreateMempoolTxsTable creates the mempool_txs table, which records when
+	// each transaction entered and left mempool, and why it left. The previous
+	// outpoints spent by the transaction are kept to identify double spends.
+	CreateMempoolTxsTable = `CREATE TABLE IF NOT EXISTS mempool_txs (
+		id SERIAL8 PRIMARY KEY,
+		tx_hash TEXT NOT NULL UNIQUE,
+		tx_type TEXT,
+		size INT4,
+		fees FLOAT8,
+		fee_rate FLOAT8,
+		first_seen TIMESTAMPTZ,
+		removed_time TIMESTAMPTZ,
+		removed_height INT4,
+		removal_reason INT2,
+		prev_tx_hashes TEXT[],
+		prev_tx_indexes INT8[]
+	);`
+
+	// UpsertMempoolTx inserts a new mempool transaction. A transaction that
+	// was previously removed from mempool, such as a transaction from a block
+	// that was orphaned in a reorganization, is marked as pending again ($9).
+	UpsertMempoolTx = `INSERT INTO mempool_txs (tx_hash, tx_type, size, fees,
+			fee_rate, first_seen, prev_tx_hashes, prev_tx_indexes, removal_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tx_hash) DO UPDATE
+		SET removed_time = NULL, removed_height = NULL, removal_reason = $9
+		WHERE mempool_txs.removal_reason != $9;`
+
+	// SetMempoolTxsRemoved marks the pending ($7) transactions that are not in
+	// the current mempool ($1) as removed at time $2 and height $3. The reason
+	// is mined ($4) if the transaction is in a main chain block, double-spent
+	// ($5) if a main chain transaction spends any of its previous outpoints, or
+	// expired ($6) otherwise.
+	SetMempoolTxsRemoved = `UPDATE mempool_txs
+		SET removed_time = $2, removed_height = $3,
+			removal_reason = CASE
+				WHEN EXISTS (SELECT 1 FROM transactions
+					WHERE transactions.tx_hash = mempool_txs.tx_hash
+					AND transactions.is_mainchain) THEN $4
+				WHEN EXISTS (SELECT 1
+					FROM unnest(mempool_txs.prev_tx_hashes, mempool_txs.prev_tx_indexes)
+						AS prev(tx_hash, tx_index)
+					JOIN vins ON vins.prev_tx_hash = prev.tx_hash
+						AND vins.prev_tx_index = prev.tx_index
+					WHERE vins.tx_hash != mempool_txs.tx_hash
+					AND vins.is_mainchain) THEN $5
+				ELSE $6
+			END
+		WHERE removal_reason = $7 AND NOT (tx_hash = ANY($1));`
+
+	// InsertMempoolTx inserts a transaction as it enters mempool. A
+	// transaction that is already recorded is left unchanged, since its
+	// removal is only determined by UpsertMempoolTx and SetMempoolTxsRemoved
+	// with the full mempool.
+	InsertMempoolTx = `INSERT INTO mempool_txs (tx_hash, tx_type, size, fees,
+			fee_rate, first_seen, prev_tx_hashes, prev_tx_indexes, removal_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tx_hash) DO NOTHING;`
+
+	// SelectMempoolTxFeeRatesByReason gets the type, size, and fee rate of the
+	// transactions with removal reason $1, such as the pending transactions.
+	SelectMempoolTxFeeRatesByReason = `SELECT tx_type, size, fee_rate
+		FROM mempool_txs
+		WHERE removal_reason = $1;`
+
+	// DeleteMempoolTxsRemovedBefore deletes the transactions removed from
+	// mempool before $1. Pending transactions are kept.
+	DeleteMempoolTxsRemovedBefore = `DELETE FROM mempool_txs WHERE removed_time < $1;`
+
+	IndexMempoolTxsTableOnRemovalReason = `CREATE INDEX ` + IndexOfMempoolTxsTableOnRemovalReason +
+		` ON mempool_txs(removal_reason);`
+	DeindexMempoolTxsTableOnRemovalReason = `DROP INDEX ` + IndexOfMempoolTxsTableOnRemovalReason + `;`
+
+	IndexMempoolTxsTableOnRemovedTime = `CREATE INDEX ` + IndexOfMempoolTxsTableOnRemovedTime +
+		` ON mempool_txs(removed_time);`
+	DeindexMempoolTxsTableOnRemovedTime = `DROP INDEX ` + IndexOfMempoolTxsTableOnRemovedTime + `;`
+
+	SelectMempoolTxHistory = `SELECT tx_type, size, fees, fee_rate, first_seen,
+			removed_time, removed_height, removal_reason
+		FROM mempool_txs
+		WHERE tx_hash = $1;`
+
+	// mempool_fee_snapshots table
+
+	// CreateMempoolFeeSnapshotsTable creates the mempool_fee_snapshots table,
+	// which holds a histogram of the fee rates of the transactions in mempool
+	// at the time of each snapshot.
+	CreateMempoolFeeSnapshotsTable = `CREATE TABLE IF NOT EXISTS mempool_fee_snapshots (
+		id SERIAL PRIMARY KEY,
+		time TIMESTAMPTZ NOT NULL,
+		height INT4,
+		num_txs INT4,
+		total_size INT8,
+		counts INT8[],
+		sizes INT8[]
+	);`
+
+	InsertMempoolFeeSnapshot = `INSERT INTO mempool_fee_snapshots (time, height,
+			num_txs, total_size, counts, sizes)
+		VALUES ($1, $2, $3, $4, $5, $6);`
+
+	// DeleteMempoolFeeSnapshotsBefore deletes the snapshots taken before $1.
+	DeleteMempoolFeeSnapshotsBefore = `DELETE FROM mempool_fee_snapshots WHERE time < $1;`
+
+	IndexMempoolFeeSnapshotsTableOnTime = `CREATE INDEX ` + IndexOfMempoolFeeSnapshotsTableOnTime +
+		` ON mempool_fee_snapshots(time);`
+	DeindexMempoolFeeSnapshotsTableOnTime = `DROP INDEX ` + IndexOfMempoolFeeSnapshotsTableOnTime + `;`
+
+	// SelectMempoolFeeSnapshots selects the snapshots taken in the time range
+	// [$1, $2], with a limit of $3.
+	SelectMempoolFeeSnapshots = `SELECT time, height, num_txs, total_size, counts, sizes
+		FROM mempool_fee_snapshots
+		WHERE time >= $1 AND time <= $2
+		ORDER BY time
+		LIMIT $3;`
+
+	// SelectMempoolFeeSnapshotAt selects the most recent snapshot taken at or
+	// before $1.
+	SelectMempoolFeeSnapshotAt = `SELECT time, height, num_txs, total_size, counts, sizes
+		FROM mempool_fee_snapshots
+		WHERE time <= $1
+		ORDER BY time DESC
+		LIMIT 1;`
+)
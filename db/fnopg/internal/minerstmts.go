@@ -0,0 +1,89 @@
+package internal
+
+// The following statements are for the block_miners table, which attributes
+// blocks to mining pools by the text and payout addresses of their coinbase
+// transactions.
+
+const (
+	// CreateBlockMinersTable creates the block_miners table. The coinbase_text
+	// is the text found in the coinbase signature script, payout_addresses are
+	// the addresses paid by the coinbase other than the project fund, and pool
+	// is the name of the mining pool, or empty if the miner is unknown.
+	CreateBlockMinersTable = `CREATE TABLE IF NOT EXISTS block_miners (
+		block_hash TEXT PRIMARY KEY,
+		coinbase_text TEXT NOT NULL,
+		payout_addresses TEXT[],
+		pool TEXT NOT NULL
+	);`
+
+	// UpsertBlockMiner inserts the coinbase data and mining pool of a block, or
+	// replaces them if the block was already stored.
+	UpsertBlockMiner = `INSERT INTO block_miners (block_hash, coinbase_text,
+			payout_addresses, pool)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (block_hash) DO UPDATE
+		SET coinbase_text = $2, payout_addresses = $3, pool = $4;`
+
+	// SelectAllBlockMiners gets the coinbase data and mining pool of every
+	// stored block, to reattribute the blocks when the mining pools change.
+	SelectAllBlockMiners = `SELECT block_hash, coinbase_text, payout_addresses, pool
+		FROM block_miners;`
+
+	// UpdateBlockMinerPools sets the mining pools $2 of the blocks with the
+	// hashes $1.
+	UpdateBlockMinerPools = `UPDATE block_miners
+		SET pool = u.pool
+		FROM unnest($1::TEXT[], $2::TEXT[]) AS u(block_hash, pool)
+		WHERE block_miners.block_hash = u.block_hash;`
+
+	// CreateMiningPoolConfigTable creates the mining_pool_config table, which
+	// has a single row with the hash of the mining pools that the stored
+	// blocks were last attributed to.
+	CreateMiningPoolConfigTable = `CREATE TABLE IF NOT EXISTS mining_pool_config (
+		id INT2 PRIMARY KEY,
+		pools_hash TEXT NOT NULL
+	);`
+
+	// SelectMiningPoolsHash gets the hash of the mining pools that the stored
+	// blocks were last attributed to.
+	SelectMiningPoolsHash = `SELECT pools_hash FROM mining_pool_config WHERE id = 1;`
+
+	// UpsertMiningPoolsHash sets the hash of the mining pools that the stored
+	// blocks were last attributed to.
+	UpsertMiningPoolsHash = `INSERT INTO mining_pool_config (id, pools_hash)
+		VALUES (1, $1)
+		ON CONFLICT (id) DO UPDATE
+		SET pools_hash = $1;`
+
+	// SelectBlockMiner gets the height, coinbase data and mining pool of the
+	// block with hash $1.
+	SelectBlockMiner = `SELECT blocks.height, block_miners.coinbase_text,
+			block_miners.payout_addresses, block_miners.pool
+		FROM block_miners
+		JOIN blocks ON blocks.hash = block_miners.block_hash
+		WHERE block_miners.block_hash = $1;`
+
+	// SelectMainchainBlockHashes gets the hashes of the main chain blocks in
+	// height order, to populate the block_miners table.
+	SelectMainchainBlockHashes = `SELECT hash FROM blocks
+		WHERE is_mainchain
+		ORDER BY height;`
+
+	// SelectMiningPoolStats counts the main chain blocks above height $1 by
+	// mining pool, with the height of the last block of each pool.
+	SelectMiningPoolStats = `SELECT block_miners.pool, COUNT(*), MAX(blocks.height)
+		FROM block_miners
+		JOIN blocks ON blocks.hash = block_miners.block_hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		GROUP BY block_miners.pool;`
+
+	// SelectMiningPoolWindows counts the main chain blocks by mining pool,
+	// binned by the ticket price window of $1 blocks in which they were mined.
+	SelectMiningPoolWindows = `SELECT blocks.height / $1 AS window_index,
+			block_miners.pool, COUNT(*)
+		FROM block_miners
+		JOIN blocks ON blocks.hash = block_miners.block_hash
+		WHERE blocks.is_mainchain
+		GROUP BY window_index, block_miners.pool
+		ORDER BY window_index;`
+)
@@ -0,0 +1,87 @@
+package internal
+
+// The following statements are for the nulldata table, which indexes the data
+// carried by nulldata (OP_RETURN) transaction outputs.
+
+const (
+	// CreateNullDataTable creates the nulldata table. The payload is the data
+	// pushed by the output's script after the OP_RETURN.
+	CreateNullDataTable = `CREATE TABLE IF NOT EXISTS nulldata (
+		id SERIAL8 PRIMARY KEY,
+		tx_hash TEXT NOT NULL,
+		tx_index INT4 NOT NULL,
+		tx_tree INT2,
+		payload BYTEA,
+		UNIQUE (tx_hash, tx_index)
+	);`
+
+	// InsertNullData inserts the payload of a nulldata output. Outputs of
+	// transactions already stored from another block are ignored.
+	InsertNullData = `INSERT INTO nulldata (tx_hash, tx_index, tx_tree, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tx_hash, tx_index) DO NOTHING;`
+
+	// IndexNullDataTableOnPayloadHex indexes the hex-encoded payloads for the
+	// prefix searches of SelectNullDataByPrefix.
+	IndexNullDataTableOnPayloadHex = `CREATE INDEX ` + IndexOfNullDataTableOnPayloadHex +
+		` ON nulldata(encode(payload, 'hex') text_pattern_ops);`
+
+	DeindexNullDataTableOnPayloadHex = `DROP INDEX ` + IndexOfNullDataTableOnPayloadHex + `;`
+
+	// CreateTrigramExtension creates the pg_trgm extension needed for the
+	// trigram index of IndexNullDataTableOnPayloadTrgm.
+	CreateTrigramExtension = `CREATE EXTENSION IF NOT EXISTS pg_trgm;`
+
+	// IndexNullDataTableOnPayloadTrgm indexes the trigrams of the hex-encoded
+	// payloads for the substring searches of SelectNullDataContaining.
+	IndexNullDataTableOnPayloadTrgm = `CREATE INDEX ` + IndexOfNullDataTableOnPayloadTrgm +
+		` ON nulldata USING GIN (encode(payload, 'hex') gin_trgm_ops);`
+
+	DeindexNullDataTableOnPayloadTrgm = `DROP INDEX ` + IndexOfNullDataTableOnPayloadTrgm + `;`
+
+	// SelectNullDataVouts gets the scripts of all of the nulldata outputs in
+	// the vouts table, to populate the nulldata table.
+	SelectNullDataVouts = `SELECT tx_hash, tx_index, tx_tree, pkscript
+		FROM vouts
+		WHERE script_type = 'nulldata';`
+
+	nullDataSearchSelect = `SELECT nulldata.tx_hash, nulldata.tx_index,
+			transactions.block_height, transactions.block_time, nulldata.payload
+		FROM nulldata
+		JOIN transactions ON transactions.tx_hash = nulldata.tx_hash
+		WHERE transactions.is_mainchain AND `
+
+	nullDataSearchOrder = `
+		ORDER BY transactions.block_height DESC, nulldata.tx_hash, nulldata.tx_index
+		LIMIT $2 OFFSET $3;`
+
+	// SelectNullDataByPrefix gets the main chain nulldata outputs with
+	// payloads beginning with the data with lowercase hex encoding $1, most
+	// recent first.
+	SelectNullDataByPrefix = nullDataSearchSelect +
+		`encode(nulldata.payload, 'hex') LIKE $1::TEXT || '%'` +
+		nullDataSearchOrder
+
+	// SelectNullDataContaining gets the main chain nulldata outputs with
+	// payloads containing the data with lowercase hex encoding $1, most recent
+	// first. The hex substring match may use the trigram index, but may match
+	// at an odd hex digit, so the payload itself is also checked.
+	SelectNullDataContaining = nullDataSearchSelect +
+		`encode(nulldata.payload, 'hex') LIKE '%' || $1::TEXT || '%'
+			AND position(decode($1::TEXT, 'hex') IN nulldata.payload) > 0` +
+		nullDataSearchOrder
+
+	// SelectNullDataChart gets the number of nulldata outputs in each main
+	// chain block above height $1.
+	SelectNullDataChart = `SELECT blocks.height, COALESCE(n.count, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT transactions.block_hash, COUNT(*) AS count
+			FROM nulldata
+			JOIN transactions ON transactions.tx_hash = nulldata.tx_hash
+			WHERE transactions.is_mainchain AND transactions.block_height > $1
+			GROUP BY transactions.block_hash
+		) n ON n.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+)
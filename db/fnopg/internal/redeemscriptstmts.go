@@ -0,0 +1,57 @@
+package internal
+
+// The following statements are for the redeem_scripts table, which holds the
+// decoded redeem scripts of P2SH addresses that have been spent from.
+
+const (
+	// CreateRedeemScriptsTable creates the redeem_scripts table. For multisig
+	// scripts, pubkeys holds the public keys and addresses their P2PKH
+	// addresses. The spending transaction input that first revealed the script
+	// is also recorded.
+	CreateRedeemScriptsTable = `CREATE TABLE IF NOT EXISTS redeem_scripts (
+		address TEXT PRIMARY KEY,
+		script BYTEA NOT NULL,
+		script_class TEXT,
+		required_sigs INT4,
+		pubkeys TEXT[],
+		addresses TEXT[],
+		spending_tx_hash TEXT,
+		spending_tx_index INT4
+	);`
+
+	// InsertRedeemScript inserts a decoded redeem script. Since a P2SH address
+	// is the hash of its redeem script, the scripts revealed by later spends
+	// are identical, and are ignored.
+	InsertRedeemScript = `INSERT INTO redeem_scripts (address, script,
+			script_class, required_sigs, pubkeys, addresses, spending_tx_hash,
+			spending_tx_index)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (address) DO NOTHING;`
+
+	// SelectRedeemScript gets the redeem script of a P2SH address.
+	SelectRedeemScript = `SELECT address, script, script_class, required_sigs,
+			pubkeys, addresses, spending_tx_hash, spending_tx_index
+		FROM redeem_scripts
+		WHERE address = $1;`
+
+	// SelectRedeemScriptsByAddress gets the redeem scripts that include the
+	// given address, such as the multisig scripts with one of its keys.
+	SelectRedeemScriptsByAddress = `SELECT address, script, script_class,
+			required_sigs, pubkeys, addresses, spending_tx_hash, spending_tx_index
+		FROM redeem_scripts
+		WHERE $1 = ANY(addresses)
+		ORDER BY address;`
+
+	// SelectP2SHSpends gets the main chain transaction inputs spending P2SH
+	// outputs, with the hashes of their blocks, to populate the redeem_scripts
+	// table.
+	SelectP2SHSpends = `SELECT transactions.block_hash, vins.tx_hash, vins.tx_index
+		FROM vins
+		JOIN vouts ON vouts.tx_hash = vins.prev_tx_hash
+			AND vouts.tx_index = vins.prev_tx_index
+		JOIN transactions ON transactions.tx_hash = vins.tx_hash
+			AND transactions.tree = vins.tx_tree
+			AND transactions.is_mainchain
+		WHERE vouts.script_type = 'scripthash'
+		ORDER BY transactions.block_height, vins.tx_hash, vins.tx_index;`
+)
@@ -0,0 +1,86 @@
+package internal
+
+// The following statements are for stake pool (voting service provider)
+// statistics, which attribute tickets to pools by the reward addresses of
+// their commitments and by their voting (stakesubmission) addresses.
+
+const (
+	// SelectStakePoolFeeAddresses detects stake pool fee addresses. The smaller
+	// commitment of a main chain ticket with two commitments is taken to be a
+	// pool fee, and a reward address of such commitments is a pool fee address
+	// if tickets with at least $1 distinct voting addresses pay to it. The
+	// addresses are ordered by the number of tickets paying to them.
+	SelectStakePoolFeeAddresses = `WITH fees AS (
+			SELECT DISTINCT ON (ticket_commitments.ticket_hash)
+				ticket_commitments.reward_address, tickets.stakesubmission_address
+			FROM ticket_commitments
+			JOIN tickets ON tickets.tx_hash = ticket_commitments.ticket_hash
+			WHERE tickets.is_mainchain AND ticket_commitments.ticket_hash IN (
+				SELECT ticket_hash FROM ticket_commitments
+				GROUP BY ticket_hash
+				HAVING COUNT(*) = 2)
+			ORDER BY ticket_commitments.ticket_hash, ticket_commitments.amount
+		)
+		SELECT reward_address
+		FROM fees
+		GROUP BY reward_address
+		HAVING COUNT(DISTINCT stakesubmission_address) >= $1
+		ORDER BY COUNT(*) DESC;`
+
+	// SelectStakePoolTicketCounts counts the main chain tickets of stake pools
+	// by pool status. $1 and $2 are arrays of the addresses and names of the
+	// pools, such that the pool with address $1[i] is named $2[i]. A ticket is
+	// counted once per pool, even if several of the pool's addresses match it.
+	SelectStakePoolTicketCounts = `WITH pools AS (
+			SELECT * FROM UNNEST($1::TEXT[], $2::TEXT[]) AS p (address, name)
+		), pool_tickets AS (
+			SELECT pools.name, ticket_commitments.ticket_hash AS tx_hash
+			FROM ticket_commitments
+			JOIN pools ON pools.address = ticket_commitments.reward_address
+			UNION
+			SELECT pools.name, tickets.tx_hash
+			FROM tickets
+			JOIN pools ON pools.address = tickets.stakesubmission_address
+		)
+		SELECT pool_tickets.name, tickets.pool_status, COUNT(*)
+		FROM pool_tickets
+		JOIN tickets ON tickets.tx_hash = pool_tickets.tx_hash
+		WHERE tickets.is_mainchain
+		GROUP BY pool_tickets.name, tickets.pool_status;`
+
+	// SelectStakePoolTicketWindows counts the main chain tickets of a stake
+	// pool with any of the addresses in $1, binned by the ticket price window
+	// of $4 blocks in which they matured, and the window in which they left
+	// the live ticket pool by voting, expiring, or being missed. $2 and $3 are
+	// the ticket maturity and expiry in blocks. The exit window of live
+	// tickets is NULL.
+	SelectStakePoolTicketWindows = `WITH pool_tickets AS (
+			SELECT ticket_hash AS tx_hash
+			FROM ticket_commitments
+			WHERE reward_address = ANY($1)
+			UNION
+			SELECT tx_hash
+			FROM tickets
+			WHERE stakesubmission_address = ANY($1)
+		), mainchain_misses AS (
+			SELECT misses.ticket_hash, misses.height
+			FROM misses
+			JOIN blocks ON blocks.hash = misses.block_hash
+			WHERE blocks.is_mainchain
+		)
+		SELECT (tickets.block_height + $2) / $4 AS mature_window,
+			tickets.pool_status,
+			(CASE tickets.pool_status
+				WHEN 1 THEN tickets.spend_height
+				WHEN 2 THEN tickets.block_height + $3
+				WHEN 3 THEN mainchain_misses.height
+			END) / $4 AS exit_window,
+			COUNT(*)
+		FROM pool_tickets
+		JOIN tickets ON tickets.tx_hash = pool_tickets.tx_hash
+		LEFT JOIN mainchain_misses ON mainchain_misses.ticket_hash = tickets.tx_hash
+			AND tickets.pool_status = 3
+		WHERE tickets.is_mainchain
+		GROUP BY mature_window, tickets.pool_status, exit_window
+		ORDER BY mature_window;`
+)
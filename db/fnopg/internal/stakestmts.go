@@ -1,5 +1,7 @@
 package internal
 
+import "fmt"
+
 // The folloiwng statements are for the tickets, votes, and misses tables.
 
 const (
@@ -342,6 +344,77 @@ const (
 		WHERE ticket_hash = $1
 			AND blocks.is_mainchain = TRUE;`
 
+	// selectMissRates is the basis for the miss rate statements. It gets the
+	// first and last block heights, start time, and the numbers of votes and
+	// misses of the main chain blocks from height $1 on, grouped by %s.
+	selectMissRates = `SELECT MIN(blocks.height), MAX(blocks.height), MIN(blocks.time),
+			SUM(blocks.voters), COALESCE(SUM(m.count), 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT block_hash, COUNT(*) AS count
+			FROM misses
+			GROUP BY block_hash
+		) m ON m.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height >= $1
+		GROUP BY %s
+		ORDER BY 1;`
+
+	// SelectMissedVotesChart gets the number of missed votes in each main
+	// chain block above height $1.
+	SelectMissedVotesChart = `SELECT blocks.height, COALESCE(m.count, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT misses.block_hash, COUNT(*) AS count
+			FROM misses
+			WHERE misses.height > $1
+			GROUP BY misses.block_hash
+		) m ON m.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+
+	// unrevokedTicketsWhere selects the main chain tickets that missed or
+	// expired and are not yet revoked, optionally with voting address $1 and
+	// a commitment to reward address $2. Empty addresses match any ticket.
+	unrevokedTicketsWhere = `WHERE tickets.is_mainchain
+			AND tickets.pool_status IN (2, 3) AND tickets.spend_type = 0
+			AND ($1 = '' OR tickets.stakesubmission_address = $1)
+			AND ($2 = '' OR tickets.tx_hash IN (
+				SELECT ticket_hash FROM ticket_commitments
+				WHERE reward_address = $2))`
+
+	// SelectUnrevokedTickets gets up to $3 of the missed and expired tickets
+	// that are not yet revoked, skipping $4, most recently purchased first.
+	// The height of the main chain block in which a missed ticket was called
+	// to vote is included. See unrevokedTicketsWhere for the filters.
+	SelectUnrevokedTickets = `SELECT tickets.tx_hash, tickets.block_height,
+			tickets.price, tickets.pool_status, tickets.stakesubmission_address,
+			COALESCE(m.height, 0)
+		FROM tickets
+		LEFT JOIN (
+			SELECT misses.ticket_hash, misses.height
+			FROM misses
+			JOIN blocks ON blocks.hash = misses.block_hash
+			WHERE blocks.is_mainchain
+		) m ON m.ticket_hash = tickets.tx_hash
+		` + unrevokedTicketsWhere + `
+		ORDER BY tickets.block_height DESC, tickets.tx_hash
+		LIMIT $3 OFFSET $4;`
+
+	// SelectUnrevokedTicketsCount gets the number of missed and expired
+	// tickets that are not yet revoked. See unrevokedTicketsWhere for the
+	// filters.
+	SelectUnrevokedTicketsCount = `SELECT COUNT(*)
+		FROM tickets
+		` + unrevokedTicketsWhere + `;`
+
+	// SelectLiveTicketCountsByHeight gets the number of live and immature
+	// main chain tickets purchased in each block above height $1.
+	SelectLiveTicketCountsByHeight = `SELECT block_height, COUNT(*)
+		FROM tickets
+		WHERE is_mainchain AND pool_status = 0 AND block_height > $1
+		GROUP BY block_height
+		ORDER BY block_height;`
+
 	// agendas table
 
 	CreateAgendasTable = `CREATE TABLE IF NOT EXISTS agendas (
@@ -428,6 +501,22 @@ const (
 
 	SelectAgendaVoteTotals = `SELECT ` + selectAgendaVotesQuery + `;`
 
+	// SelectAgendaVotesByAddress groups the agenda vote choices by the voting
+	// (stake submission) address of the tickets that cast the votes. Addresses
+	// are ordered by the total number of votes cast, with a limit of $7.
+	SelectAgendaVotesByAddress = `SELECT tickets.stakesubmission_address AS address,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $1 THEN 1 ELSE NULL END) AS yes,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $2 THEN 1 ELSE NULL END) AS abstain,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $3 THEN 1 ELSE NULL END) AS no,
+			count(*) AS total
+		FROM agenda_votes
+		INNER JOIN votes ON agenda_votes.votes_row_id = votes.id
+		INNER JOIN tickets ON tickets.tx_hash = votes.ticket_hash
+			AND tickets.is_mainchain = TRUE
+		WHERE agenda_votes.agendas_row_id = (SELECT id from agendas WHERE name = $4)
+		AND votes.height >= $5 AND votes.height <= $6
+		GROUP BY address ORDER BY total DESC, address LIMIT $7;`
+
 	selectAgendaVotesQuery = `
 			count(CASE WHEN agenda_votes.agenda_vote_choice = $1 THEN 1 ELSE NULL END) AS yes,
 			count(CASE WHEN agenda_votes.agenda_vote_choice = $2 THEN 1 ELSE NULL END) AS abstain,
@@ -505,6 +594,24 @@ const (
 		WHERE proposals.token = $1
 		GROUP BY proposals.time
 		ORDER BY proposals.time;`
+
+	// SelectProposalVotesTimeline counts the votes added at each block height,
+	// where the height of a commit's votes is that of the latest mainchain
+	// block at the commit time.
+	SelectProposalVotesTimeline = `SELECT commit_blocks.height,
+		COUNT(CASE WHEN proposal_votes.choice = 'No' THEN 1 ElSE NULL END) as no,
+		COUNT(CASE WHEN proposal_votes.choice = 'Yes' THEN 1 ElSE NULL END) as yes
+		FROM proposal_votes
+		INNER JOIN proposals on proposals.id = proposal_votes.proposals_row_id
+		INNER JOIN LATERAL (
+			SELECT height FROM blocks
+			WHERE is_mainchain AND time <= proposals.time
+			ORDER BY height DESC
+			LIMIT 1
+		) AS commit_blocks ON TRUE
+		WHERE proposals.token = $1
+		GROUP BY commit_blocks.height
+		ORDER BY commit_blocks.height;`
 )
 
 // MakeTicketInsertStatement returns the appropriate tickets insert statement
@@ -582,6 +689,15 @@ func MakeProposalsInsertStatement(checked bool) string {
 	return InsertProposalsRow
 }
 
+// MakeSelectMissRates returns the selectMissRates query for the given time
+// grouping, or for "window", grouped by ticket price windows of $2 blocks.
+func MakeSelectMissRates(group string) string {
+	if group == "window" {
+		return fmt.Sprintf(selectMissRates, "blocks.height / $2")
+	}
+	return formatGroupingQuery(selectMissRates, group, "blocks.time")
+}
+
 // MakeSelectTicketsByPurchaseDate returns the selectTicketsByPurchaseDate query
 func MakeSelectTicketsByPurchaseDate(group string) string {
 	return formatGroupingQuery(selectTicketsByPurchaseDate, group, "transactions.block_time")
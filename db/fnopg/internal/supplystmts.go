@@ -0,0 +1,39 @@
+package internal
+
+// The following statements are for the coin supply breakdown, which is computed
+// from the vouts and addresses tables.
+
+const (
+	// SelectSupplyChart gets, for each main chain block above height $1, the
+	// amount burned in nulldata outputs or outputs paying the zero pubkey hash
+	// address $3, and the amounts received and spent by the project fund
+	// address $2. Outputs of the regular transaction tree of a block that is
+	// disapproved by stakeholders are not counted as burned.
+	SelectSupplyChart = `SELECT blocks.height, COALESCE(b.burned, 0),
+			COALESCE(d.received, 0), COALESCE(d.sent, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT transactions.block_height, SUM(vouts.value) AS burned
+			FROM vouts
+			JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+			WHERE transactions.is_mainchain AND transactions.is_valid
+				AND transactions.block_height > $1
+				AND vouts.value > 0
+				AND (vouts.script_type = 'nulldata'
+					OR $3 = ANY(vouts.script_addresses))
+			GROUP BY transactions.block_height
+		) b ON b.block_height = blocks.height
+		LEFT JOIN (
+			SELECT transactions.block_height,
+				SUM(CASE WHEN addresses.is_funding THEN addresses.value ELSE 0 END) AS received,
+				SUM(CASE WHEN addresses.is_funding THEN 0 ELSE addresses.value END) AS sent
+			FROM addresses
+			JOIN transactions ON transactions.tx_hash = addresses.tx_hash
+				AND transactions.is_mainchain
+			WHERE addresses.address = $2 AND addresses.valid_mainchain
+				AND transactions.block_height > $1
+			GROUP BY transactions.block_height
+		) d ON d.block_height = blocks.height
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+)
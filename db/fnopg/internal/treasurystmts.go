@@ -0,0 +1,63 @@
+package internal
+
+// The following statements are for the flows of the project fund (treasury)
+// address, which are computed from the addresses table.
+
+const (
+	// SelectTreasuryInflowsByMonth sums the amounts received by address $1 in
+	// each calendar month from transactions other than coinbases. Transactions
+	// that also spend from the address are excluded, since the amounts they
+	// return to the address are change.
+	SelectTreasuryInflowsByMonth = `SELECT date_trunc('month', addresses.block_time) AS month,
+			SUM(addresses.value)
+		FROM addresses
+		JOIN transactions ON transactions.tx_hash = addresses.tx_hash
+			AND transactions.is_mainchain
+		WHERE addresses.address = $1 AND addresses.is_funding
+			AND addresses.valid_mainchain
+			AND NOT (transactions.tree = 0 AND transactions.block_index = 0)
+			AND NOT EXISTS (SELECT 1 FROM addresses spending
+				WHERE spending.address = $1
+					AND spending.tx_hash = addresses.tx_hash
+					AND spending.is_funding = FALSE
+					AND spending.valid_mainchain)
+		GROUP BY month
+		ORDER BY month;`
+
+	// SelectTreasuryCoinbaseInflows counts the coinbase transactions paying
+	// address $1, grouped by calendar month, subsidy reduction interval of $2
+	// blocks, number of votes in the block, and amount paid to the address.
+	// The project fund subsidy is the same for the blocks of each group, so a
+	// representative height is selected to compute it.
+	SelectTreasuryCoinbaseInflows = `SELECT date_trunc('month', coinbases.block_time) AS month,
+			MIN(coinbases.block_height), coinbases.voters, coinbases.amount, COUNT(*)
+		FROM (
+			SELECT MIN(addresses.block_time) AS block_time, transactions.block_height,
+				blocks.voters, SUM(addresses.value) AS amount
+			FROM addresses
+			JOIN transactions ON transactions.tx_hash = addresses.tx_hash
+				AND transactions.is_mainchain
+				AND transactions.tree = 0 AND transactions.block_index = 0
+			JOIN blocks ON blocks.hash = transactions.block_hash
+			WHERE addresses.address = $1 AND addresses.is_funding
+				AND addresses.valid_mainchain
+			GROUP BY addresses.tx_hash, transactions.block_height, blocks.voters
+		) coinbases
+		GROUP BY month, coinbases.block_height / $2, coinbases.voters, coinbases.amount
+		ORDER BY month;`
+
+	// SelectTreasurySpends selects the transactions spending from address $1,
+	// with the amounts they spend from and return to the address.
+	SelectTreasurySpends = `SELECT addresses.tx_hash, transactions.block_height,
+			MIN(addresses.block_time),
+			SUM(CASE WHEN addresses.is_funding THEN 0 ELSE addresses.value END),
+			SUM(CASE WHEN addresses.is_funding THEN addresses.value ELSE 0 END)
+		FROM addresses
+		JOIN transactions ON transactions.tx_hash = addresses.tx_hash
+			AND transactions.is_mainchain
+		WHERE addresses.address = $1 AND addresses.valid_mainchain
+			AND addresses.tx_hash IN (SELECT tx_hash FROM addresses
+				WHERE address = $1 AND is_funding = FALSE AND valid_mainchain)
+		GROUP BY addresses.tx_hash, transactions.block_height
+		ORDER BY transactions.block_height;`
+)
@@ -0,0 +1,173 @@
+package internal
+
+// The following statements are for the address usage and UTXO set charts. The
+// address usage of each block is computed from the addresses and transactions
+// tables as the block is stored, and kept in the address_usage table. The UTXO
+// set changes of each block are computed from the vouts, vins and transactions
+// tables as the block is stored, and kept in the utxo_set_changes table, since
+// archival pruning deletes spent vouts and vins.
+
+const (
+	// CreateAddressUsageTable creates the address_usage table, which holds,
+	// for each main chain block, the number of distinct addresses funded or
+	// spent in the block (active), the number of those not already funded or
+	// spent earlier in the same UTC day (day_active), and the number of
+	// addresses funded for the first time (new_addresses). An address with
+	// pruned history is not new if it was first seen before the block.
+	CreateAddressUsageTable = `CREATE TABLE IF NOT EXISTS address_usage (
+		block_hash TEXT PRIMARY KEY,
+		height INT4,
+		active INT8,
+		day_active INT8,
+		new_addresses INT8
+	);`
+
+	// UpsertAddressUsage computes and stores the address usage of the main
+	// chain blocks at heights $1 through $2, replacing any stored usage of the
+	// blocks. Earlier uses of an address are those in main chain blocks of
+	// lower height, since block times are not strictly increasing, and those
+	// in the address_summary of pruned history first seen before the block.
+	UpsertAddressUsage = `INSERT INTO address_usage (block_hash, height, active,
+			day_active, new_addresses)
+		SELECT blocks.hash, blocks.height, COALESCE(a.active, 0),
+			COALESCE(a.day_active, 0), COALESCE(a.new_addresses, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT blocks.height,
+				COUNT(DISTINCT addresses.address) AS active,
+				COUNT(DISTINCT addresses.address) FILTER (WHERE NOT EXISTS (
+					SELECT 1 FROM addresses prev
+					JOIN transactions prev_tx ON prev_tx.tx_hash = prev.tx_hash
+						AND prev_tx.is_mainchain
+					WHERE prev.address = addresses.address AND prev.valid_mainchain
+						AND prev_tx.block_height < blocks.height
+						AND prev.block_time >= date_trunc('day',
+							addresses.block_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+				)) AS day_active,
+				COUNT(DISTINCT addresses.address) FILTER (WHERE addresses.is_funding
+					AND NOT EXISTS (
+						SELECT 1 FROM addresses prev
+						JOIN transactions prev_tx ON prev_tx.tx_hash = prev.tx_hash
+							AND prev_tx.is_mainchain
+						WHERE prev.address = addresses.address AND prev.valid_mainchain
+							AND prev_tx.block_height < blocks.height
+					)
+					AND NOT EXISTS (
+						SELECT 1 FROM address_summary
+						WHERE address_summary.address = addresses.address
+							AND address_summary.first_seen < addresses.block_time
+				)) AS new_addresses
+			FROM blocks
+			JOIN transactions ON transactions.block_hash = blocks.hash
+				AND transactions.is_mainchain
+			JOIN addresses ON addresses.tx_hash = transactions.tx_hash
+				AND addresses.valid_mainchain
+			WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+			GROUP BY blocks.height
+		) a ON a.height = blocks.height
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ON CONFLICT (block_hash) DO UPDATE
+		SET height = EXCLUDED.height, active = EXCLUDED.active,
+			day_active = EXCLUDED.day_active,
+			new_addresses = EXCLUDED.new_addresses;`
+
+	// SelectAddressUsageTip gets the height of the highest main chain block
+	// with stored address usage, or -1 if there is none.
+	SelectAddressUsageTip = `SELECT COALESCE(MAX(address_usage.height), -1)
+		FROM address_usage
+		JOIN blocks ON blocks.hash = address_usage.block_hash
+			AND blocks.is_mainchain;`
+
+	// SelectAddressUsageChart gets the stored address usage of each main chain
+	// block above height $1. Blocks without stored usage have zero usage.
+	SelectAddressUsageChart = `SELECT blocks.height,
+			COALESCE(address_usage.active, 0),
+			COALESCE(address_usage.day_active, 0),
+			COALESCE(address_usage.new_addresses, 0)
+		FROM blocks
+		LEFT JOIN address_usage ON address_usage.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+
+	// CreateUTXOSetChangesTable creates the utxo_set_changes table, which
+	// holds, for each main chain block, the number and value of the spendable
+	// (not nulldata) outputs created, the number and value of the previous
+	// outputs spent, and the total output value and number of the regular,
+	// non-coinbase transactions.
+	CreateUTXOSetChangesTable = `CREATE TABLE IF NOT EXISTS utxo_set_changes (
+		block_hash TEXT PRIMARY KEY,
+		height INT4,
+		created INT8,
+		created_value INT8,
+		spent INT8,
+		spent_value INT8,
+		tx_value INT8,
+		tx_count INT8
+	);`
+
+	// UpsertUTXOSetChanges computes and stores the UTXO set changes of the
+	// main chain blocks at heights $1 through $2, replacing any stored changes
+	// of the blocks. Transactions of a regular transaction tree disapproved by
+	// stakeholders are skipped.
+	UpsertUTXOSetChanges = `INSERT INTO utxo_set_changes (block_hash, height,
+			created, created_value, spent, spent_value, tx_value, tx_count)
+		SELECT blocks.hash, blocks.height, COALESCE(o.created, 0),
+			COALESCE(o.created_value, 0), COALESCE(i.spent, 0),
+			COALESCE(i.spent_value, 0), COALESCE(t.tx_value, 0),
+			COALESCE(t.tx_count, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT transactions.block_height, COUNT(*) AS created,
+				SUM(vouts.value) AS created_value
+			FROM vouts
+			JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+			WHERE transactions.is_mainchain AND transactions.is_valid
+				AND transactions.block_height BETWEEN $1 AND $2
+				AND vouts.script_type != 'nulldata'
+			GROUP BY transactions.block_height
+		) o ON o.block_height = blocks.height
+		LEFT JOIN (
+			SELECT transactions.block_height, COUNT(*) AS spent,
+				SUM(vins.value_in) AS spent_value
+			FROM vins
+			JOIN transactions ON transactions.tx_hash = vins.tx_hash
+			WHERE vins.is_mainchain AND vins.is_valid AND transactions.is_mainchain
+				AND transactions.block_height BETWEEN $1 AND $2
+				AND vins.prev_tx_hash != '0000000000000000000000000000000000000000000000000000000000000000'
+			GROUP BY transactions.block_height
+		) i ON i.block_height = blocks.height
+		LEFT JOIN (
+			SELECT block_height, SUM(sent) AS tx_value, COUNT(*) AS tx_count
+			FROM transactions
+			WHERE is_mainchain AND is_valid AND tree = 0 AND block_index > 0
+				AND block_height BETWEEN $1 AND $2
+			GROUP BY block_height
+		) t ON t.block_height = blocks.height
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ON CONFLICT (block_hash) DO UPDATE
+		SET height = EXCLUDED.height, created = EXCLUDED.created,
+			created_value = EXCLUDED.created_value, spent = EXCLUDED.spent,
+			spent_value = EXCLUDED.spent_value, tx_value = EXCLUDED.tx_value,
+			tx_count = EXCLUDED.tx_count;`
+
+	// SelectUTXOSetChangesTip gets the height of the highest main chain block
+	// with stored UTXO set changes, or -1 if there are none.
+	SelectUTXOSetChangesTip = `SELECT COALESCE(MAX(utxo_set_changes.height), -1)
+		FROM utxo_set_changes
+		JOIN blocks ON blocks.hash = utxo_set_changes.block_hash
+			AND blocks.is_mainchain;`
+
+	// SelectUTXOSetChart gets the stored UTXO set changes of each main chain
+	// block above height $1. Blocks without stored changes have no changes.
+	SelectUTXOSetChart = `SELECT blocks.height,
+			COALESCE(utxo_set_changes.created, 0),
+			COALESCE(utxo_set_changes.created_value, 0),
+			COALESCE(utxo_set_changes.spent, 0),
+			COALESCE(utxo_set_changes.spent_value, 0),
+			COALESCE(utxo_set_changes.tx_value, 0),
+			COALESCE(utxo_set_changes.tx_count, 0)
+		FROM blocks
+		LEFT JOIN utxo_set_changes ON utxo_set_changes.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+)
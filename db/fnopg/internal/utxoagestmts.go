@@ -0,0 +1,111 @@
+package internal
+
+// The following statements are for the coin_days_destroyed table, which holds
+// the coin-days-destroyed chart data computed as each block is stored, and the
+// utxo_ages table, which holds the daily age distribution of the unspent
+// transaction outputs.
+
+const (
+	// CreateUTXOAgesTable creates the utxo_ages table. The time is the end of
+	// the UTC day at which the distribution is measured, and counts and amounts
+	// are the number and value of the unspent outputs in each age band.
+	CreateUTXOAgesTable = `CREATE TABLE IF NOT EXISTS utxo_ages (
+		time TIMESTAMPTZ PRIMARY KEY,
+		counts INT8[],
+		amounts INT8[]
+	);`
+
+	// UpsertUTXOAges inserts the UTXO age distribution at time $1, or replaces
+	// it if it was already stored.
+	UpsertUTXOAges = `INSERT INTO utxo_ages (time, counts, amounts)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (time) DO UPDATE
+		SET counts = $2, amounts = $3;`
+
+	// SelectUTXOAges gets the UTXO age distributions, oldest first.
+	SelectUTXOAges = `SELECT time, counts, amounts FROM utxo_ages ORDER BY time;`
+
+	// DeleteUTXOAgesAfter deletes the UTXO age distributions measured after
+	// time $1.
+	DeleteUTXOAgesAfter = `DELETE FROM utxo_ages WHERE time > $1;`
+
+	// SelectLastUTXOAgesTime gets the time of the most recent UTXO age
+	// distribution, or NULL if there are none.
+	SelectLastUTXOAgesTime = `SELECT MAX(time) FROM utxo_ages;`
+
+	// SelectUTXOAgeCells gets the number and value of the spendable (not
+	// nulldata) outputs by the UTC day, counted in days since the epoch, that
+	// they were created and spent. The day spent is NULL for outputs that are
+	// unspent. Outputs spent before $1 and outputs created at or after $2 are
+	// skipped. Every output created before $2 is still joined to its spending
+	// vin, so the cost grows with the number of outputs ever created rather
+	// than with the number of days being computed.
+	SelectUTXOAgeCells = `SELECT created_day, spent_day, COUNT(*), SUM(value)
+		FROM (
+			SELECT FLOOR(EXTRACT(EPOCH FROM funding.block_time) / 86400)::INT8 AS created_day,
+				FLOOR(EXTRACT(EPOCH FROM spending.block_time) / 86400)::INT8 AS spent_day,
+				vouts.value
+			FROM vouts
+			JOIN transactions funding ON funding.tx_hash = vouts.tx_hash
+			LEFT JOIN vins spending ON spending.prev_tx_hash = vouts.tx_hash
+				AND spending.prev_tx_index = vouts.tx_index
+				AND spending.prev_tx_tree = vouts.tx_tree
+				AND spending.is_mainchain AND spending.is_valid
+			WHERE funding.is_mainchain AND funding.is_valid
+				AND funding.block_time < $2
+				AND vouts.script_type != 'nulldata'
+				AND (spending.block_time IS NULL OR spending.block_time >= $1)
+		) outputs
+		GROUP BY created_day, spent_day;`
+
+	// CreateCoinDaysDestroyedTable creates the coin_days_destroyed table,
+	// which holds the coin-days destroyed by the transactions of each main
+	// chain block: the sum over the previous outputs spent of their value in
+	// coins times the number of days since they were created.
+	CreateCoinDaysDestroyedTable = `CREATE TABLE IF NOT EXISTS coin_days_destroyed (
+		block_hash TEXT PRIMARY KEY,
+		height INT4,
+		coin_days FLOAT8
+	);`
+
+	// UpsertCoinDaysDestroyed computes and stores the coin-days destroyed by
+	// the main chain blocks at heights $1 through $2, replacing any stored
+	// values of the blocks.
+	UpsertCoinDaysDestroyed = `INSERT INTO coin_days_destroyed (block_hash, height, coin_days)
+		SELECT blocks.hash, blocks.height, COALESCE(c.coin_days, 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT blocks.height,
+				SUM(vins.value_in * EXTRACT(EPOCH FROM vins.block_time - funding.block_time))
+					/ 8.64e12 AS coin_days
+			FROM blocks
+			JOIN transactions ON transactions.block_hash = blocks.hash
+				AND transactions.is_mainchain
+			JOIN vins ON vins.tx_hash = transactions.tx_hash
+				AND vins.is_mainchain AND vins.is_valid
+			JOIN transactions funding ON funding.tx_hash = vins.prev_tx_hash
+				AND funding.is_mainchain
+			WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+			GROUP BY blocks.height
+		) c ON c.height = blocks.height
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ON CONFLICT (block_hash) DO UPDATE
+		SET height = EXCLUDED.height, coin_days = EXCLUDED.coin_days;`
+
+	// SelectCoinDaysDestroyedTip gets the height of the highest main chain
+	// block with stored coin-days destroyed, or -1 if there is none.
+	SelectCoinDaysDestroyedTip = `SELECT COALESCE(MAX(coin_days_destroyed.height), -1)
+		FROM coin_days_destroyed
+		JOIN blocks ON blocks.hash = coin_days_destroyed.block_hash
+			AND blocks.is_mainchain;`
+
+	// SelectCoinDaysDestroyedChart gets the stored coin-days destroyed by each
+	// main chain block above height $1. Blocks without a stored value destroyed
+	// none.
+	SelectCoinDaysDestroyedChart = `SELECT blocks.height,
+			COALESCE(coin_days_destroyed.coin_days, 0)
+		FROM blocks
+		LEFT JOIN coin_days_destroyed ON coin_days_destroyed.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+)
@@ -0,0 +1,30 @@
+package internal
+
+// The following statements are for the history of the block, stake, and vote
+// versions, which are stored with each block and vote.
+
+const (
+	// SelectBlockVersionCounts counts the main chain blocks at or above height
+	// $2 by block version in each stake version interval of $1 blocks.
+	SelectBlockVersionCounts = `SELECT height / $1 AS interval, version, COUNT(*)
+		FROM blocks
+		WHERE is_mainchain AND height >= $2
+		GROUP BY interval, version
+		ORDER BY interval, version;`
+
+	// SelectStakeVersionCounts counts the main chain blocks at or above height
+	// $2 by stake version in each stake version interval of $1 blocks.
+	SelectStakeVersionCounts = `SELECT height / $1 AS interval, stake_version, COUNT(*)
+		FROM blocks
+		WHERE is_mainchain AND height >= $2
+		GROUP BY interval, stake_version
+		ORDER BY interval, stake_version;`
+
+	// SelectVoteVersionCounts counts the main chain votes in blocks at or above
+	// height $2 by vote version in each stake version interval of $1 blocks.
+	SelectVoteVersionCounts = `SELECT height / $1 AS interval, version, COUNT(*)
+		FROM votes
+		WHERE is_mainchain AND height >= $2
+		GROUP BY interval, version
+		ORDER BY interval, version;`
+)
@@ -67,6 +67,24 @@ const (
 				FROM vins) t
 			WHERE t.rnum > 1);`
 
+	// PruneSpentVouts deletes the vouts spent by the valid main chain vins with
+	// block_time before $1. These are the spent vouts not already pruned by
+	// PruneAddressHistory, such as non-standard outputs without addresses,
+	// which would otherwise appear unspent once PruneVins deletes the vins
+	// spending them. The vout_db_ids of the transactions are left intact. This
+	// is only used for archival pruning, and should be run before PruneVins.
+	PruneSpentVouts = `DELETE FROM vouts
+		USING vins
+		WHERE vins.block_time < $1 AND vins.is_mainchain AND vins.is_valid
+			AND vouts.tx_hash = vins.prev_tx_hash
+			AND vouts.tx_index = vins.prev_tx_index
+			AND vouts.tx_tree = vins.prev_tx_tree;`
+
+	// PruneVins deletes the vins with block_time before $1. The vin_db_ids of
+	// the transactions are left intact. This is only used for archival
+	// pruning, and should be run after PruneAddressHistory.
+	PruneVins = `DELETE FROM vins WHERE block_time < $1;`
+
 	IndexVinTableOnVins = `CREATE UNIQUE INDEX ` + IndexOfVinsTableOnVin +
 		` ON vins(tx_hash, tx_index, tx_tree);`
 	DeindexVinTableOnVins = `DROP INDEX ` + IndexOfVinsTableOnVin + `;`
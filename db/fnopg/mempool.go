@@ -0,0 +1,147 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"time"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	exptypes "github.com/fonero-project/fnodata/explorer/types"
+	"github.com/fonero-project/fnodata/mempool"
+)
+
+// defaultMempoolSnapInterval is the interval between mempool fee rate
+// snapshots if EnableMempoolHistory is given no interval.
+const defaultMempoolSnapInterval = time.Minute
+
+// EnableMempoolHistory enables the periodic mempool fee rate snapshots, taken
+// every snapInterval, and sets the time for which the mempool history is kept.
+// The transactions removed from mempool and the snapshots taken more than keep
+// ago are deleted with each new block. A keep value of 0 keeps all history.
+// The snapshots stop when the ChainDB's context is canceled.
+func (pgb *ChainDB) EnableMempoolHistory(snapInterval, keep time.Duration) {
+	if pgb == nil {
+		return
+	}
+	if snapInterval <= 0 {
+		snapInterval = defaultMempoolSnapInterval
+	}
+	pgb.mempoolHistoryKeep = keep
+	go pgb.mempoolSnapshots(snapInterval)
+}
+
+// mempoolSnapshots stores a mempool fee rate snapshot every interval until the
+// ChainDB's context is canceled.
+func (pgb *ChainDB) mempoolSnapshots(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if pgb.InBatchSync {
+				continue
+			}
+			if err := pgb.storeMempoolFeeSnapshot(); err != nil {
+				log.Errorf("Failed to store mempool fee snapshot: %v", err)
+			}
+		case <-pgb.ctx.Done():
+			return
+		}
+	}
+}
+
+// storeMempoolFeeSnapshot stores a fee rate histogram of the transactions
+// recorded as pending in mempool.
+func (pgb *ChainDB) storeMempoolFeeSnapshot() error {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	txs, err := retrievePendingMempoolTxFees(ctx, pgb.db)
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+	snap := makeMempoolFeeSnapshot(txs, time.Now(), pgb.Height())
+	return insertMempoolFeeSnapshot(pgb.db, snap)
+}
+
+// StoreMPData records the mempool history, satisfying the
+// mempool.MempoolDataSaver interface. The transactions in mempool are recorded
+// with their entry times, and the previously recorded transactions that are no
+// longer in mempool are marked as mined, double-spent, or expired. History
+// older than the time set by EnableMempoolHistory is then deleted. Nothing is
+// stored during batch sync, when the blockchain tables are incomplete.
+func (pgb *ChainDB) StoreMPData(stakeData *mempool.StakeData, txs []exptypes.MempoolTx, _ *exptypes.MempoolInfo) {
+	if pgb.InBatchSync || stakeData == nil {
+		return
+	}
+
+	height := stakeData.LatestBlock.Height
+	numRemoved, err := storeMempoolTxs(pgb.db, txs, stakeData.Time, height)
+	if err != nil {
+		log.Errorf("Failed to store mempool transactions: %v", err)
+		return
+	}
+
+	log.Debugf("Stored %d mempool transactions (%d removed) at height %d.",
+		len(txs), numRemoved, height)
+
+	if pgb.mempoolHistoryKeep <= 0 {
+		return
+	}
+	numTxs, numSnaps, err := deleteMempoolHistoryBefore(pgb.db,
+		stakeData.Time.Add(-pgb.mempoolHistoryKeep))
+	if err != nil {
+		log.Errorf("Failed to delete old mempool history: %v", err)
+		return
+	}
+	if numTxs > 0 || numSnaps > 0 {
+		log.Debugf("Deleted %d old mempool transactions and %d fee snapshots.",
+			numTxs, numSnaps)
+	}
+}
+
+// StoreMPTx records a transaction as it enters mempool, satisfying the
+// mempool.MempoolTxSaver interface. Its removal from mempool is recorded by
+// StoreMPData. Nothing is stored during batch sync.
+func (pgb *ChainDB) StoreMPTx(tx *exptypes.MempoolTx) {
+	if pgb.InBatchSync || tx == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	if err := insertMempoolTx(ctx, pgb.db, tx); err != nil {
+		log.Errorf("Failed to store mempool transaction %s: %v", tx.Hash,
+			pgb.replaceCancelError(err))
+	}
+}
+
+// MempoolTxHistory retrieves the mempool entry and exit times, and the reason
+// for the exit, of the transaction with the given hash. sql.ErrNoRows is
+// returned if the transaction was never recorded in mempool.
+func (pgb *ChainDB) MempoolTxHistory(txHash string) (*dbtypes.MempoolTxHistory, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	h, err := retrieveMempoolTxHistory(ctx, pgb.db, txHash)
+	return h, pgb.replaceCancelError(err)
+}
+
+// MempoolFeeSnapshots retrieves up to limit mempool fee rate snapshots taken
+// in the time range [from, to].
+func (pgb *ChainDB) MempoolFeeSnapshots(from, to time.Time, limit int) ([]*dbtypes.MempoolFeeSnapshot, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	snaps, err := retrieveMempoolFeeSnapshots(ctx, pgb.db, from, to, limit)
+	return snaps, pgb.replaceCancelError(err)
+}
+
+// MempoolFeeSnapshotAt retrieves the most recent mempool fee rate snapshot
+// taken at or before the given time. sql.ErrNoRows is returned if there is no
+// such snapshot.
+func (pgb *ChainDB) MempoolFeeSnapshotAt(t time.Time) (*dbtypes.MempoolFeeSnapshot, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	snap, err := retrieveMempoolFeeSnapshotAt(ctx, pgb.db, t)
+	return snap, pgb.replaceCancelError(err)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_makeMempoolFeeSnapshot(t *testing.T) {
+	txs := []mempoolTxFee{
+		{txType: "Regular", size: 300, feeRate: 0.0001},
+		{txType: "Ticket", size: 300, feeRate: 0.001},
+		{txType: "Regular", size: 200, feeRate: 0.0015},
+		// Votes are excluded.
+		{txType: "Vote", size: 350, feeRate: 0},
+	}
+
+	snap := makeMempoolFeeSnapshot(txs, time.Unix(1554000000, 0), 1234)
+	if snap.Height != 1234 {
+		t.Errorf("incorrect height %d", snap.Height)
+	}
+	if snap.NumTxs != 3 {
+		t.Errorf("expected 3 transactions, got %d", snap.NumTxs)
+	}
+	if snap.TotalSize != 800 {
+		t.Errorf("expected total size 800, got %d", snap.TotalSize)
+	}
+
+	wantCounts := []uint64{0, 1, 0, 0, 2, 0, 0, 0, 0, 0, 0}
+	if !reflect.DeepEqual(snap.Counts, wantCounts) {
+		t.Errorf("incorrect counts %v, expected %v", snap.Counts, wantCounts)
+	}
+	wantSizes := []uint64{0, 300, 0, 0, 500, 0, 0, 0, 0, 0, 0}
+	if !reflect.DeepEqual(snap.Sizes, wantSizes) {
+		t.Errorf("incorrect sizes %v, expected %v", snap.Sizes, wantSizes)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/wire"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+// miningPoolRegistry is the mutex-protected list of known mining pools.
+type miningPoolRegistry struct {
+	mtx   sync.RWMutex
+	pools []*dbtypes.MiningPool
+}
+
+// miningPoolWindowCount is the number of blocks mined by a pool in one ticket
+// price window.
+type miningPoolWindowCount struct {
+	window int64
+	pool   string
+	count  int64
+}
+
+// newBlockMiner extracts the coinbase data of a block. The pool is not set.
+func newBlockMiner(msgBlock *wire.MsgBlock, params *chaincfg.Params) *dbtypes.BlockMiner {
+	miner := &dbtypes.BlockMiner{
+		Hash:   msgBlock.BlockHash().String(),
+		Height: int64(msgBlock.Header.Height),
+	}
+	if len(msgBlock.Transactions) == 0 {
+		return miner
+	}
+	coinbase := msgBlock.Transactions[0]
+	if len(coinbase.TxIn) > 0 {
+		miner.CoinbaseText = txhelpers.CoinbaseText(coinbase.TxIn[0].SignatureScript)
+	}
+	miner.PayoutAddresses = txhelpers.CoinbasePayoutAddresses(coinbase, params)
+	return miner
+}
+
+// attributeMiner finds the name of the first of the pools that mined a block
+// with the given coinbase data, returning an empty string if the miner is
+// unknown.
+func attributeMiner(pools []*dbtypes.MiningPool, text string, addresses []string) string {
+	for _, pool := range pools {
+		if pool.Matches(text, addresses) {
+			return pool.Name
+		}
+	}
+	return ""
+}
+
+// miningPoolsBatchSize is the number of blocks reattributed to mining pools by
+// each UPDATE.
+const miningPoolsBatchSize = 10000
+
+// miningPoolsHash is the hash of the mining pools, in order, which identifies
+// the pools that the stored blocks are attributed to.
+func miningPoolsHash(pools []*dbtypes.MiningPool) string {
+	b, _ := json.Marshal(pools)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// SetMiningPools sets the known mining pools. If the pools differ from those
+// that the stored blocks were last attributed to, the blocks whose mining pool
+// changed are reattributed in the background.
+func (pgb *ChainDB) SetMiningPools(pools []*dbtypes.MiningPool) {
+	if pgb == nil {
+		return
+	}
+	pgb.miningPools.mtx.Lock()
+	pgb.miningPools.pools = pools
+	pgb.miningPools.mtx.Unlock()
+
+	go pgb.reattributeBlockMiners(pools)
+}
+
+// reattributeBlockMiners reattributes the stored blocks whose mining pool
+// changed, in batches of miningPoolsBatchSize blocks, unless the blocks were
+// already attributed to the given pools.
+func (pgb *ChainDB) reattributeBlockMiners(pools []*dbtypes.MiningPool) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	poolsHash := miningPoolsHash(pools)
+	lastHash, err := retrieveMiningPoolsHash(ctx, pgb.db)
+	if err != nil {
+		log.Errorf("Failed to retrieve mining pools hash: %v", pgb.replaceCancelError(err))
+		return
+	}
+	if lastHash == poolsHash {
+		return
+	}
+
+	miners, err := retrieveAllBlockMiners(ctx, pgb.db)
+	if err != nil {
+		log.Errorf("Failed to retrieve block miners: %v", pgb.replaceCancelError(err))
+		return
+	}
+
+	var changed []*dbtypes.BlockMiner
+	for _, miner := range miners {
+		pool := attributeMiner(pools, miner.CoinbaseText, miner.PayoutAddresses)
+		if pool != miner.Pool {
+			miner.Pool = pool
+			changed = append(changed, miner)
+		}
+	}
+	for start := 0; start < len(changed); start += miningPoolsBatchSize {
+		end := start + miningPoolsBatchSize
+		if end > len(changed) {
+			end = len(changed)
+		}
+		if err = updateBlockMinerPools(pgb.db, changed[start:end]); err != nil {
+			log.Errorf("Failed to reattribute blocks to mining pools: %v", err)
+			return
+		}
+	}
+
+	if err = storeMiningPoolsHash(pgb.db, poolsHash); err != nil {
+		log.Errorf("Failed to store mining pools hash: %v", err)
+		return
+	}
+	if len(changed) > 0 {
+		log.Infof("Reattributed %d blocks to mining pools.", len(changed))
+	}
+}
+
+// MiningPools returns the known mining pools.
+func (pgb *ChainDB) MiningPools() []*dbtypes.MiningPool {
+	pgb.miningPools.mtx.RLock()
+	defer pgb.miningPools.mtx.RUnlock()
+	return pgb.miningPools.pools
+}
+
+// StoreBlockMiner stores the coinbase data of the block and the mining pool
+// that mined it.
+func (pgb *ChainDB) StoreBlockMiner(msgBlock *wire.MsgBlock) error {
+	miner := newBlockMiner(msgBlock, pgb.chainParams)
+	miner.Pool = attributeMiner(pgb.MiningPools(), miner.CoinbaseText,
+		miner.PayoutAddresses)
+	return insertBlockMiner(pgb.db, miner)
+}
+
+// BlockMiner retrieves the coinbase data and mining pool of the block with the
+// given hash. sql.ErrNoRows is returned if the block is not stored.
+func (pgb *ChainDB) BlockMiner(hash string) (*dbtypes.BlockMiner, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	miner, err := retrieveBlockMiner(ctx, pgb.db, hash)
+	return miner, pgb.replaceCancelError(err)
+}
+
+// MiningPoolStats retrieves the number of blocks mined by each mining pool in
+// the last window main chain blocks, most blocks first. Blocks from unknown
+// miners are counted under an empty pool name.
+func (pgb *ChainDB) MiningPoolStats(window int64) ([]*dbtypes.MiningPoolStats, error) {
+	height := pgb.Height()
+	if window > height+1 {
+		window = height + 1
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	stats, err := retrieveMiningPoolStats(ctx, pgb.db, height-window)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	miningPoolShares(stats, window)
+	return stats, nil
+}
+
+// miningPoolShares sets the window and shares of the pools, and sorts them by
+// the number of blocks mined, most first.
+func miningPoolShares(stats []*dbtypes.MiningPoolStats, window int64) {
+	for _, s := range stats {
+		s.Window = window
+		if window > 0 {
+			s.Share = float64(s.Blocks) / float64(window)
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Blocks == stats[j].Blocks {
+			return stats[i].Pool < stats[j].Pool
+		}
+		return stats[i].Blocks > stats[j].Blocks
+	})
+}
+
+// MiningPoolChart retrieves the number of blocks mined by each mining pool in
+// each ticket price window.
+func (pgb *ChainDB) MiningPoolChart() (*dbtypes.MiningPoolChart, error) {
+	windowSize := pgb.chainParams.StakeDiffWindowSize
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	counts, err := retrieveMiningPoolWindows(ctx, pgb.db, windowSize)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	lastWindow := pgb.Height() / windowSize
+	return miningPoolChart(counts, lastWindow, windowSize), nil
+}
+
+// miningPoolChart makes the MiningPoolChart from the block counts by pool and
+// window, from the first window with a block through the window lastWindow.
+// The pools are ordered by the total number of blocks mined, most first.
+func miningPoolChart(counts []miningPoolWindowCount, lastWindow, windowSize int64) *dbtypes.MiningPoolChart {
+	chart := &dbtypes.MiningPoolChart{
+		Height: []int64{},
+		Pools:  []string{},
+		Blocks: [][]int64{},
+	}
+	if len(counts) == 0 {
+		return chart
+	}
+
+	firstWindow := lastWindow + 1
+	totals := make(map[string]int64)
+	for _, c := range counts {
+		if c.window > lastWindow {
+			continue
+		}
+		if c.window < firstWindow {
+			firstWindow = c.window
+		}
+		totals[c.pool] += c.count
+	}
+	if len(totals) == 0 {
+		return chart
+	}
+
+	for pool := range totals {
+		chart.Pools = append(chart.Pools, pool)
+	}
+	sort.Slice(chart.Pools, func(i, j int) bool {
+		pi, pj := chart.Pools[i], chart.Pools[j]
+		if totals[pi] == totals[pj] {
+			return pi < pj
+		}
+		return totals[pi] > totals[pj]
+	})
+
+	n := lastWindow - firstWindow + 1
+	index := make(map[string]int, len(chart.Pools))
+	for i, pool := range chart.Pools {
+		index[pool] = i
+		chart.Blocks = append(chart.Blocks, make([]int64, n))
+	}
+	for i := int64(0); i < n; i++ {
+		chart.Height = append(chart.Height, (firstWindow+i)*windowSize)
+	}
+	for _, c := range counts {
+		if c.window > lastWindow {
+			continue
+		}
+		chart.Blocks[index[c.pool]][c.window-firstWindow] += c.count
+	}
+	return chart
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestAttributeMiner(t *testing.T) {
+	pools := []*dbtypes.MiningPool{
+		{Name: "a", Tags: []string{"/PoolA/"}},
+		{Name: "b", Tags: []string{"poolb"}, Addresses: []string{"Dsb"}},
+	}
+	tests := []struct {
+		text      string
+		addresses []string
+		pool      string
+	}{
+		{"/poola/ fnod", nil, "a"},
+		{"mined by PoolB", nil, "b"},
+		{"", []string{"Dsx", "Dsb"}, "b"},
+		{"/PoolA/ PoolB", []string{"Dsb"}, "a"},
+		{"solo", []string{"Dsx"}, ""},
+	}
+	for _, test := range tests {
+		if pool := attributeMiner(pools, test.text, test.addresses); pool != test.pool {
+			t.Errorf("attributeMiner(%q, %v): got %q, want %q", test.text,
+				test.addresses, pool, test.pool)
+		}
+	}
+}
+
+func TestMiningPoolShares(t *testing.T) {
+	stats := []*dbtypes.MiningPoolStats{
+		{Pool: "", Blocks: 10},
+		{Pool: "b", Blocks: 30},
+		{Pool: "a", Blocks: 10},
+	}
+	miningPoolShares(stats, 50)
+	if stats[0].Pool != "b" || stats[1].Pool != "" || stats[2].Pool != "a" {
+		t.Fatalf("unexpected order %v, %v, %v", stats[0].Pool, stats[1].Pool, stats[2].Pool)
+	}
+	if stats[0].Share != 0.6 || stats[2].Share != 0.2 || stats[2].Window != 50 {
+		t.Errorf("unexpected stats %+v", stats[2])
+	}
+}
+
+func TestMiningPoolChart(t *testing.T) {
+	counts := []miningPoolWindowCount{
+		{window: 1, pool: "a", count: 3},
+		{window: 1, pool: "", count: 1},
+		{window: 3, pool: "b", count: 2},
+		{window: 3, pool: "a", count: 1},
+		// After the last window.
+		{window: 5, pool: "b", count: 9},
+	}
+	chart := miningPoolChart(counts, 3, 10)
+
+	want := &dbtypes.MiningPoolChart{
+		Height: []int64{10, 20, 30},
+		Pools:  []string{"a", "b", ""},
+		Blocks: [][]int64{
+			{3, 0, 1},
+			{0, 0, 2},
+			{1, 0, 0},
+		},
+	}
+	if !reflect.DeepEqual(chart, want) {
+		t.Errorf("got %+v, want %+v", chart, want)
+	}
+
+	empty := miningPoolChart(nil, 3, 10)
+	if empty.Height == nil || len(empty.Height) != 0 || len(empty.Pools) != 0 {
+		t.Errorf("expected an empty chart, got %+v", empty)
+	}
+}
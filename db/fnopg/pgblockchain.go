@@ -231,6 +231,19 @@ type ChainDB struct {
 	deployments        *ChainDeployments
 	piparser           ProposalsFetcher
 	proposalsSync      lastSync
+	pruneKeepBlocks    int64
+	lastPruneHeight    int64
+	pruneLock          trylock.Mutex
+	utxoAgesLock       trylock.Mutex
+	utxoAgesRewinds    rewindCounter
+	stakePools         stakePoolRegistry
+	miningPools        miningPoolRegistry
+	addressLabels      addressLabelRegistry
+	treasury           treasuryCache
+	xpubs              xpubCache
+	feeRatesChartCheck sync.Once
+	mempoolHistoryKeep time.Duration
+	bg                 BlockGetter
 }
 
 // ChainDeployments is mutex-protected blockchain deployment data.
@@ -563,7 +576,7 @@ func NewChainDBWithCancel(ctx context.Context, dbi *DBInfo, params *chaincfg.Par
 		}
 		// Do upgrades required by meta table versioning.
 		log.Infof("DB schema version %v upgrading to version %v", dbVer, targetDatabaseVersion)
-		success, err := UpgradeDatabase(db, bg)
+		success, err := UpgradeDatabase(db, bg, params)
 		if err != nil {
 			return nil, fmt.Errorf("failed to upgrade database: %v", err)
 		}
@@ -741,6 +754,9 @@ func NewChainDBWithCancel(ctx context.Context, dbi *DBInfo, params *chaincfg.Par
 		deployments:        new(ChainDeployments),
 		piparser:           parser,
 	}
+	if bg != nil && !reflect.ValueOf(bg).IsNil() {
+		chainDB.bg = bg
+	}
 
 	// If loading a DB with the legacy versioning system, fully upgrade prior to
 	// migrating to meta table versioning.
@@ -768,7 +784,7 @@ func NewChainDBWithCancel(ctx context.Context, dbi *DBInfo, params *chaincfg.Par
 
 		// Now run any upgrades from legacyDatabaseVersion to
 		// targetDatabaseVersion.
-		success, err := UpgradeDatabase(db, bg)
+		success, err := UpgradeDatabase(db, bg, params)
 		if err != nil {
 			return chainDB, fmt.Errorf("failed to upgrade legacy database: %v", err)
 		} else if !success {
@@ -836,6 +852,10 @@ func versionCheck(db *sql.DB) (*DatabaseVersion, CompatAction, error) {
 		if tableName == "meta" {
 			continue
 		}
+		// Tables from schema upgrades are created by UpgradeDatabase.
+		if _, isUpgradeTable := upgradeTables[tableName]; isUpgradeTable {
+			continue
+		}
 		exists, err := TableExists(db, tableName)
 		if err != nil {
 			return nil, Unknown, err
@@ -961,6 +981,48 @@ func (pgb *ChainDB) RegisterCharts(charts *cache.ChartData) {
 		Fetcher:  pgb.windowStats,
 		Appender: appendWindowStats,
 	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "nulldata",
+		Fetcher:  pgb.nullDataChart,
+		Appender: appendNullDataChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "missed votes",
+		Fetcher:  pgb.missedVotesChart,
+		Appender: appendMissedVotesChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "supply",
+		Fetcher:  pgb.supplyChart,
+		Appender: appendSupplyChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "fee rates",
+		Fetcher:  pgb.feeRatesChart,
+		Appender: appendFeeRatesChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "address usage",
+		Fetcher:  pgb.addressUsageChart,
+		Appender: appendAddressUsageChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "utxo set",
+		Fetcher:  pgb.utxoSetChart,
+		Appender: appendUTXOSetChart,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "coin-days destroyed",
+		Fetcher:  pgb.coinDaysDestroyedChart,
+		Appender: appendCoinDaysDestroyedChart,
+	})
 }
 
 // TransactionBlocks retrieves the blocks in which the specified transaction
@@ -1234,6 +1296,15 @@ func (pgb *ChainDB) ProposalVotes(proposalToken string) (*dbtypes.ProposalCharts
 	return chartsData, pgb.replaceCancelError(err)
 }
 
+// ProposalVotesTimeline retrieves the cumulative vote tallies of the proposal
+// with the provided token at block resolution.
+func (pgb *ChainDB) ProposalVotesTimeline(proposalToken string) (*dbtypes.ProposalVotesTimeline, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	timeline, err := retrieveProposalVotesTimeline(ctx, pgb.db, proposalToken)
+	return timeline, pgb.replaceCancelError(err)
+}
+
 // SpendingTransactions retrieves all transactions spending outpoints from the
 // specified funding transaction. The spending transaction hashes, the spending
 // tx input indexes, and the corresponding funding tx output indexes, and an
@@ -1304,6 +1375,36 @@ func (pgb *ChainDB) TicketMiss(ticketHash string) (string, int64, error) {
 	return blockHash, blockHeight, pgb.replaceCancelError(err)
 }
 
+// MissRates retrieves the numbers of votes and missed votes, and the miss rate,
+// in each interval since stake validation began. The grouping may be a time
+// grouping (day, week, month, or year) or "window" for ticket price windows.
+func (pgb *ChainDB) MissRates(grouping string) ([]*dbtypes.MissRate, error) {
+	if grouping != "window" {
+		interval := dbtypes.TimeGroupingFromStr(grouping)
+		if interval == dbtypes.AllGrouping || interval == dbtypes.UnknownGrouping {
+			return nil, fmt.Errorf("invalid miss rate grouping %q", grouping)
+		}
+		grouping = interval.String()
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rates, err := retrieveMissRates(ctx, pgb.db, grouping,
+		pgb.chainParams.StakeValidationHeight, pgb.chainParams.StakeDiffWindowSize)
+	return rates, pgb.replaceCancelError(err)
+}
+
+// UnrevokedTickets retrieves up to limit of the main chain tickets that missed
+// or expired and are not yet revoked, skipping offset, and the total number of
+// such tickets. Unless empty, votingAddress and rewardAddress filter the
+// tickets by voting address and by the reward addresses of their commitments.
+func (pgb *ChainDB) UnrevokedTickets(votingAddress, rewardAddress string, limit, offset int) ([]*dbtypes.UnrevokedTicket, int64, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	tickets, total, err := retrieveUnrevokedTickets(ctx, pgb.db, votingAddress,
+		rewardAddress, limit, offset)
+	return tickets, total, pgb.replaceCancelError(err)
+}
+
 // PoolStatusForTicket retrieves the specified ticket's spend status and ticket
 // pool status, and an error value.
 func (pgb *ChainDB) PoolStatusForTicket(txid string) (dbtypes.TicketSpendType, dbtypes.TicketPoolStatus, error) {
@@ -1406,6 +1507,57 @@ func (pgb *ChainDB) AgendaVoteCounts(agendaID string) (yes, abstain, no uint32,
 		agendaInfo.VotingStarted, agendaInfo.VotingDone)
 }
 
+// AgendaVotesByAddress fetches the vote choice counts for the provided agenda
+// grouped by the voting address of the tickets that voted. At most limit
+// addresses, ordered by the number of votes cast, are returned.
+func (pgb *ChainDB) AgendaVotesByAddress(agendaID string, limit int) ([]*dbtypes.AgendaAddressVotes, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	chainInfo := pgb.ChainInfo()
+	agendaInfo := chainInfo.AgendaMileStones[agendaID]
+
+	// Check if starttime is in the future and exit if true.
+	if time.Now().Before(agendaInfo.StartTime) {
+		return nil, nil
+	}
+
+	addrVotes, err := retrieveAgendaVotesByAddress(ctx, pgb.db, agendaID,
+		agendaInfo.VotingStarted, agendaInfo.VotingDone, limit)
+	return addrVotes, pgb.replaceCancelError(err)
+}
+
+// AgendaVotesCsv grabs the per-block vote choice counts for the provided
+// agenda as a 2-D array of strings to be CSV-formatted.
+func (pgb *ChainDB) AgendaVotesCsv(agendaID string) ([][]string, error) {
+	avc, err := pgb.AgendaVotes(agendaID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return MakeCsvAgendaVotes(avc), nil
+}
+
+// MakeCsvAgendaVotes converts per-block agenda vote choice counts into a 2-D
+// array of strings to be CSV-formatted. The first row contains the headers.
+func MakeCsvAgendaVotes(avc *dbtypes.AgendaVoteChoices) [][]string {
+	csvRows := [][]string{{"height", "yes", "abstain", "no", "total"}}
+	if avc == nil {
+		return csvRows
+	}
+
+	for i := range avc.Height {
+		csvRows = append(csvRows, []string{
+			strconv.FormatUint(avc.Height[i], 10),
+			strconv.FormatUint(avc.Yes[i], 10),
+			strconv.FormatUint(avc.Abstain[i], 10),
+			strconv.FormatUint(avc.No[i], 10),
+			strconv.FormatUint(avc.Total[i], 10),
+		})
+	}
+	return csvRows
+}
+
 // AllAgendas returns all the agendas stored currently.
 func (pgb *ChainDB) AllAgendas() (map[string]dbtypes.MileStone, error) {
 	return retrieveAllAgendas(pgb.db)
@@ -2133,7 +2285,7 @@ func (pgb *ChainDBRPC) AddressData(address string, limitN, offsetAddrOuts int64,
 			// Empty history is not expected for credit or all txnType with any
 			// txns. i.e. Empty history is OK for debit views (merged or not).
 			if (txnType != dbtypes.AddrTxnDebit && txnType != dbtypes.AddrMergedTxnDebit) &&
-				(balance.NumSpent+balance.NumUnspent) > 0 {
+				(balance.NumSpent+balance.NumUnspent-balance.NumPrunedSpent()) > 0 {
 				log.Debugf("empty address history (%s) for view %s: n=%d&start=%d",
 					address, txnType.String(), limitN, offsetAddrOuts)
 				return nil, fmt.Errorf("that address has no history")
@@ -2161,15 +2313,18 @@ func (pgb *ChainDBRPC) AddressData(address string, limitN, offsetAddrOuts int64,
 			}
 			addrData.TxnCount = int64(count)
 		} else {
-			// For non-merged views, use the balance data.
+			// For non-merged views, use the balance data. The funding and
+			// spending rows of pruned outpoints are no longer listed.
+			numPruned := balance.NumPrunedSpent()
 			switch txnType {
 			case dbtypes.AddrTxnAll:
-				addrData.TxnCount = addrData.KnownFundingTxns + addrData.KnownSpendingTxns
+				addrData.TxnCount = addrData.KnownFundingTxns + addrData.KnownSpendingTxns -
+					2*numPruned
 			case dbtypes.AddrTxnCredit:
-				addrData.TxnCount = addrData.KnownFundingTxns
+				addrData.TxnCount = addrData.KnownFundingTxns - numPruned
 				addrData.Transactions = addrData.TxnsFunding
 			case dbtypes.AddrTxnDebit:
-				addrData.TxnCount = addrData.KnownSpendingTxns
+				addrData.TxnCount = addrData.KnownSpendingTxns - numPruned
 				addrData.Transactions = addrData.TxnsSpending
 			}
 		}
@@ -2431,9 +2586,88 @@ func (pgb *ChainDB) AddressTotals(address string) (*apitypes.AddressTotals, erro
 		NumUnspent:   ab.NumUnspent,
 		CoinsSpent:   fnoutil.Amount(ab.TotalSpent).ToCoin(),
 		CoinsUnspent: fnoutil.Amount(ab.TotalUnspent).ToCoin(),
+
+		HistoryTruncated: ab.HistoryTruncated(),
 	}, nil
 }
 
+// AddressBalanceAtTime computes the balance of the address as of the given
+// time from the mainchain address history, including any pruned history.
+// dbtypes.ErrAddressHistoryPruned is returned if the history before the given
+// time has been pruned.
+func (pgb *ChainDB) AddressBalanceAtTime(address string, t time.Time) (*apitypes.AddressBalanceAt, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	summary, err := retrieveAddressSummary(ctx, pgb.db, address)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if summary != nil && t.Before(summary.PrunedBefore.T) {
+		return nil, dbtypes.ErrAddressHistoryPruned
+	}
+
+	received, sent, err := retrieveAddressReceivedSentAtTime(ctx, pgb.db, address, t)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	return addressBalanceAt(address, dbtypes.NewTimeDef(t), received, sent, summary), nil
+}
+
+// AddressBalanceAtHeight computes the balance of the address as of the end of
+// the mainchain block at the given height from the address history of valid
+// mainchain blocks up to that height, including any pruned history.
+// sql.ErrNoRows is returned if there is no mainchain block at the height, and
+// dbtypes.ErrAddressHistoryPruned is returned if the history before the
+// block's time has been pruned.
+func (pgb *ChainDB) AddressBalanceAtHeight(address string, height int64) (*apitypes.AddressBalanceAt, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	blockTime, err := RetrieveBlockTimeByHeight(ctx, pgb.db, height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	summary, err := retrieveAddressSummary(ctx, pgb.db, address)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if summary != nil && blockTime.T.Before(summary.PrunedBefore.T) {
+		return nil, dbtypes.ErrAddressHistoryPruned
+	}
+
+	received, sent, err := retrieveAddressReceivedSentAtHeight(ctx, pgb.db, address, height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	balance := addressBalanceAt(address, blockTime, received, sent, summary)
+	balance.BlockHeight = height
+	return balance, nil
+}
+
+// addressBalanceAt creates the balance of the address at time t from the
+// amounts, in atoms, received and sent in its unpruned history. The pruned
+// history in the address summary, which is entirely before t, is included.
+func addressBalanceAt(address string, t dbtypes.TimeDef, received, sent int64,
+	summary *dbtypes.AddressSummary) *apitypes.AddressBalanceAt {
+	if summary != nil {
+		// The pruned history consists of spent outputs, so the amounts
+		// received and sent are both the pruned amount spent.
+		received += summary.TotalSpent
+		sent += summary.TotalSpent
+	}
+	return &apitypes.AddressBalanceAt{
+		Address:       address,
+		Time:          t,
+		CoinsReceived: fnoutil.Amount(received).ToCoin(),
+		CoinsSent:     fnoutil.Amount(sent).ToCoin(),
+		Balance:       fnoutil.Amount(received - sent).ToCoin(),
+	}
+}
+
 // MakeCsvAddressRows converts an AddressRow slice into a [][]string, including
 // column headers, suitable for saving to CSV.
 func MakeCsvAddressRows(rows []*dbtypes.AddressRow) [][]string {
@@ -2586,8 +2820,10 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 	// Generate AddressInfo skeleton from the address table rows
 	addrData, _, _ := dbtypes.ReduceAddressHistory(addrHist)
 	if addrData == nil {
-		// Empty history is not expected for credit txnType with any txns.
-		if txnType != dbtypes.AddrTxnDebit && (balance.NumSpent+balance.NumUnspent) > 0 {
+		// Empty history is not expected for credit txnType with any txns,
+		// excluding those removed by archival pruning.
+		numRows := balance.NumSpent + balance.NumUnspent - balance.NumPrunedSpent()
+		if txnType != dbtypes.AddrTxnDebit && numRows > 0 {
 			return nil, nil, fmt.Errorf("empty address history (%s): n=%d&start=%d", address, count, skip)
 		}
 		// No mined transactions. Return Address with nil Transactions slice.
@@ -2623,15 +2859,17 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
 	txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error) {
 	// Fetch address history for given transaction range and type
-	addrData, _, err := pgb.addressInfo(addr, count, skip, txnType)
+	addrData, balance, err := pgb.addressInfo(addr, count, skip, txnType)
 	if err != nil {
 		return nil, err
 	}
+	truncated := balance != nil && balance.HistoryTruncated()
 	// No transactions found. Not an error.
 	if addrData == nil {
 		return &apitypes.Address{
-			Address:      addr,
-			Transactions: make([]*apitypes.AddressTxShort, 0), // not nil for JSON formatting
+			Address:          addr,
+			Transactions:     make([]*apitypes.AddressTxShort, 0), // not nil for JSON formatting
+			HistoryTruncated: truncated,
 		}, nil
 	}
 
@@ -2650,8 +2888,9 @@ func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
 
 	// put a bow on it
 	return &apitypes.Address{
-		Address:      addr,
-		Transactions: txsShort,
+		Address:          addr,
+		Transactions:     txsShort,
+		HistoryTruncated: truncated,
 	}, nil
 }
 
@@ -2794,6 +3033,13 @@ func (pgb *ChainDB) PurgeBestBlocks(N int64) (*dbtypes.DeletionSummary, int64, e
 		return nil, height, pgb.replaceCancelError(err)
 	}
 
+	// Remove the UTXO age distributions measured after the new best block.
+	if height >= 0 {
+		if err = pgb.RewindUTXOAges(height); err != nil {
+			return nil, height, fmt.Errorf("RewindUTXOAges: %v", err)
+		}
+	}
+
 	summary := dbtypes.DeletionSummarySlice(res).Reduce()
 
 	return &summary, height, err
@@ -2916,6 +3162,176 @@ func (pgb *ChainDB) coinSupply(charts *cache.ChartData) (*sql.Rows, func(), erro
 	return rows, cancel, nil
 }
 
+// nullDataChart fetches the nulldata outputs chart data from
+// retrieveNullDataChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendNullDataChart.
+func (pgb *ChainDB) nullDataChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveNullDataChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("nullDataChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// missedVotesChart fetches the missed votes chart data from
+// retrieveMissedVotesChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendMissedVotesChart.
+func (pgb *ChainDB) missedVotesChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveMissedVotesChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("missedVotesChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// supplyChart fetches the burned amounts and project fund flows chart data from
+// retrieveSupplyChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendSupplyChart.
+func (pgb *ChainDB) supplyChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	zeroAddress, err := txhelpers.ZeroHashP2PHKAddress(pgb.chainParams)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("supplyChart: %v", err)
+	}
+	rows, err := retrieveSupplyChart(ctx, pgb.db, charts, pgb.devAddress, zeroAddress)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("supplyChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// feeRatesChart fetches the block fee rate statistics chart data from
+// retrieveFeeRatesChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendFeeRatesChart.
+func (pgb *ChainDB) feeRatesChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	// The fee rates of blocks stored before they were introduced may have been
+	// computed since the charts cache was saved (see rebuilddb2's --feerates
+	// option), so the cached fee rates are checked against the DB once.
+	pgb.feeRatesChartCheck.Do(func() {
+		tip, count := charts.FeeRatesCount()
+		if tip < 0 {
+			return
+		}
+		dbCount, err := retrieveFeeRatesCount(ctx, pgb.db, tip)
+		if err != nil {
+			log.Warnf("Unable to check the cached fee rate charts: %v",
+				pgb.replaceCancelError(err))
+			return
+		}
+		if dbCount != count {
+			log.Infof("The cached fee rate charts describe %d transactions, but "+
+				"the DB has fee rates for %d transactions through height %d, "+
+				"likely computed by rebuilddb2 --feerates. Reloading the fee "+
+				"rate charts.", count, dbCount, tip)
+			charts.ClearFeeRates()
+		}
+	})
+
+	rows, err := retrieveFeeRatesChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("feeRatesChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// addressUsageChart fetches the active and new address chart data from
+// retrieveAddressUsageChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendAddressUsageChart.
+func (pgb *ChainDB) addressUsageChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveAddressUsageChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("addressUsageChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// utxoSetChart fetches the UTXO set and transaction value chart data from
+// retrieveUTXOSetChart. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendUTXOSetChart.
+func (pgb *ChainDB) utxoSetChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveUTXOSetChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("utxoSetChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// coinDaysDestroyedChart fetches the coin-days destroyed chart data from
+// retrieveCoinDaysDestroyedChart. This is the Fetcher half of a pair that make
+// up a cache.ChartUpdater. The Appender half is appendCoinDaysDestroyedChart.
+func (pgb *ChainDB) coinDaysDestroyedChart(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveCoinDaysDestroyedChart(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("coinDaysDestroyedChart: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// SearchNullData retrieves up to limit main chain nulldata (OP_RETURN) outputs,
+// skipping offset, with payloads that begin with (if prefix is true) or
+// contain the given data. The most recent outputs are first. Substring
+// searches are only performed if the payload trigram index exists, otherwise
+// dbtypes.ErrNullDataSubstringsUnindexed is returned.
+func (pgb *ChainDB) SearchNullData(data []byte, prefix bool, limit, offset int) ([]*dbtypes.NullData, error) {
+	if !prefix {
+		indexed, err := ExistsIndex(pgb.db, internal.IndexOfNullDataTableOnPayloadTrgm)
+		if err != nil {
+			return nil, err
+		}
+		if !indexed {
+			return nil, dbtypes.ErrNullDataSubstringsUnindexed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	outputs, err := retrieveNullData(ctx, pgb.db, data, prefix, limit, offset)
+	return outputs, pgb.replaceCancelError(err)
+}
+
+// TicketCommitments retrieves up to limit commitments of main chain tickets to
+// the given reward address, skipping offset, and the total number of such
+// commitments. The most recent tickets are first.
+func (pgb *ChainDB) TicketCommitments(address string, limit, offset int) ([]*dbtypes.TicketCommitment, int64, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	commitments, total, err := retrieveTicketCommitmentsByAddress(ctx, pgb.db,
+		address, limit, offset)
+	return commitments, total, pgb.replaceCancelError(err)
+}
+
+// RedeemScript retrieves the decoded redeem script of a P2SH address that has
+// been spent from. sql.ErrNoRows is returned if the address has never been
+// spent from.
+func (pgb *ChainDB) RedeemScript(address string) (*dbtypes.RedeemScript, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rs, err := retrieveRedeemScript(ctx, pgb.db, address)
+	return rs, pgb.replaceCancelError(err)
+}
+
+// RedeemScriptsByAddress retrieves the decoded redeem scripts that include the
+// given address, such as the multisig scripts with one of its public keys.
+func (pgb *ChainDB) RedeemScriptsByAddress(address string) ([]*dbtypes.RedeemScript, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	scripts, err := retrieveRedeemScriptsByAddress(ctx, pgb.db, address)
+	return scripts, pgb.replaceCancelError(err)
+}
+
 // txPerDay fetches the tx-per-day chart data from retrieveTxPerDay.
 func (pgb *ChainDB) txPerDay(timeArr []dbtypes.TimeDef, txCountArr []uint64) (
 	[]dbtypes.TimeDef, []uint64, error) {
@@ -3078,8 +3494,10 @@ func (pgb *ChainDB) VinsForTx(dbTx *dbtypes.Tx) ([]dbtypes.VinTxProperty, []stri
 	prevPkScripts := make([]string, 0, len(dbTx.VinDbIds))
 	versions := make([]uint16, 0, len(dbTx.VinDbIds))
 	for _, id := range dbTx.VinDbIds {
+		// The vin or its previous outpoint may have been removed by archival
+		// pruning, leaving the pkScript empty.
 		pkScript, ver, err := pgb.PkScriptByVinID(id)
-		if err != nil {
+		if err != nil && err != sql.ErrNoRows {
 			return nil, nil, nil, fmt.Errorf("PkScriptByVinID: %v", err)
 		}
 		prevPkScripts = append(prevPkScripts, hex.EncodeToString(pkScript))
@@ -3202,6 +3620,8 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, winningTickets []string,
 	numVins int64, numVouts int64, numAddresses int64, err error) {
 	// Convert the wire.MsgBlock to a dbtypes.Block
 	dbBlock := dbtypes.MsgBlockToDBBlock(msgBlock, pgb.chainParams, chainWork)
+	// Compute the fee rate statistics of the block's transactions.
+	dbBlock.FeeRates = dbtypes.MsgBlockFeeRates(msgBlock)
 
 	// Get the previous winners (stake DB pool info cache has this info). If the
 	// previous block is side chain, stakedb will not have the
@@ -3325,6 +3745,17 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, winningTickets []string,
 		return
 	}
 
+	// Store the block's committed filter for light wallets. Failure to do so
+	// does not prevent the block from being stored.
+	if errF := pgb.StoreBlockFilter(msgBlock); errF != nil {
+		log.Errorf("StoreBlockFilter (%v): %v", msgBlock.BlockHash(), errF)
+	}
+
+	// Store the block's coinbase data and mining pool.
+	if errM := pgb.StoreBlockMiner(msgBlock); errM != nil {
+		log.Errorf("StoreBlockMiner (%v): %v", msgBlock.BlockHash(), errM)
+	}
+
 	if isMainchain {
 		// Update best block height and hash.
 		pgb.bestBlock.mtx.Lock()
@@ -3344,6 +3775,54 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, winningTickets []string,
 		if err = pgb.FreshenAddressCaches(true, addresses); err != nil {
 			log.Warnf("FreshenAddressCaches: %v", err)
 		}
+
+		// Periodically prune old history if archival pruning is enabled.
+		if isMainchain && pgb.pruneKeepBlocks > 0 &&
+			int64(dbBlock.Height)-pgb.lastPruneHeight >= pruneIntervalBlocks {
+			pgb.lastPruneHeight = int64(dbBlock.Height)
+			go func() {
+				if _, _, err := pgb.PruneHistory(); err != nil {
+					log.Errorf("PruneHistory: %v", err)
+				}
+			}()
+		}
+
+		// Store the address usage, UTXO set changes and coin-days destroyed of
+		// the block, and of the previous block, which may have been
+		// disapproved by this block's votes. The cached charts are only
+		// extended, so they keep the values of the previous block from when it
+		// was stored until they are rebuilt from the DB.
+		if isMainchain {
+			height := int64(dbBlock.Height)
+			ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+			if errU := storeAddressUsage(ctx, pgb.db, height-1, height); errU != nil {
+				log.Errorf("storeAddressUsage (%v): %v", dbBlock.Hash,
+					pgb.replaceCancelError(errU))
+			}
+			if errS := storeUTXOSetChanges(ctx, pgb.db, height-1, height); errS != nil {
+				log.Errorf("storeUTXOSetChanges (%v): %v", dbBlock.Hash,
+					pgb.replaceCancelError(errS))
+			}
+			cancel()
+			if errC := storeCoinDaysDestroyed(pgb.db, height-1, height); errC != nil {
+				log.Errorf("storeCoinDaysDestroyed (%v): %v", dbBlock.Hash, errC)
+			}
+		}
+
+		// Store the UTXO age distributions of any newly completed days, and
+		// detect stake pools once per ticket price window.
+		if isMainchain {
+			go func() {
+				if err := pgb.UpdateUTXOAges(); err != nil {
+					log.Errorf("UpdateUTXOAges: %v", err)
+				}
+			}()
+			go func() {
+				if err := pgb.DetectStakePools(); err != nil {
+					log.Warnf("Stake pool detection failed: %v", err)
+				}
+			}()
+		}
 	}
 
 	return
@@ -3543,6 +4022,20 @@ func (pgb *ChainDB) storeTxns(txns []*dbtypes.Tx, vouts [][]*dbtypes.Vout, vins
 		Tx.Vouts = vouts[it]
 	}
 
+	// Index the payloads of any nulldata outputs.
+	if err = insertNullData(dbTx, vouts); err != nil {
+		err = fmt.Errorf("failure in insertNullData: %v", err)
+		_ = dbTx.Rollback()
+		return
+	}
+
+	// Decode the commitment outputs of any ticket purchases.
+	if err = insertTicketCommitments(dbTx, txns, pgb.chainParams); err != nil {
+		err = fmt.Errorf("failure in insertTicketCommitments: %v", err)
+		_ = dbTx.Rollback()
+		return
+	}
+
 	// Get the tx PK IDs for storage in the blocks, tickets, and votes table.
 	txDbIDs, err = InsertTxnsDbTxn(dbTx, txns, pgb.dupChecks, updateExistingRecords)
 	if err != nil && err != sql.ErrNoRows {
@@ -3791,6 +4284,7 @@ func (pgb *ChainDB) storeBlockTxnTree(msgBlock *MsgBlockPG, txTree int8,
 		txRes.addresses[ad.Address] = struct{}{}
 	}
 
+	var p2shVins []*dbtypes.VinTxProperty
 	for it, tx := range dbTransactions {
 		// vins array for this transaction
 		txVins := dbTxVins[it]
@@ -3816,7 +4310,7 @@ func (pgb *ChainDB) storeBlockTxnTree(msgBlock *MsgBlockPG, txTree int8,
 			if !ok {
 				log.Tracef("Data for that utxo (%s:%d) wasn't cached!", vin.PrevTxHash, vin.PrevTxIndex)
 			}
-			numAddressRowsSet, err := insertSpendingAddressRow(dbTx,
+			numAddressRowsSet, prevAddrs, err := insertSpendingAddressRow(dbTx,
 				vin.PrevTxHash, vin.PrevTxIndex, int8(vin.PrevTxTree),
 				spendingTxHash, spendingTxIndex, vinDbID, utxoData, pgb.dupChecks,
 				updateExistingRecords, validMainchain, vin.TxType, updateAddressesSpendingInfo,
@@ -3828,10 +4322,26 @@ func (pgb *ChainDB) storeBlockTxnTree(msgBlock *MsgBlockPG, txTree int8,
 				return txRes
 			}
 			txRes.numAddresses += numAddressRowsSet
+
+			// Only the spends of P2SH outputs reveal redeem scripts.
+			if len(prevAddrs) == 1 {
+				_, addrType, _ := txhelpers.AddressValidation(prevAddrs[0], pgb.chainParams)
+				if addrType == txhelpers.AddressTypeP2SH {
+					p2shVins = append(p2shVins, vin)
+				}
+			}
 		}
 	}
 
 	txRes.err = dbTx.Commit()
+	if txRes.err != nil {
+		return txRes
+	}
+
+	// Decode the redeem scripts revealed by the P2SH spends.
+	if err = insertRedeemScripts(pgb.db, p2shVins, pgb.chainParams); err != nil {
+		log.Errorf("insertRedeemScripts: %v", err)
+	}
 
 	return txRes
 }
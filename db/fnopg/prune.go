@@ -0,0 +1,68 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"time"
+)
+
+// pruneIntervalBlocks is the number of new main chain blocks between archival
+// pruning runs.
+const pruneIntervalBlocks = 144
+
+// EnableHistoryPruning enables archival pruning of the vins, vouts, and
+// addresses tables. Full detail is kept for the most recent keepBlocks blocks,
+// while the history of outpoints spent before then is aggregated into
+// per-address summaries. A keepBlocks value of 0 disables pruning.
+func (pgb *ChainDB) EnableHistoryPruning(keepBlocks int64) {
+	if pgb == nil {
+		return
+	}
+	pgb.pruneKeepBlocks = keepBlocks
+}
+
+// PruneHistory performs archival pruning of history older than the configured
+// number of blocks from the best block. See EnableHistoryPruning. The number of
+// address summaries updated and vins deleted are returned. If pruning is
+// disabled or a prune is already in progress, PruneHistory returns immediately.
+func (pgb *ChainDB) PruneHistory() (numSummaries, numVins int64, err error) {
+	if pgb.pruneKeepBlocks <= 0 {
+		return
+	}
+
+	if !pgb.pruneLock.TryLock() {
+		log.Debugf("History pruning already in progress.")
+		return
+	}
+	defer pgb.pruneLock.Unlock()
+
+	cutoffHeight := pgb.Height() - pgb.pruneKeepBlocks
+	if cutoffHeight <= 0 {
+		return
+	}
+
+	cutoffTime, err := pgb.BlockTimeByHeight(cutoffHeight)
+	if err != nil {
+		return
+	}
+
+	log.Infof("Pruning address history prior to block %d...", cutoffHeight)
+	start := time.Now()
+	var numInvalid int64
+	numSummaries, numInvalid, numVins, err = pruneHistory(pgb.db, time.Unix(cutoffTime, 0))
+	if err != nil {
+		return
+	}
+
+	// Cached address rows may refer to pruned history.
+	pgb.AddressCache.ClearAll()
+
+	log.Infof("Pruned history for %d addresses and %d vins in %v.",
+		numSummaries, numVins, time.Since(start))
+	if numInvalid > 0 {
+		log.Infof("Deleted %d addresses rows of side chain or disapproved "+
+			"transactions without summarizing them.", numInvalid)
+	}
+	return
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/fonero-project/fnodata/db/cache"
 	"github.com/fonero-project/fnodata/db/dbtypes"
 	"github.com/fonero-project/fnodata/db/fnopg/internal"
+	exptypes "github.com/fonero-project/fnodata/explorer/types"
 	"github.com/fonero-project/fnodata/txhelpers"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/lib/pq"
@@ -714,6 +715,117 @@ func RetrieveMissForTicket(ctx context.Context, db *sql.DB, ticketHash string) (
 	return
 }
 
+// retrieveMissRates retrieves the numbers of votes and misses in the main
+// chain blocks from height startHeight on, grouped by the named time grouping
+// or by ticket price windows of windowSize blocks for "window".
+func retrieveMissRates(ctx context.Context, db *sql.DB, group string, startHeight,
+	windowSize int64) ([]*dbtypes.MissRate, error) {
+	args := []interface{}{startHeight}
+	if group == "window" {
+		args = append(args, windowSize)
+	}
+	rows, err := db.QueryContext(ctx, internal.MakeSelectMissRates(group), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var rates []*dbtypes.MissRate
+	for rows.Next() {
+		var mr dbtypes.MissRate
+		err = rows.Scan(&mr.StartHeight, &mr.EndHeight, &mr.StartTime, &mr.Votes,
+			&mr.Misses)
+		if err != nil {
+			return nil, err
+		}
+		if called := mr.Votes + mr.Misses; called > 0 {
+			mr.MissRate = float64(mr.Misses) / float64(called)
+		}
+		rates = append(rates, &mr)
+	}
+	return rates, rows.Err()
+}
+
+// retrieveLiveTicketCountsByHeight retrieves the number of live and immature
+// main chain tickets purchased in each block above height minHeight.
+func retrieveLiveTicketCountsByHeight(ctx context.Context, db *sql.DB, minHeight int64) ([]ticketHeightCount, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectLiveTicketCountsByHeight, minHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var counts []ticketHeightCount
+	for rows.Next() {
+		var c ticketHeightCount
+		if err = rows.Scan(&c.height, &c.count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// retrieveUnrevokedTickets retrieves up to limit of the main chain tickets
+// that missed or expired and are not yet revoked, skipping offset, and the
+// total number of such tickets. The tickets may be filtered by voting address
+// and by reward address, unless they are empty.
+func retrieveUnrevokedTickets(ctx context.Context, db *sql.DB, votingAddress,
+	rewardAddress string, limit, offset int) ([]*dbtypes.UnrevokedTicket, int64, error) {
+	var total int64
+	err := db.QueryRowContext(ctx, internal.SelectUnrevokedTicketsCount,
+		votingAddress, rewardAddress).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.QueryContext(ctx, internal.SelectUnrevokedTickets,
+		votingAddress, rewardAddress, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer closeRows(rows)
+
+	var tickets []*dbtypes.UnrevokedTicket
+	for rows.Next() {
+		var t dbtypes.UnrevokedTicket
+		var poolStatus dbtypes.TicketPoolStatus
+		err = rows.Scan(&t.TicketHash, &t.BlockHeight, &t.Price, &poolStatus,
+			&t.VotingAddress, &t.MissHeight)
+		if err != nil {
+			return nil, 0, err
+		}
+		t.PoolStatus = poolStatus.String()
+		tickets = append(tickets, &t)
+	}
+	return tickets, total, rows.Err()
+}
+
+// retrieveMissedVotesChart fetches the number of missed votes in each block
+// above the height of the charts' missed votes data.
+func retrieveMissedVotesChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectMissedVotesChart, charts.MissedVotesTip())
+}
+
+// Append the results from retrieveMissedVotesChart to the provided ChartData.
+// This is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendMissedVotesChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, count uint64
+		if err := rows.Scan(&height, &count); err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.MissedVotes)) {
+			return fmt.Errorf("appendMissedVotesChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.MissedVotes))
+		}
+		blocks.MissedVotes = append(blocks.MissedVotes, count)
+	}
+	return rows.Err()
+}
+
 // retrieveAllAgendas returns all the current agendas in the db.
 func retrieveAllAgendas(db *sql.DB) (map[string]dbtypes.MileStone, error) {
 	rows, err := db.Query(internal.SelectAllAgendas)
@@ -1405,6 +1517,25 @@ func RetrieveAddressBalance(ctx context.Context, db *sql.DB, address string) (ba
 		}
 	}
 
+	// Include the totals of any history removed by archival pruning.
+	var summary *dbtypes.AddressSummary
+	summary, err = retrieveAddressSummary(ctx, db, address)
+	if err != nil {
+		closeRows(rows)
+		if errRoll := dbtx.Rollback(); errRoll != nil {
+			log.Errorf("Rollback failed: %v", errRoll)
+		}
+		err = fmt.Errorf("failed to query address summary: %v", err)
+		return
+	}
+	if summary != nil {
+		balance.Pruned = summary
+		balance.NumSpent += summary.NumSpent
+		balance.TotalSpent += summary.TotalSpent
+		fromStake += summary.FromStake
+		toStake += summary.ToStake
+	}
+
 	totalTransfer := balance.TotalSpent + balance.TotalUnspent
 	if totalTransfer > 0 {
 		balance.FromStake = float64(fromStake) / float64(totalTransfer)
@@ -1501,6 +1632,65 @@ func RetrieveAddressUTXOs(ctx context.Context, db *sql.DB, address string, curre
 	return outputs, nil
 }
 
+// retrieveUsedAddresses retrieves the set of the given addresses that have
+// mainchain history, including pruned history.
+func retrieveUsedAddresses(ctx context.Context, db *sql.DB, addresses []string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectUsedAddresses, pq.Array(addresses))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	used := make(map[string]bool)
+	for rows.Next() {
+		var addr string
+		if err = rows.Scan(&addr); err != nil {
+			return nil, err
+		}
+		used[addr] = true
+	}
+	return used, rows.Err()
+}
+
+// retrieveAddressesMergedTxns retrieves a page of the valid mainchain
+// transactions involving any of the given addresses, most recent first. For
+// each transaction, the amounts sent from and received by all of the addresses
+// are totaled.
+func retrieveAddressesMergedTxns(ctx context.Context, db *sql.DB, addresses []string,
+	N, offset int64) ([]*apitypes.XpubTx, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAddressesMergedTxns,
+		pq.Array(addresses), N, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var txns []*apitypes.XpubTx
+	for rows.Next() {
+		var blockTime time.Time
+		var sent, received int64
+		tx := new(apitypes.XpubTx)
+		err = rows.Scan(&tx.TxID, &blockTime, &sent, &received,
+			pq.Array(&tx.Addresses))
+		if err != nil {
+			return nil, err
+		}
+		tx.Time = dbtypes.NewTimeDef(blockTime)
+		tx.Sent = fnoutil.Amount(sent).ToCoin()
+		tx.Received = fnoutil.Amount(received).ToCoin()
+		txns = append(txns, tx)
+	}
+	return txns, rows.Err()
+}
+
+// retrieveAddressesMergedTxnsCount counts the valid mainchain transactions
+// involving any of the given addresses.
+func retrieveAddressesMergedTxnsCount(ctx context.Context, db *sql.DB, addresses []string) (count int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectAddressesMergedTxnsCount,
+		pq.Array(addresses)).Scan(&count)
+	return
+}
+
 // RetrieveAddressTxnsOrdered will get all transactions for addresses provided
 // and return them sorted by time in descending order. It will also return a
 // short list of recently (defined as greater than recentBlockHeight) confirmed
@@ -1871,6 +2061,79 @@ func retrieveTxHistoryByAmountFlow(ctx context.Context, db *sql.DB, addr, timeIn
 	return items, nil
 }
 
+// retrieveAddressSummary retrieves the aggregated totals of the pruned history
+// of an address. A nil *AddressSummary is returned if no history of the address
+// has been pruned.
+func retrieveAddressSummary(ctx context.Context, db *sql.DB, address string) (*dbtypes.AddressSummary, error) {
+	var s dbtypes.AddressSummary
+	err := db.QueryRowContext(ctx, internal.SelectAddressSummary, address).Scan(
+		&s.NumSpent, &s.TotalSpent, &s.FromStake, &s.ToStake,
+		&s.FirstSeen, &s.LastSeen, &s.PrunedBefore)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// retrieveAddressReceivedSentAtTime retrieves the total amounts, in atoms,
+// received and sent by the address in mainchain blocks with times no later
+// than the given time.
+func retrieveAddressReceivedSentAtTime(ctx context.Context, db *sql.DB, address string,
+	t time.Time) (received, sent int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectAddressReceivedSentAtTime,
+		address, t).Scan(&received, &sent)
+	return
+}
+
+// retrieveAddressReceivedSentAtHeight retrieves the total amounts, in atoms,
+// received and sent by the address in mainchain blocks with heights no greater
+// than the given height.
+func retrieveAddressReceivedSentAtHeight(ctx context.Context, db *sql.DB, address string,
+	height int64) (received, sent int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectAddressReceivedSentAtHeight,
+		address, height).Scan(&received, &sent)
+	return
+}
+
+// pruneHistory removes the address history of outpoints spent before the given
+// time from the addresses and vouts tables, aggregating it into per-address
+// summaries in the address_summary table, and deletes the vins from before the
+// given time. The vin_db_ids and vout_db_ids of the transactions are left
+// intact, so readers must allow for the rows of pruned ids being missing. The
+// number of address summaries created or updated, the number of deleted
+// addresses rows that were not valid_mainchain, and the number of vins deleted
+// are returned.
+func pruneHistory(db *sql.DB, before time.Time) (numSummaries, numInvalid, numVins int64, err error) {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+
+	err = dbtx.QueryRow(internal.PruneAddressHistory, before).Scan(&numSummaries, &numInvalid)
+	if err != nil {
+		_ = dbtx.Rollback()
+		return 0, 0, 0, fmt.Errorf("failed to prune address history: %v", err)
+	}
+
+	_, err = dbtx.Exec(internal.PruneSpentVouts, before)
+	if err != nil {
+		_ = dbtx.Rollback()
+		return 0, 0, 0, fmt.Errorf("failed to prune spent vouts: %v", err)
+	}
+
+	res, err := dbtx.Exec(internal.PruneVins, before)
+	if err != nil {
+		_ = dbtx.Rollback()
+		return 0, 0, 0, fmt.Errorf("failed to prune vins: %v", err)
+	}
+	numVins, _ = res.RowsAffected()
+
+	return numSummaries, numInvalid, numVins, dbtx.Commit()
+}
+
 // --- vins and vouts tables ---
 
 // InsertVin either inserts, attempts to insert, or upserts the given vin data
@@ -2291,7 +2554,8 @@ func RetrieveVinByID(ctx context.Context, db *sql.DB, vinDbID uint64) (prevOutHa
 
 // RetrieveVinsByIDs retrieves vin details for the rows of the vins table
 // specified by the provided row IDs. This function is an important part of the
-// transaction page.
+// transaction page. The vins of rows removed by archival pruning have only
+// their TxIndex set.
 func RetrieveVinsByIDs(ctx context.Context, db *sql.DB, vinDbIDs []uint64) ([]dbtypes.VinTxProperty, error) {
 	vins := make([]dbtypes.VinTxProperty, len(vinDbIDs))
 	for i, id := range vinDbIDs {
@@ -2300,6 +2564,11 @@ func RetrieveVinsByIDs(ctx context.Context, db *sql.DB, vinDbIDs []uint64) ([]db
 			&vin.TxIndex, &vin.TxTree, &vin.IsValid, &vin.IsMainchain,
 			&vin.Time, &vin.PrevTxHash, &vin.PrevTxIndex, &vin.PrevTxTree,
 			&vin.ValueIn, &vin.TxType)
+		if err == sql.ErrNoRows {
+			// The vin was removed by archival pruning.
+			vin.TxIndex = uint32(i)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -2309,7 +2578,8 @@ func RetrieveVinsByIDs(ctx context.Context, db *sql.DB, vinDbIDs []uint64) ([]db
 
 // RetrieveVoutsByIDs retrieves vout details for the rows of the vouts table
 // specified by the provided row IDs. This function is an important part of the
-// transaction page.
+// transaction page. The vouts of rows removed by archival pruning have only
+// their TxIndex and Pruned set.
 func RetrieveVoutsByIDs(ctx context.Context, db *sql.DB, voutDbIDs []uint64) ([]dbtypes.Vout, error) {
 	vouts := make([]dbtypes.Vout, len(voutDbIDs))
 	for i, id := range voutDbIDs {
@@ -2320,6 +2590,12 @@ func RetrieveVoutsByIDs(ctx context.Context, db *sql.DB, voutDbIDs []uint64) ([]
 		err := db.QueryRowContext(ctx, internal.SelectVoutByID, id).Scan(&id0, &vout.TxHash,
 			&vout.TxIndex, &vout.TxTree, &vout.Value, &vout.Version,
 			&vout.ScriptPubKey, &reqSigs, &scriptType, &addresses)
+		if err == sql.ErrNoRows {
+			// The spent vout was removed by archival pruning.
+			vout.TxIndex = uint32(i)
+			vout.Pruned = true
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -2520,7 +2796,7 @@ func InsertSpendingAddressRow(db *sql.DB, fundingTxHash string,
 		return 0, fmt.Errorf(`unable to begin database transaction: %v`, err)
 	}
 
-	c, err := insertSpendingAddressRow(dbtx, fundingTxHash, fundingTxVoutIndex,
+	c, _, err := insertSpendingAddressRow(dbtx, fundingTxHash, fundingTxVoutIndex,
 		fundingTxTree, spendingTxHash, spendingTxVinIndex, vinDbID, utxoData, checked,
 		updateExisting, isValidMainchain, txType, updateFundingRow, spendingTXBlockTime)
 	if err != nil {
@@ -2533,11 +2809,12 @@ func InsertSpendingAddressRow(db *sql.DB, fundingTxHash string,
 
 // insertSpendingAddressRow inserts a new row in the addresses table for a new
 // transaction input, and updates the spending information for the addresses
-// table row corresponding to the previous outpoint.
+// table row corresponding to the previous outpoint. The addresses paid by the
+// previous outpoint are also returned.
 func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutIndex uint32,
 	fundingTxTree int8, spendingTxHash string, spendingTxVinIndex uint32, vinDbID uint64,
 	utxoData *dbtypes.UTXOData, checked, updateExisting, validMainchain bool, txType int16,
-	updateFundingRow bool, blockT ...dbtypes.TimeDef) (int64, error) {
+	updateFundingRow bool, blockT ...dbtypes.TimeDef) (int64, []string, error) {
 
 	// Select addresses and value from the matching funding tx output. A maximum
 	// of one row and a minimum of none are expected.
@@ -2556,7 +2833,7 @@ func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutInd
 		case sql.ErrNoRows, nil:
 			// If no row found or error is nil, continue
 		default:
-			return 0, fmt.Errorf("SelectAddressByTxHash: %v", err)
+			return 0, nil, fmt.Errorf("SelectAddressByTxHash: %v", err)
 		}
 
 		// Get address list.
@@ -2576,7 +2853,7 @@ func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutInd
 		// Fetch the block time from the tx table.
 		err := tx.QueryRow(internal.SelectTxBlockTimeByHash, spendingTxHash).Scan(&blockTime)
 		if err != nil {
-			return 0, fmt.Errorf("SelectTxBlockTimeByHash: %v", err)
+			return 0, nil, fmt.Errorf("SelectTxBlockTimeByHash: %v", err)
 		}
 	}
 
@@ -2589,16 +2866,17 @@ func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutInd
 			spendingTxVinIndex, vinDbID, value, blockTime, isFunding,
 			validMainchain, txType).Scan(&rowID)
 		if err != nil {
-			return 0, fmt.Errorf("InsertAddressRow: %v", err)
+			return 0, nil, fmt.Errorf("InsertAddressRow: %v", err)
 		}
 	}
 
 	if updateFundingRow {
 		// Update the matching funding addresses row with the spending info.
-		return setSpendingForFundingOP(tx, fundingTxHash, fundingTxVoutIndex,
+		numSet, err := setSpendingForFundingOP(tx, fundingTxHash, fundingTxVoutIndex,
 			spendingTxHash, spendingTxVinIndex)
+		return numSet, addrs, err
 	}
-	return 0, nil
+	return 0, addrs, nil
 }
 
 // --- agendas table ---
@@ -2679,6 +2957,34 @@ func retrieveTotalAgendaVotesCount(ctx context.Context, db *sql.DB, agendaID str
 	return
 }
 
+// retrieveAgendaVotesByAddress returns the vote choice counts for the provided
+// agenda id grouped by the voting address of the tickets that cast the votes.
+// At most limit addresses, those with the most votes, are returned.
+func retrieveAgendaVotesByAddress(ctx context.Context, db *sql.DB, agendaID string,
+	votingStartHeight, votingDoneHeight int64, limit int) ([]*dbtypes.AgendaAddressVotes, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAgendaVotesByAddress,
+		dbtypes.Yes, dbtypes.Abstain, dbtypes.No, agendaID, votingStartHeight,
+		votingDoneHeight, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var addrVotes []*dbtypes.AgendaAddressVotes
+	for rows.Next() {
+		var address sql.NullString
+		v := new(dbtypes.AgendaAddressVotes)
+		err = rows.Scan(&address, &v.Yes, &v.Abstain, &v.No, &v.Total)
+		if err != nil {
+			return nil, err
+		}
+		v.Address = address.String
+		addrVotes = append(addrVotes, v)
+	}
+
+	return addrVotes, rows.Err()
+}
+
 // --- transactions table ---
 
 func InsertTx(db *sql.DB, dbTx *dbtypes.Tx, checked, updateExistingRecords bool) (uint64, error) {
@@ -3303,10 +3609,1530 @@ func retrieveProposalVotesData(ctx context.Context, db *sql.DB,
 	return data, err
 }
 
+// retrieveProposalVotesTimeline returns the cumulative vote tallies of the
+// provided proposal token at each block height where the tallies changed.
+func retrieveProposalVotesTimeline(ctx context.Context, db *sql.DB,
+	proposalToken string) (*dbtypes.ProposalVotesTimeline, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectProposalVotesTimeline, proposalToken)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeRows(rows)
+
+	data := &dbtypes.ProposalVotesTimeline{
+		Height: make([]uint64, 0),
+		Yes:    make([]uint64, 0),
+		No:     make([]uint64, 0),
+	}
+	var totalYes, totalNo uint64
+	for rows.Next() {
+		var height, yes, no uint64
+		if err = rows.Scan(&height, &no, &yes); err != nil {
+			return nil, err
+		}
+
+		totalYes += yes
+		totalNo += no
+		data.Height = append(data.Height, height)
+		data.Yes = append(data.Yes, totalYes)
+		data.No = append(data.No, totalNo)
+	}
+
+	return data, rows.Err()
+}
+
+// --- mempool_txs and mempool_fee_snapshots tables ---
+
+// mempoolTxPrevOuts gets the previous outpoints spent by a mempool
+// transaction as separate slices of hashes and indexes.
+func mempoolTxPrevOuts(tx *exptypes.MempoolTx) ([]string, []int64) {
+	prevHashes := make([]string, 0, len(tx.Vin))
+	prevIndexes := make([]int64, 0, len(tx.Vin))
+	for _, in := range tx.Vin {
+		prevHashes = append(prevHashes, in.TxId)
+		prevIndexes = append(prevIndexes, int64(in.Outdex))
+	}
+	return prevHashes, prevIndexes
+}
+
+// storeMempoolTxs records the transactions in the current mempool, and marks
+// the previously recorded transactions that are no longer in mempool as
+// removed at the given time and block height. The number of transactions
+// marked as removed is returned.
+func storeMempoolTxs(db *sql.DB, txs []exptypes.MempoolTx, removedTime time.Time,
+	height int64) (int64, error) {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+
+	stmt, err := dbtx.Prepare(internal.UpsertMempoolTx)
+	if err != nil {
+		_ = dbtx.Rollback() // try, but we want the Prepare error back
+		return 0, err
+	}
+
+	hashes := make([]string, 0, len(txs))
+	for i := range txs {
+		tx := &txs[i]
+		prevHashes, prevIndexes := mempoolTxPrevOuts(tx)
+		_, err = stmt.Exec(tx.Hash, tx.Type, tx.Size, tx.Fees,
+			mempoolTxFeeRate(tx), time.Unix(tx.Time, 0),
+			pq.Array(prevHashes), pq.Array(prevIndexes), dbtypes.MempoolTxPending)
+		if err != nil {
+			_ = stmt.Close()
+			_ = dbtx.Rollback()
+			return 0, fmt.Errorf("failed to insert mempool transaction: %v", err)
+		}
+		hashes = append(hashes, tx.Hash)
+	}
+	_ = stmt.Close()
+
+	res, err := dbtx.Exec(internal.SetMempoolTxsRemoved, pq.Array(hashes),
+		removedTime, height, dbtypes.MempoolTxMined, dbtypes.MempoolTxDoubleSpent,
+		dbtypes.MempoolTxExpired, dbtypes.MempoolTxPending)
+	if err != nil {
+		_ = dbtx.Rollback()
+		return 0, fmt.Errorf("failed to mark removed mempool transactions: %v", err)
+	}
+	numRemoved, _ := res.RowsAffected()
+
+	return numRemoved, dbtx.Commit()
+}
+
+// insertMempoolTx records a transaction as it enters mempool. A transaction
+// that is already recorded is left unchanged.
+func insertMempoolTx(ctx context.Context, db *sql.DB, tx *exptypes.MempoolTx) error {
+	prevHashes, prevIndexes := mempoolTxPrevOuts(tx)
+	_, err := db.ExecContext(ctx, internal.InsertMempoolTx, tx.Hash, tx.Type,
+		tx.Size, tx.Fees, mempoolTxFeeRate(tx), time.Unix(tx.Time, 0),
+		pq.Array(prevHashes), pq.Array(prevIndexes), dbtypes.MempoolTxPending)
+	return err
+}
+
+// deleteMempoolHistoryBefore deletes the transactions removed from mempool and
+// the fee rate snapshots taken before the given time. The numbers of
+// transactions and snapshots deleted are returned.
+func deleteMempoolHistoryBefore(db *sql.DB, t time.Time) (numTxs, numSnaps int64, err error) {
+	res, err := db.Exec(internal.DeleteMempoolTxsRemovedBefore, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	numTxs, _ = res.RowsAffected()
+
+	res, err = db.Exec(internal.DeleteMempoolFeeSnapshotsBefore, t)
+	if err != nil {
+		return numTxs, 0, err
+	}
+	numSnaps, _ = res.RowsAffected()
+	return numTxs, numSnaps, nil
+}
+
+// mempoolTxFeeRate computes the fee rate of a mempool transaction in FNO/kB.
+func mempoolTxFeeRate(tx *exptypes.MempoolTx) float64 {
+	if tx.Size == 0 {
+		return 0
+	}
+	return tx.Fees * 1000 / float64(tx.Size)
+}
+
+// mempoolTxFee is the type, size, and fee rate in FNO/kB of a transaction in
+// mempool.
+type mempoolTxFee struct {
+	txType  string
+	size    int32
+	feeRate float64
+}
+
+// makeMempoolFeeSnapshot builds a fee rate histogram of the non-vote
+// transactions in txs.
+func makeMempoolFeeSnapshot(txs []mempoolTxFee, t time.Time, height int64) *dbtypes.MempoolFeeSnapshot {
+	numBuckets := len(dbtypes.MempoolFeeRateBuckets) + 1
+	snap := &dbtypes.MempoolFeeSnapshot{
+		Time:    dbtypes.NewTimeDef(t),
+		Height:  height,
+		Buckets: dbtypes.MempoolFeeRateBuckets,
+		Counts:  make([]uint64, numBuckets),
+		Sizes:   make([]uint64, numBuckets),
+	}
+
+	for i := range txs {
+		tx := &txs[i]
+		// Votes do not pay fees.
+		if tx.txType == "Vote" {
+			continue
+		}
+		b := dbtypes.MempoolFeeRateBucket(tx.feeRate)
+		snap.Counts[b]++
+		snap.Sizes[b] += uint64(tx.size)
+		snap.NumTxs++
+		snap.TotalSize += int64(tx.size)
+	}
+
+	return snap
+}
+
+// retrievePendingMempoolTxFees retrieves the type, size, and fee rate of the
+// transactions recorded as pending in mempool.
+func retrievePendingMempoolTxFees(ctx context.Context, db *sql.DB) ([]mempoolTxFee, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMempoolTxFeeRatesByReason,
+		dbtypes.MempoolTxPending)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var txs []mempoolTxFee
+	for rows.Next() {
+		var txType sql.NullString
+		var tx mempoolTxFee
+		if err = rows.Scan(&txType, &tx.size, &tx.feeRate); err != nil {
+			return nil, err
+		}
+		tx.txType = txType.String
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+func insertMempoolFeeSnapshot(db *sql.DB, snap *dbtypes.MempoolFeeSnapshot) error {
+	_, err := db.Exec(internal.InsertMempoolFeeSnapshot, snap.Time.T,
+		snap.Height, snap.NumTxs, snap.TotalSize,
+		dbtypes.UInt64Array(snap.Counts), dbtypes.UInt64Array(snap.Sizes))
+	return err
+}
+
+// retrieveMempoolTxHistory retrieves the mempool history of the transaction
+// with the given hash. sql.ErrNoRows is returned if the transaction was never
+// recorded in mempool.
+func retrieveMempoolTxHistory(ctx context.Context, db *sql.DB, txHash string) (*dbtypes.MempoolTxHistory, error) {
+	var txType sql.NullString
+	var removedTime pq.NullTime
+	var removedHeight sql.NullInt64
+	var reason dbtypes.MempoolRemovalReason
+	h := &dbtypes.MempoolTxHistory{TxHash: txHash}
+	err := db.QueryRowContext(ctx, internal.SelectMempoolTxHistory, txHash).Scan(
+		&txType, &h.Size, &h.Fees, &h.FeeRate, &h.FirstSeen, &removedTime,
+		&removedHeight, &reason)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Type = txType.String
+	h.Status = reason.String()
+	if removedTime.Valid {
+		removed := dbtypes.NewTimeDef(removedTime.Time)
+		h.RemovedTime = &removed
+		h.RemovedHeight = removedHeight.Int64
+		h.WaitSeconds = removed.UNIX() - h.FirstSeen.UNIX()
+	}
+	return h, nil
+}
+
+func scanMempoolFeeSnapshot(row interface{ Scan(...interface{}) error }) (*dbtypes.MempoolFeeSnapshot, error) {
+	snap := &dbtypes.MempoolFeeSnapshot{Buckets: dbtypes.MempoolFeeRateBuckets}
+	var counts, sizes dbtypes.UInt64Array
+	err := row.Scan(&snap.Time, &snap.Height, &snap.NumTxs, &snap.TotalSize,
+		&counts, &sizes)
+	if err != nil {
+		return nil, err
+	}
+	snap.Counts, snap.Sizes = counts, sizes
+	return snap, nil
+}
+
+// retrieveMempoolFeeSnapshots retrieves up to limit mempool fee rate snapshots
+// taken in the time range [from, to].
+func retrieveMempoolFeeSnapshots(ctx context.Context, db *sql.DB, from, to time.Time,
+	limit int) ([]*dbtypes.MempoolFeeSnapshot, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMempoolFeeSnapshots, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var snaps []*dbtypes.MempoolFeeSnapshot
+	for rows.Next() {
+		snap, err := scanMempoolFeeSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, rows.Err()
+}
+
+// retrieveMempoolFeeSnapshotAt retrieves the most recent mempool fee rate
+// snapshot taken at or before the given time.
+func retrieveMempoolFeeSnapshotAt(ctx context.Context, db *sql.DB, t time.Time) (*dbtypes.MempoolFeeSnapshot, error) {
+	row := db.QueryRowContext(ctx, internal.SelectMempoolFeeSnapshotAt, t)
+	return scanMempoolFeeSnapshot(row)
+}
+
+// --- block_filters table ---
+
+// storeBlockFilter inserts or updates the serialized filter and the filter
+// header of the block with the given hash.
+func storeBlockFilter(db *sql.DB, blockHash string, height uint32, filter []byte,
+	header sql.NullString) error {
+	_, err := db.Exec(internal.UpsertBlockFilter, blockHash, height, filter, header)
+	return err
+}
+
+// populateBlockFilterHeaders computes the filter header chain of the main
+// chain blocks, fetching the blocks without a stored filter header from the
+// node to build their filters.
+func populateBlockFilterHeaders(db *sql.DB, bg BlockGetter) error {
+	rows, err := db.Query(internal.SelectMainchainBlockFilterHeaders)
+	if err != nil {
+		return err
+	}
+	defer closeRows(rows)
+
+	var count int64
+	prevHeader := new(chainhash.Hash)
+	for rows.Next() {
+		var blockHash string
+		var header sql.NullString
+		if err = rows.Scan(&blockHash, &header); err != nil {
+			return err
+		}
+		if header.Valid {
+			if prevHeader, err = chainhash.NewHashFromStr(header.String); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hash, err := chainhash.NewHashFromStr(blockHash)
+		if err != nil {
+			return err
+		}
+		msgBlock, err := bg.GetBlock(hash)
+		if err != nil {
+			return fmt.Errorf("GetBlock(%v): %v", hash, err)
+		}
+		prevHeader, err = storeBlockFilterWithHeader(db, msgBlock, prevHeader)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Computed the filter headers of %d blocks.", count)
+	return nil
+}
+
+// retrieveBlockFilterHeader retrieves the filter header of the block with the
+// given hash. An empty string is returned if the filter header is unknown, and
+// sql.ErrNoRows if the block's filter is not stored.
+func retrieveBlockFilterHeader(ctx context.Context, db *sql.DB, blockHash string) (string, error) {
+	var header sql.NullString
+	err := db.QueryRowContext(ctx, internal.SelectBlockFilterHeader, blockHash).Scan(&header)
+	return header.String, err
+}
+
+// retrieveBlockFilter retrieves the filter and filter header of the block with
+// the given hash.
+func retrieveBlockFilter(ctx context.Context, db *sql.DB, blockHash string) (*apitypes.BlockFilter, error) {
+	var filter []byte
+	var header sql.NullString
+	bf := apitypes.BlockFilter{BlockHash: blockHash}
+	err := db.QueryRowContext(ctx, internal.SelectBlockFilter, blockHash).Scan(
+		&bf.Height, &filter, &header)
+	if err != nil {
+		return nil, err
+	}
+	bf.Filter = hex.EncodeToString(filter)
+	bf.Header = header.String
+	return &bf, nil
+}
+
+// retrieveMainchainFilterHeaders retrieves the filter headers of the main chain
+// blocks in the height range [start, end].
+func retrieveMainchainFilterHeaders(ctx context.Context, db *sql.DB, start, end int64) ([]*apitypes.FilterHeader, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMainchainFilterHeaders, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var headers []*apitypes.FilterHeader
+	for rows.Next() {
+		var fh apitypes.FilterHeader
+		if err = rows.Scan(&fh.Height, &fh.BlockHash, &fh.Header); err != nil {
+			return nil, err
+		}
+		headers = append(headers, &fh)
+	}
+	return headers, rows.Err()
+}
+
+// --- nulldata table ---
+
+// insertNullData inserts the payloads of the nulldata outputs among the given
+// vouts in the database transaction. The insert statement is prepared once,
+// and only if there are nulldata outputs.
+func insertNullData(dbTx *sql.Tx, vouts [][]*dbtypes.Vout) error {
+	var stmt *sql.Stmt
+	for _, txVouts := range vouts {
+		for _, vout := range txVouts {
+			if vout.ScriptPubKeyData.Type != "nulldata" {
+				continue
+			}
+			payload, ok := txhelpers.NullDataPayload(vout.ScriptPubKey)
+			if !ok {
+				continue
+			}
+			if stmt == nil {
+				var err error
+				if stmt, err = dbTx.Prepare(internal.InsertNullData); err != nil {
+					return err
+				}
+				defer stmt.Close()
+			}
+			_, err := stmt.Exec(vout.TxHash, vout.TxIndex, vout.TxTree, payload)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// populateNullData indexes the payloads of all of the nulldata outputs in the
+// vouts table.
+func populateNullData(db *sql.DB) error {
+	rows, err := db.Query(internal.SelectNullDataVouts)
+	if err != nil {
+		return err
+	}
+	defer closeRows(rows)
+
+	stmt, err := db.Prepare(internal.InsertNullData)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var count int64
+	for rows.Next() {
+		var txHash string
+		var txIndex uint32
+		var txTree int8
+		var script []byte
+		if err = rows.Scan(&txHash, &txIndex, &txTree, &script); err != nil {
+			return err
+		}
+		payload, ok := txhelpers.NullDataPayload(script)
+		if !ok {
+			continue
+		}
+		if _, err = stmt.Exec(txHash, txIndex, txTree, payload); err != nil {
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Indexed %d nulldata outputs.", count)
+	return nil
+}
+
+// retrieveNullData retrieves up to limit main chain nulldata outputs, skipping
+// offset, with payloads that begin with (if prefix is true) or contain the
+// given data. The most recent outputs are first.
+func retrieveNullData(ctx context.Context, db *sql.DB, data []byte, prefix bool,
+	limit, offset int) ([]*dbtypes.NullData, error) {
+	query := internal.SelectNullDataContaining
+	if prefix {
+		query = internal.SelectNullDataByPrefix
+	}
+	rows, err := db.QueryContext(ctx, query, hex.EncodeToString(data), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var outputs []*dbtypes.NullData
+	for rows.Next() {
+		var nd dbtypes.NullData
+		var payload []byte
+		err = rows.Scan(&nd.TxHash, &nd.TxIndex, &nd.BlockHeight, &nd.BlockTime,
+			&payload)
+		if err != nil {
+			return nil, err
+		}
+		nd.Payload = hex.EncodeToString(payload)
+		nd.Text = txhelpers.NullDataText(payload)
+		outputs = append(outputs, &nd)
+	}
+	return outputs, rows.Err()
+}
+
+// retrieveNullDataChart fetches the number of nulldata outputs in each block
+// above the height of the charts' nulldata data.
+func retrieveNullDataChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectNullDataChart, charts.NullDataTip())
+}
+
+// Append the results from retrieveNullDataChart to the provided ChartData.
+// This is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendNullDataChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, count uint64
+		if err := rows.Scan(&height, &count); err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.NullData)) {
+			return fmt.Errorf("appendNullDataChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.NullData))
+		}
+		blocks.NullData = append(blocks.NullData, count)
+	}
+	return rows.Err()
+}
+
+// --- redeem_scripts table ---
+
+// insertRedeemScript decodes and stores the redeem script in the signature
+// script of a transaction input spending a P2SH output. false is returned if
+// the signature script does not end with a standard redeem script.
+func insertRedeemScript(stmt *sql.Stmt, sigScript []byte, txHash string,
+	txIndex uint32, params *chaincfg.Params) (bool, error) {
+	rs, err := txhelpers.SigScriptRedeemScript(sigScript, params)
+	if err != nil {
+		return false, nil
+	}
+	_, err = stmt.Exec(rs.Address, rs.Script, rs.Class, rs.RequiredSigs,
+		pq.Array(rs.PubKeys), pq.Array(rs.Addresses), txHash, txIndex)
+	return err == nil, err
+}
+
+// insertRedeemScripts stores the redeem scripts revealed by the given
+// transaction inputs spending P2SH outputs. Inputs with signature scripts that
+// do not end with a standard redeem script are skipped.
+func insertRedeemScripts(db *sql.DB, vins []*dbtypes.VinTxProperty, params *chaincfg.Params) error {
+	if len(vins) == 0 {
+		return nil
+	}
+
+	dbtx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+
+	stmt, err := dbtx.Prepare(internal.InsertRedeemScript)
+	if err != nil {
+		_ = dbtx.Rollback()
+		return err
+	}
+
+	for _, vin := range vins {
+		_, err = insertRedeemScript(stmt, vin.ScriptHex, vin.TxID, vin.TxIndex, params)
+		if err != nil {
+			_ = stmt.Close()
+			return fmt.Errorf("%v (rollback: %v)", err, dbtx.Rollback())
+		}
+	}
+
+	_ = stmt.Close()
+	return dbtx.Commit()
+}
+
+// p2shSpend is a transaction input spending a P2SH output.
+type p2shSpend struct {
+	txHash  string
+	txIndex uint32
+}
+
+// populateRedeemScripts stores the redeem scripts revealed by the transaction
+// inputs in the vins table that spend P2SH outputs. The vins table does not
+// hold signature scripts, so the spends are grouped by the main chain block of
+// the spending transaction, and each block is retrieved once with the
+// BlockGetter.
+func populateRedeemScripts(db *sql.DB, bg BlockGetter, params *chaincfg.Params) error {
+	rows, err := db.Query(internal.SelectP2SHSpends)
+	if err != nil {
+		return err
+	}
+
+	var blockHashes []string
+	spends := make(map[string][]p2shSpend)
+	for rows.Next() {
+		var blockHash string
+		var spend p2shSpend
+		if err = rows.Scan(&blockHash, &spend.txHash, &spend.txIndex); err != nil {
+			closeRows(rows)
+			return err
+		}
+		if _, found := spends[blockHash]; !found {
+			blockHashes = append(blockHashes, blockHash)
+		}
+		spends[blockHash] = append(spends[blockHash], spend)
+	}
+	if err = rows.Err(); err != nil {
+		closeRows(rows)
+		return err
+	}
+	closeRows(rows)
+
+	stmt, err := db.Prepare(internal.InsertRedeemScript)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var count int64
+	for _, blockHash := range blockHashes {
+		hash, err := chainhash.NewHashFromStr(blockHash)
+		if err != nil {
+			return err
+		}
+		msgBlock, err := bg.GetBlock(hash)
+		if err != nil {
+			return fmt.Errorf("GetBlock(%v): %v", hash, err)
+		}
+		txns := make(map[string]*wire.MsgTx)
+		for _, tx := range msgBlock.Transactions {
+			txns[tx.TxHash().String()] = tx
+		}
+		for _, tx := range msgBlock.STransactions {
+			txns[tx.TxHash().String()] = tx
+		}
+
+		for _, spend := range spends[blockHash] {
+			tx, found := txns[spend.txHash]
+			if !found || int(spend.txIndex) >= len(tx.TxIn) {
+				return fmt.Errorf("input %s:%d not found in block %s",
+					spend.txHash, spend.txIndex, blockHash)
+			}
+			decoded, err := insertRedeemScript(stmt, tx.TxIn[spend.txIndex].SignatureScript,
+				spend.txHash, spend.txIndex, params)
+			if err != nil {
+				return err
+			}
+			if decoded {
+				count++
+			}
+		}
+	}
+
+	log.Infof("Decoded %d P2SH redeem scripts from %d blocks.", count, len(blockHashes))
+	return nil
+}
+
+// scanRedeemScripts scans the redeem_scripts table rows.
+func scanRedeemScripts(rows *sql.Rows) ([]*dbtypes.RedeemScript, error) {
+	var scripts []*dbtypes.RedeemScript
+	for rows.Next() {
+		var rs dbtypes.RedeemScript
+		var script []byte
+		err := rows.Scan(&rs.Address, &script, &rs.Class, &rs.RequiredSigs,
+			pq.Array(&rs.PubKeys), pq.Array(&rs.Addresses), &rs.SpendingTxHash,
+			&rs.SpendingTxIndex)
+		if err != nil {
+			return nil, err
+		}
+		rs.Script = hex.EncodeToString(script)
+		scripts = append(scripts, &rs)
+	}
+	return scripts, rows.Err()
+}
+
+// retrieveRedeemScript retrieves the decoded redeem script of a P2SH address.
+// sql.ErrNoRows is returned if no redeem script is known for the address.
+func retrieveRedeemScript(ctx context.Context, db *sql.DB, address string) (*dbtypes.RedeemScript, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectRedeemScript, address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	scripts, err := scanRedeemScripts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(scripts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return scripts[0], nil
+}
+
+// retrieveRedeemScriptsByAddress retrieves the decoded redeem scripts that
+// include the given address, such as the multisig scripts with one of its
+// public keys.
+func retrieveRedeemScriptsByAddress(ctx context.Context, db *sql.DB, address string) ([]*dbtypes.RedeemScript, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectRedeemScriptsByAddress, address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	return scanRedeemScripts(rows)
+}
+
+// --- ticket_commitments table ---
+
+// insertTicketCommitment decodes and stores a ticket commitment output script.
+// A script that cannot be decoded is logged and skipped, and false is
+// returned.
+func insertTicketCommitment(stmt *sql.Stmt, ticketHash string, txIndex uint32,
+	pkScript []byte, params *chaincfg.Params) (bool, error) {
+	c, err := txhelpers.DecodeTicketCommitment(pkScript, params)
+	if err != nil {
+		log.Warnf("Skipping commitment of ticket %s output %d: %v",
+			ticketHash, txIndex, err)
+		return false, nil
+	}
+	_, err = stmt.Exec(ticketHash, txIndex, c.Address, c.Amount,
+		c.VoteFeeLimit, c.RevokeFeeLimit)
+	return err == nil, err
+}
+
+// insertTicketCommitments stores the commitment outputs, which are the odd
+// numbered outputs, of the ticket purchases among the given transactions in
+// the database transaction. The insert statement is prepared once, and only if
+// there are ticket purchases.
+func insertTicketCommitments(dbTx *sql.Tx, txns []*dbtypes.Tx, params *chaincfg.Params) error {
+	var stmt *sql.Stmt
+	for _, tx := range txns {
+		if tx.TxType != int16(stake.TxTypeSStx) {
+			continue
+		}
+		if stmt == nil {
+			var err error
+			if stmt, err = dbTx.Prepare(internal.InsertTicketCommitment); err != nil {
+				return err
+			}
+			defer stmt.Close()
+		}
+		for i := 1; i < len(tx.Vouts); i += 2 {
+			_, err := insertTicketCommitment(stmt, tx.TxID, uint32(i),
+				tx.Vouts[i].ScriptPubKey, params)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// populateTicketCommitments stores the commitment outputs of all of the
+// tickets in the tickets table.
+func populateTicketCommitments(db *sql.DB, params *chaincfg.Params) error {
+	rows, err := db.Query(internal.SelectTicketCommitmentVouts)
+	if err != nil {
+		return err
+	}
+	defer closeRows(rows)
+
+	stmt, err := db.Prepare(internal.InsertTicketCommitment)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var count int64
+	for rows.Next() {
+		var ticketHash string
+		var txIndex uint32
+		var pkScript []byte
+		if err = rows.Scan(&ticketHash, &txIndex, &pkScript); err != nil {
+			return err
+		}
+		inserted, err := insertTicketCommitment(stmt, ticketHash, txIndex,
+			pkScript, params)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			count++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Decoded %d ticket commitments.", count)
+	return nil
+}
+
+// retrieveTicketCommitmentsByAddress retrieves up to limit commitments of main
+// chain tickets to the given reward address, skipping offset, and the total
+// number of such commitments. The most recent tickets are first.
+func retrieveTicketCommitmentsByAddress(ctx context.Context, db *sql.DB, address string,
+	limit, offset int) ([]*dbtypes.TicketCommitment, int64, error) {
+	var total int64
+	err := db.QueryRowContext(ctx, internal.SelectTicketCommitmentsCountByAddress,
+		address).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.QueryContext(ctx, internal.SelectTicketCommitmentsByAddress,
+		address, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer closeRows(rows)
+
+	var commitments []*dbtypes.TicketCommitment
+	for rows.Next() {
+		var c dbtypes.TicketCommitment
+		var amount, voteFeeLimit, revokeFeeLimit int64
+		var spendType dbtypes.TicketSpendType
+		var poolStatus dbtypes.TicketPoolStatus
+		err = rows.Scan(&c.TicketHash, &c.TxIndex, &c.BlockHeight, &c.Price,
+			&amount, &voteFeeLimit, &revokeFeeLimit, &spendType, &poolStatus)
+		if err != nil {
+			return nil, 0, err
+		}
+		c.Amount = fnoutil.Amount(amount).ToCoin()
+		c.VoteFeeLimit = fnoutil.Amount(voteFeeLimit).ToCoin()
+		c.RevokeFeeLimit = fnoutil.Amount(revokeFeeLimit).ToCoin()
+		c.SpendType = spendType.String()
+		c.PoolStatus = poolStatus.String()
+		commitments = append(commitments, &c)
+	}
+	return commitments, total, rows.Err()
+}
+
+// --- block_miners table ---
+
+// insertBlockMiner stores the coinbase data and mining pool of a block.
+func insertBlockMiner(db *sql.DB, miner *dbtypes.BlockMiner) error {
+	_, err := db.Exec(internal.UpsertBlockMiner, miner.Hash, miner.CoinbaseText,
+		pq.Array(miner.PayoutAddresses), miner.Pool)
+	return err
+}
+
+// populateBlockMiners stores the coinbase data of all of the main chain blocks,
+// fetching the blocks from the node. The blocks are attributed to mining pools
+// when the pools are set.
+func populateBlockMiners(db *sql.DB, bg BlockGetter, params *chaincfg.Params) error {
+	rows, err := db.Query(internal.SelectMainchainBlockHashes)
+	if err != nil {
+		return err
+	}
+	defer closeRows(rows)
+
+	stmt, err := db.Prepare(internal.UpsertBlockMiner)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var count int64
+	for rows.Next() {
+		var blockHash string
+		if err = rows.Scan(&blockHash); err != nil {
+			return err
+		}
+		hash, err := chainhash.NewHashFromStr(blockHash)
+		if err != nil {
+			return err
+		}
+		msgBlock, err := bg.GetBlock(hash)
+		if err != nil {
+			return fmt.Errorf("GetBlock(%v): %v", hash, err)
+		}
+		miner := newBlockMiner(msgBlock, params)
+		_, err = stmt.Exec(miner.Hash, miner.CoinbaseText,
+			pq.Array(miner.PayoutAddresses), miner.Pool)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Indexed the coinbase data of %d blocks.", count)
+	return nil
+}
+
+// retrieveAllBlockMiners retrieves the coinbase data and mining pool of every
+// stored block. The heights are not set.
+func retrieveAllBlockMiners(ctx context.Context, db *sql.DB) ([]*dbtypes.BlockMiner, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAllBlockMiners)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var miners []*dbtypes.BlockMiner
+	for rows.Next() {
+		var miner dbtypes.BlockMiner
+		err = rows.Scan(&miner.Hash, &miner.CoinbaseText,
+			pq.Array(&miner.PayoutAddresses), &miner.Pool)
+		if err != nil {
+			return nil, err
+		}
+		miners = append(miners, &miner)
+	}
+	return miners, rows.Err()
+}
+
+// updateBlockMinerPools sets the mining pools of the given blocks with a single
+// UPDATE.
+func updateBlockMinerPools(db *sql.DB, miners []*dbtypes.BlockMiner) error {
+	hashes := make([]string, 0, len(miners))
+	pools := make([]string, 0, len(miners))
+	for _, miner := range miners {
+		hashes = append(hashes, miner.Hash)
+		pools = append(pools, miner.Pool)
+	}
+	_, err := db.Exec(internal.UpdateBlockMinerPools, pq.Array(hashes),
+		pq.Array(pools))
+	return err
+}
+
+// retrieveMiningPoolsHash retrieves the hash of the mining pools that the
+// stored blocks were last attributed to. An empty string is returned if the
+// blocks were never reattributed.
+func retrieveMiningPoolsHash(ctx context.Context, db *sql.DB) (hash string, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectMiningPoolsHash).Scan(&hash)
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// storeMiningPoolsHash stores the hash of the mining pools that the stored
+// blocks were last attributed to.
+func storeMiningPoolsHash(db *sql.DB, hash string) error {
+	_, err := db.Exec(internal.UpsertMiningPoolsHash, hash)
+	return err
+}
+
+// retrieveBlockMiner retrieves the coinbase data and mining pool of a block.
+func retrieveBlockMiner(ctx context.Context, db *sql.DB, hash string) (*dbtypes.BlockMiner, error) {
+	miner := &dbtypes.BlockMiner{Hash: hash}
+	err := db.QueryRowContext(ctx, internal.SelectBlockMiner, hash).Scan(&miner.Height,
+		&miner.CoinbaseText, pq.Array(&miner.PayoutAddresses), &miner.Pool)
+	if err != nil {
+		return nil, err
+	}
+	return miner, nil
+}
+
+// retrieveMiningPoolStats counts the main chain blocks above the given height
+// by mining pool. The shares are not set.
+func retrieveMiningPoolStats(ctx context.Context, db *sql.DB, sinceHeight int64) ([]*dbtypes.MiningPoolStats, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMiningPoolStats, sinceHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var stats []*dbtypes.MiningPoolStats
+	for rows.Next() {
+		var s dbtypes.MiningPoolStats
+		if err = rows.Scan(&s.Pool, &s.Blocks, &s.LastHeight); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &s)
+	}
+	return stats, rows.Err()
+}
+
+// retrieveMiningPoolWindows counts the main chain blocks by mining pool and
+// ticket price window.
+func retrieveMiningPoolWindows(ctx context.Context, db *sql.DB, windowSize int64) ([]miningPoolWindowCount, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMiningPoolWindows, windowSize)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var counts []miningPoolWindowCount
+	for rows.Next() {
+		var c miningPoolWindowCount
+		if err = rows.Scan(&c.window, &c.pool, &c.count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// --- stake pools ---
+
+// retrieveStakePoolFeeAddresses retrieves the detected stake pool fee
+// addresses, to which the pool fee commitments of tickets with at least
+// minVoters distinct voting addresses pay.
+func retrieveStakePoolFeeAddresses(ctx context.Context, db *sql.DB, minVoters int64) ([]string, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStakePoolFeeAddresses, minVoters)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err = rows.Scan(&address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// retrieveStakePoolStats retrieves the main chain ticket counts of the stake
+// pools by pool status.
+func retrieveStakePoolStats(ctx context.Context, db *sql.DB, pools []*dbtypes.StakePool) ([]*dbtypes.StakePoolStats, error) {
+	var addresses, names []string
+	stats := make([]*dbtypes.StakePoolStats, 0, len(pools))
+	statsByName := make(map[string]*dbtypes.StakePoolStats, len(pools))
+	for _, pool := range pools {
+		for _, address := range pool.Addresses {
+			addresses = append(addresses, address)
+			names = append(names, pool.Name)
+		}
+		ps := &dbtypes.StakePoolStats{StakePool: pool}
+		stats = append(stats, ps)
+		statsByName[pool.Name] = ps
+	}
+	if len(addresses) == 0 {
+		return stats, nil
+	}
+
+	rows, err := db.QueryContext(ctx, internal.SelectStakePoolTicketCounts,
+		pq.Array(addresses), pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var name string
+		var poolStatus dbtypes.TicketPoolStatus
+		var count int64
+		if err = rows.Scan(&name, &poolStatus, &count); err != nil {
+			return nil, err
+		}
+		ps := statsByName[name]
+		if ps == nil {
+			continue
+		}
+		switch poolStatus {
+		case dbtypes.PoolStatusLive:
+			ps.Live = count
+		case dbtypes.PoolStatusVoted:
+			ps.Voted = count
+		case dbtypes.PoolStatusExpired:
+			ps.Expired = count
+		case dbtypes.PoolStatusMissed:
+			ps.Missed = count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, ps := range stats {
+		if called := ps.Voted + ps.Missed; called > 0 {
+			ps.MissRate = float64(ps.Missed) / float64(called)
+		}
+	}
+	return stats, nil
+}
+
+// retrieveStakePoolTicketWindows retrieves the main chain ticket counts of the
+// stake pool with the given addresses, binned by the ticket price windows in
+// which the tickets matured and left the live ticket pool.
+func retrieveStakePoolTicketWindows(ctx context.Context, db *sql.DB, addresses []string,
+	params *chaincfg.Params) ([]stakePoolWindowCount, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStakePoolTicketWindows,
+		pq.Array(addresses), params.TicketMaturity, params.TicketExpiry,
+		params.StakeDiffWindowSize)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var counts []stakePoolWindowCount
+	for rows.Next() {
+		var c stakePoolWindowCount
+		var exitWindow sql.NullInt64
+		err = rows.Scan(&c.matureWindow, &c.poolStatus, &exitWindow, &c.count)
+		if err != nil {
+			return nil, err
+		}
+		c.exitWindow = -1
+		if exitWindow.Valid {
+			c.exitWindow = exitWindow.Int64
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// --- versions ---
+
+// retrieveVersionCounts retrieves the counts of blocks or votes by version in
+// each stake version interval of intervalSize blocks, starting with the block
+// at minHeight. stmt is one of the version count statements.
+func retrieveVersionCounts(ctx context.Context, db *sql.DB, stmt string,
+	intervalSize, minHeight int64) ([]versionCount, error) {
+	rows, err := db.QueryContext(ctx, stmt, intervalSize, minHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var counts []versionCount
+	for rows.Next() {
+		var c versionCount
+		if err = rows.Scan(&c.interval, &c.version, &c.count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// --- treasury ---
+
+func retrieveTreasuryInflows(ctx context.Context, db *sql.DB, address string,
+	params *chaincfg.Params) ([]treasuryInflow, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTreasuryCoinbaseInflows,
+		address, params.SubsidyReductionInterval)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var coinbases []treasuryCoinbases
+	for rows.Next() {
+		var cb treasuryCoinbases
+		err = rows.Scan(&cb.month, &cb.height, &cb.voters, &cb.amount, &cb.count)
+		if err != nil {
+			return nil, err
+		}
+		coinbases = append(coinbases, cb)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taxAt := func(height int64, voters uint16) int64 {
+		_, _, tax := txhelpers.RewardsAtBlock(height, voters, params)
+		return tax
+	}
+	inflows := treasuryCoinbaseInflows(coinbases, taxAt)
+
+	rows, err = db.QueryContext(ctx, internal.SelectTreasuryInflowsByMonth, address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var in treasuryInflow
+		if err = rows.Scan(&in.month, &in.amount); err != nil {
+			return nil, err
+		}
+		inflows = append(inflows, in)
+	}
+	return inflows, rows.Err()
+}
+
+func retrieveTreasurySpends(ctx context.Context, db *sql.DB, address string) ([]treasurySpendTx, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTreasurySpends, address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var spends []treasurySpendTx
+	for rows.Next() {
+		var tx treasurySpendTx
+		err = rows.Scan(&tx.txHash, &tx.height, &tx.time, &tx.sent, &tx.returned)
+		if err != nil {
+			return nil, err
+		}
+		spends = append(spends, tx)
+	}
+	return spends, rows.Err()
+}
+
+// --- supply ---
+
+// retrieveSupplyChart fetches the amounts burned and the project fund flows in
+// each block above the height of the charts' supply data.
+func retrieveSupplyChart(ctx context.Context, db *sql.DB, charts *cache.ChartData,
+	devAddress, zeroAddress string) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectSupplyChart, charts.SupplyTip(),
+		devAddress, zeroAddress)
+}
+
+// Append the results from retrieveSupplyChart to the provided ChartData. This
+// is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendSupplyChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, burned, received, sent uint64
+		if err := rows.Scan(&height, &burned, &received, &sent); err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.Burned)) {
+			return fmt.Errorf("appendSupplyChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.Burned))
+		}
+		blocks.Burned = append(blocks.Burned, burned)
+		blocks.DevFundIn = append(blocks.DevFundIn, received)
+		blocks.DevFundOut = append(blocks.DevFundOut, sent)
+	}
+	return rows.Err()
+}
+
+// --- fee rates ---
+
+// feeRatesArrays converts the fee rate statistics of a block to the arrays
+// stored in the fee_rates and ticket_fee_rates columns of the blocks table.
+// Missing statistics are NULL.
+func feeRatesArrays(feeRates *dbtypes.BlockFeeRates) (regular, tickets dbtypes.UInt64Array) {
+	if feeRates == nil {
+		return nil, nil
+	}
+	return feeRates.Regular.Array(), feeRates.Tickets.Array()
+}
+
+// updateBlockFeeRates sets the fee rate statistics of the block with the given
+// hash.
+func updateBlockFeeRates(db *sql.DB, hash string, feeRates *dbtypes.BlockFeeRates) error {
+	regular, tickets := feeRatesArrays(feeRates)
+	_, err := db.Exec(internal.UpdateBlockFeeRates, hash, regular, tickets)
+	return err
+}
+
+// retrieveBlockFeeRates gets the fee rate statistics of the block with the
+// given hash. The statistics are nil if they were not computed for the block.
+func retrieveBlockFeeRates(ctx context.Context, db *sql.DB, hash string) (*dbtypes.BlockFeeRates, error) {
+	var regular, tickets dbtypes.UInt64Array
+	err := db.QueryRowContext(ctx, internal.SelectBlockFeeRates, hash).
+		Scan(&regular, &tickets)
+	if err != nil {
+		return nil, err
+	}
+	if regular == nil && tickets == nil {
+		return nil, nil
+	}
+	return &dbtypes.BlockFeeRates{
+		Regular: dbtypes.FeeRateStatsFromArray(regular),
+		Tickets: dbtypes.FeeRateStatsFromArray(tickets),
+	}, nil
+}
+
+// retrieveFeeRatesChart fetches the fee rate statistics of the main chain
+// blocks that are not yet in the ChartData.
+func retrieveFeeRatesChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectBlocksFeeRates, charts.FeeRatesTip())
+}
+
+// retrieveFeeRatesCount fetches the total number of transactions described by
+// the fee rate statistics of the main chain blocks up to height tip.
+func retrieveFeeRatesCount(ctx context.Context, db *sql.DB, tip int32) (count uint64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectBlocksFeeRatesCount, tip).Scan(&count)
+	return
+}
+
+// Append the results from retrieveFeeRatesChart to the provided ChartData.
+// This is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendFeeRatesChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height uint64
+		var regular, tickets dbtypes.UInt64Array
+		if err := rows.Scan(&height, &regular, &tickets); err != nil {
+			return err
+		}
+		if height != uint64(blocks.FeeRates.Length()) {
+			return fmt.Errorf("appendFeeRatesChart: height misalignment. "+
+				"height = %d, data length = %d", height, blocks.FeeRates.Length())
+		}
+		blocks.FeeRates.Append(dbtypes.FeeRateStatsFromArray(regular))
+		blocks.TicketFeeRates.Append(dbtypes.FeeRateStatsFromArray(tickets))
+	}
+	return rows.Err()
+}
+
+// --- address usage and UTXO set ---
+
+// blockDataChunkBlocks is the number of blocks whose data is computed by each
+// query of populateBlockData.
+const blockDataChunkBlocks = 2000
+
+// populateBlockData computes and stores the data of the main chain blocks above
+// the highest block with stored data, in chunks of blockDataChunkBlocks blocks.
+// selectTip gets the height of the highest main chain block with stored data,
+// and upsert computes and stores the data of the blocks in a range of heights.
+// what describes the data in the log.
+func populateBlockData(db *sql.DB, selectTip, upsert, what string) error {
+	var tip int64
+	if err := db.QueryRow(selectTip).Scan(&tip); err != nil {
+		return err
+	}
+	bestHeight, _, err := RetrieveBestBlock(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	for from := tip + 1; from <= bestHeight; from += blockDataChunkBlocks {
+		to := from + blockDataChunkBlocks - 1
+		if to > bestHeight {
+			to = bestHeight
+		}
+		if _, err = db.Exec(upsert, from, to); err != nil {
+			return err
+		}
+		log.Infof("Computed the %s of blocks %d to %d of %d.", what, from, to,
+			bestHeight)
+	}
+	return nil
+}
+
+// storeAddressUsage computes and stores the address usage of the main chain
+// blocks at heights from through to.
+func storeAddressUsage(ctx context.Context, db *sql.DB, from, to int64) error {
+	_, err := db.ExecContext(ctx, internal.UpsertAddressUsage, from, to)
+	return err
+}
+
+// populateAddressUsage computes and stores the address usage of the main chain
+// blocks above the highest block with stored usage.
+func populateAddressUsage(db *sql.DB) error {
+	return populateBlockData(db, internal.SelectAddressUsageTip,
+		internal.UpsertAddressUsage, "address usage")
+}
+
+// retrieveAddressUsageChart fetches the stored numbers of active and new
+// addresses in each block above the height of the charts' address usage data.
+func retrieveAddressUsageChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectAddressUsageChart, charts.AddressUsageTip())
+}
+
+// Append the results from retrieveAddressUsageChart to the provided ChartData.
+// This is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendAddressUsageChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, active, dayActive, newAddresses uint64
+		if err := rows.Scan(&height, &active, &dayActive, &newAddresses); err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.ActiveAddresses)) {
+			return fmt.Errorf("appendAddressUsageChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.ActiveAddresses))
+		}
+		blocks.ActiveAddresses = append(blocks.ActiveAddresses, active)
+		blocks.DayActiveAddresses = append(blocks.DayActiveAddresses, dayActive)
+		blocks.NewAddresses = append(blocks.NewAddresses, newAddresses)
+	}
+	return rows.Err()
+}
+
+// storeUTXOSetChanges computes and stores the UTXO set changes of the main
+// chain blocks at heights from through to.
+func storeUTXOSetChanges(ctx context.Context, db *sql.DB, from, to int64) error {
+	_, err := db.ExecContext(ctx, internal.UpsertUTXOSetChanges, from, to)
+	return err
+}
+
+// populateUTXOSetChanges computes and stores the UTXO set changes of the main
+// chain blocks above the highest block with stored changes.
+func populateUTXOSetChanges(db *sql.DB) error {
+	return populateBlockData(db, internal.SelectUTXOSetChangesTip,
+		internal.UpsertUTXOSetChanges, "UTXO set changes")
+}
+
+// retrieveUTXOSetChart fetches the stored outputs created and spent, and the
+// regular transaction values, in each block above the height of the charts'
+// UTXO set data.
+func retrieveUTXOSetChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectUTXOSetChart, charts.UTXOSetTip())
+}
+
+// Append the results from retrieveUTXOSetChart to the provided ChartData. This
+// is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendUTXOSetChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, created, createdValue, spent, spentValue, txValue, txCount uint64
+		err := rows.Scan(&height, &created, &createdValue, &spent, &spentValue,
+			&txValue, &txCount)
+		if err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.UTXOCount)) {
+			return fmt.Errorf("appendUTXOSetChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.UTXOCount))
+		}
+		blocks.AppendUTXOChange(created, createdValue, spent, spentValue)
+		blocks.TxValue = append(blocks.TxValue, txValue)
+		blocks.RegularTxCount = append(blocks.RegularTxCount, txCount)
+	}
+	return rows.Err()
+}
+
+// --- coin-days destroyed and UTXO ages ---
+
+// storeCoinDaysDestroyed computes and stores the coin-days destroyed by the
+// main chain blocks at heights from through to.
+func storeCoinDaysDestroyed(db *sql.DB, from, to int64) error {
+	_, err := db.Exec(internal.UpsertCoinDaysDestroyed, from, to)
+	return err
+}
+
+// populateCoinDaysDestroyed computes and stores the coin-days destroyed by the
+// main chain blocks above the highest block with a stored value.
+func populateCoinDaysDestroyed(db *sql.DB) error {
+	return populateBlockData(db, internal.SelectCoinDaysDestroyedTip,
+		internal.UpsertCoinDaysDestroyed, "coin-days destroyed")
+}
+
+// retrieveCoinDaysDestroyedChart fetches the stored coin-days destroyed in each
+// block above the height of the charts' coin-days destroyed data.
+func retrieveCoinDaysDestroyedChart(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	return db.QueryContext(ctx, internal.SelectCoinDaysDestroyedChart, charts.CoinDaysDestroyedTip())
+}
+
+// Append the results from retrieveCoinDaysDestroyedChart to the provided
+// ChartData. This is the Appender half of a pair that make up a
+// cache.ChartUpdater.
+func appendCoinDaysDestroyedChart(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height uint64
+		var coinDays float64
+		if err := rows.Scan(&height, &coinDays); err != nil {
+			return err
+		}
+		if height != uint64(len(blocks.CoinDaysDestroyed)) {
+			return fmt.Errorf("appendCoinDaysDestroyedChart: height misalignment. "+
+				"height = %d, data length = %d", height, len(blocks.CoinDaysDestroyed))
+		}
+		blocks.CoinDaysDestroyed = append(blocks.CoinDaysDestroyed, coinDays)
+	}
+	return rows.Err()
+}
+
+// deleteUTXOAgesAfter deletes the UTXO age distributions measured after t, and
+// returns the number deleted.
+func deleteUTXOAgesAfter(db *sql.DB, t time.Time) (int64, error) {
+	res, err := db.Exec(internal.DeleteUTXOAgesAfter, t)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// retrieveLastUTXOAgesTime gets the time of the most recent stored UTXO age
+// distribution, which is not valid if there are none.
+func retrieveLastUTXOAgesTime(ctx context.Context, db *sql.DB) (pq.NullTime, error) {
+	var lastTime pq.NullTime
+	err := db.QueryRowContext(ctx, internal.SelectLastUTXOAgesTime).Scan(&lastTime)
+	return lastTime, err
+}
+
+// retrieveUTXOAgeCells gets the number and value of the outputs by the day they
+// were created and spent, skipping those spent before spentSince and those
+// created at or after createdBefore.
+func retrieveUTXOAgeCells(ctx context.Context, db *sql.DB, spentSince, createdBefore time.Time) ([]utxoAgeCell, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectUTXOAgeCells, spentSince,
+		createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var cells []utxoAgeCell
+	for rows.Next() {
+		var c utxoAgeCell
+		var spent sql.NullInt64
+		if err = rows.Scan(&c.created, &spent, &c.count, &c.value); err != nil {
+			return nil, err
+		}
+		c.spent = -1
+		if spent.Valid {
+			c.spent = spent.Int64
+		}
+		cells = append(cells, c)
+	}
+	return cells, rows.Err()
+}
+
+// upsertUTXOAges stores the UTXO age distributions.
+func upsertUTXOAges(db *sql.DB, ages []*dbtypes.UTXOAges) error {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+
+	stmt, err := dbtx.Prepare(internal.UpsertUTXOAges)
+	if err != nil {
+		_ = dbtx.Rollback() // try, but we want the Prepare error back
+		return err
+	}
+
+	for _, a := range ages {
+		_, err = stmt.Exec(time.Unix(a.Time, 0), pq.Int64Array(a.Counts),
+			pq.Int64Array(a.Amounts))
+		if err != nil {
+			_ = stmt.Close()
+			_ = dbtx.Rollback()
+			return err
+		}
+	}
+
+	_ = stmt.Close()
+	return dbtx.Commit()
+}
+
+// retrieveUTXOAges gets the stored UTXO age distributions, oldest first.
+func retrieveUTXOAges(ctx context.Context, db *sql.DB) ([]*dbtypes.UTXOAges, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectUTXOAges)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	ages := []*dbtypes.UTXOAges{}
+	for rows.Next() {
+		var a dbtypes.UTXOAges
+		var t dbtypes.TimeDef
+		err = rows.Scan(&t, (*pq.Int64Array)(&a.Counts), (*pq.Int64Array)(&a.Amounts))
+		if err != nil {
+			return nil, err
+		}
+		a.Time = t.UNIX()
+		ages = append(ages, &a)
+	}
+	return ages, rows.Err()
+}
+
 // --- blocks and block_chain tables ---
 
 func InsertBlock(db *sql.DB, dbBlock *dbtypes.Block, isValid, isMainchain, checked bool) (uint64, error) {
 	insertStatement := internal.MakeBlockInsertStatement(dbBlock, checked)
+	feeRates, ticketFeeRates := feeRatesArrays(dbBlock.FeeRates)
 	var id uint64
 	err := db.QueryRow(insertStatement,
 		dbBlock.Hash, dbBlock.Height, dbBlock.Size, isValid, isMainchain,
@@ -3314,7 +5140,7 @@ func InsertBlock(db *sql.DB, dbBlock *dbtypes.Block, isValid, isMainchain, check
 		dbBlock.Time, dbBlock.Nonce, dbBlock.VoteBits, dbBlock.Voters,
 		dbBlock.FreshStake, dbBlock.Revocations, dbBlock.PoolSize, dbBlock.Bits,
 		dbBlock.SBits, dbBlock.Difficulty, dbBlock.StakeVersion,
-		dbBlock.PreviousHash, dbBlock.ChainWork).Scan(&id)
+		dbBlock.PreviousHash, dbBlock.ChainWork, feeRates, ticketFeeRates).Scan(&id)
 	return id, err
 }
 
@@ -75,6 +75,10 @@ func deleteAddressesForBlock(dbTx *sql.Tx, hash string) (rowsDeleted int64, err
 	return sqlExec(dbTx, internal.DeleteAddresses, "failed to delete addresses", hash)
 }
 
+func deleteBlockFilterForBlock(dbTx *sql.Tx, hash string) (rowsDeleted int64, err error) {
+	return sqlExec(dbTx, internal.DeleteBlockFilter, "failed to delete block filter", hash)
+}
+
 func deleteBlock(dbTx *sql.Tx, hash string) (rowsDeleted int64, err error) {
 	return sqlExec(dbTx, internal.DeleteBlock, "failed to delete block", hash)
 }
@@ -134,7 +138,7 @@ func RetrieveTxsBestBlockMainchain(ctx context.Context, db *sql.DB) (height int6
 
 // DeleteBlockData removes all data for the specified block from every table.
 // Data are removed from tables in the following order: vins, vouts, addresses,
-// transactions, tickets, votes, misses, blocks, block_chain.
+// transactions, tickets, votes, misses, block_filters, blocks, block_chain.
 // WARNING: When no indexes are present, these queries are VERY SLOW.
 func DeleteBlockData(ctx context.Context, db *sql.DB, hash string) (res dbtypes.DeletionSummary, err error) {
 	// The data purge is an all or nothing operation (no partial removal of
@@ -208,6 +212,14 @@ func DeleteBlockData(ctx context.Context, db *sql.DB, hash string) (res dbtypes.
 	}
 	res.Timings.Misses = time.Since(start).Nanoseconds()
 
+	// The block's committed filter is removed so that the filter of a block
+	// stored again at this height does not extend a stale filter header chain.
+	if _, err = deleteBlockFilterForBlock(dbTx, hash); err != nil {
+		err = fmt.Errorf(`deleteBlockFilterForBlock failed with "%v". Rollback: %v`,
+			err, dbTx.Rollback())
+		return
+	}
+
 	start = time.Now()
 	if res.Blocks, err = deleteBlock(dbTx, hash); err != nil {
 		err = fmt.Errorf(`deleteBlock failed with "%v". Rollback: %v`,
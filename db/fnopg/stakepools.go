@@ -0,0 +1,226 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/chappjc/trylock"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// minPoolFeeVoters is the number of distinct voting addresses whose tickets
+// must pay pool fees to a reward address for the address to be detected as a
+// stake pool fee address. Solo stakers and split ticket participants rarely
+// share a reward address across so many voting addresses.
+const minPoolFeeVoters = 20
+
+// stakePoolRegistry is the mutex-protected registry of configured and
+// detected stake pools. Detection is run in the background by
+// DetectStakePools, once per ticket price window.
+type stakePoolRegistry struct {
+	mtx          sync.Mutex
+	configured   []*dbtypes.StakePool
+	detected     []*dbtypes.StakePool
+	detectHeight int64
+	detectLock   trylock.Mutex
+}
+
+// stakePoolWindowCount is the number of a stake pool's tickets with the given
+// pool status that matured in one ticket price window and left the live ticket
+// pool in another. exitWindow is -1 for live tickets.
+type stakePoolWindowCount struct {
+	matureWindow int64
+	exitWindow   int64
+	poolStatus   dbtypes.TicketPoolStatus
+	count        int64
+}
+
+// SetStakePools sets the known stake pools. Pools with other pool fee
+// addresses are detected from the ticket commitments.
+func (pgb *ChainDB) SetStakePools(pools []*dbtypes.StakePool) {
+	if pgb == nil {
+		return
+	}
+	pgb.stakePools.mtx.Lock()
+	pgb.stakePools.configured = pools
+	pgb.stakePools.mtx.Unlock()
+}
+
+// StakePools returns the configured stake pools followed by the detected
+// stake pools, most tickets first. The detected pools are those found by the
+// last run of DetectStakePools.
+func (pgb *ChainDB) StakePools() []*dbtypes.StakePool {
+	reg := &pgb.stakePools
+	reg.mtx.Lock()
+	defer reg.mtx.Unlock()
+	pools := make([]*dbtypes.StakePool, 0, len(reg.configured)+len(reg.detected))
+	pools = append(pools, reg.configured...)
+	return append(pools, reg.detected...)
+}
+
+// DetectStakePools detects the stake pools not configured by their pool fee
+// addresses if detection has not yet run in the current ticket price window.
+// If detection fails, it is retried in the next window, and the previously
+// detected pools are kept. If detection is already in progress,
+// DetectStakePools returns immediately.
+func (pgb *ChainDB) DetectStakePools() error {
+	reg := &pgb.stakePools
+	if !reg.detectLock.TryLock() {
+		log.Debugf("Stake pool detection already in progress.")
+		return nil
+	}
+	defer reg.detectLock.Unlock()
+
+	height := pgb.Height()
+	reg.mtx.Lock()
+	due := reg.detectHeight == 0 || height-reg.detectHeight >= pgb.chainParams.StakeDiffWindowSize
+	if due {
+		reg.detectHeight = height
+	}
+	reg.mtx.Unlock()
+	if !due {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	addresses, err := retrieveStakePoolFeeAddresses(ctx, pgb.db, minPoolFeeVoters)
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+
+	reg.mtx.Lock()
+	defer reg.mtx.Unlock()
+	reg.detected = detectedStakePools(addresses, reg.configured)
+	return nil
+}
+
+// detectedStakePools makes the detected stake pools of the pool fee addresses
+// that are not addresses of the configured pools.
+func detectedStakePools(addresses []string, configured []*dbtypes.StakePool) []*dbtypes.StakePool {
+	detected := make([]*dbtypes.StakePool, 0, len(addresses))
+addresses:
+	for _, address := range addresses {
+		for _, pool := range configured {
+			if pool.HasAddress(address) {
+				continue addresses
+			}
+		}
+		detected = append(detected, &dbtypes.StakePool{
+			Name:      address,
+			Addresses: []string{address},
+			Detected:  true,
+		})
+	}
+	return detected
+}
+
+// StakePool finds the stake pool with the given name or address, returning
+// nil if there is no such pool.
+func (pgb *ChainDB) StakePool(nameOrAddress string) *dbtypes.StakePool {
+	for _, pool := range pgb.StakePools() {
+		if pool.Name == nameOrAddress || pool.HasAddress(nameOrAddress) {
+			return pool
+		}
+	}
+	return nil
+}
+
+// StakePoolStats retrieves the ticket counts and miss rates of the stake
+// pools, most live tickets first.
+func (pgb *ChainDB) StakePoolStats() ([]*dbtypes.StakePoolStats, error) {
+	pools := pgb.StakePools()
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	stats, err := retrieveStakePoolStats(ctx, pgb.db, pools)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Live > stats[j].Live
+	})
+	return stats, nil
+}
+
+// StakePoolChart retrieves the live tickets, votes, misses, and miss rate of
+// the stake pool in each ticket price window.
+func (pgb *ChainDB) StakePoolChart(pool *dbtypes.StakePool) (*dbtypes.StakePoolChart, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	counts, err := retrieveStakePoolTicketWindows(ctx, pgb.db, pool.Addresses,
+		pgb.chainParams)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	lastWindow := pgb.Height() / pgb.chainParams.StakeDiffWindowSize
+	return stakePoolChart(counts, lastWindow, pgb.chainParams.StakeDiffWindowSize), nil
+}
+
+// stakePoolChart makes the StakePoolChart of a stake pool from its ticket
+// counts by window, through the window lastWindow. The chart starts with the
+// window in which the first of the pool's tickets matured.
+func stakePoolChart(counts []stakePoolWindowCount, lastWindow, windowSize int64) *dbtypes.StakePoolChart {
+	chart := &dbtypes.StakePoolChart{
+		Height:   []int64{},
+		Live:     []int64{},
+		Votes:    []int64{},
+		Misses:   []int64{},
+		MissRate: []float64{},
+	}
+	if len(counts) == 0 {
+		return chart
+	}
+
+	firstWindow := counts[0].matureWindow
+	for _, c := range counts {
+		if c.matureWindow < firstWindow {
+			firstWindow = c.matureWindow
+		}
+	}
+	if lastWindow < firstWindow {
+		return chart
+	}
+
+	n := lastWindow - firstWindow + 1
+	change := make([]int64, n)
+	votes := make([]int64, n)
+	misses := make([]int64, n)
+	inRange := func(window int64) bool {
+		return window >= firstWindow && window <= lastWindow
+	}
+	for _, c := range counts {
+		if inRange(c.matureWindow) {
+			change[c.matureWindow-firstWindow] += c.count
+		}
+		if !inRange(c.exitWindow) {
+			continue
+		}
+		i := c.exitWindow - firstWindow
+		change[i] -= c.count
+		switch c.poolStatus {
+		case dbtypes.PoolStatusVoted:
+			votes[i] += c.count
+		case dbtypes.PoolStatusMissed:
+			misses[i] += c.count
+		}
+	}
+
+	var live int64
+	for i := int64(0); i < n; i++ {
+		live += change[i]
+		var missRate float64
+		if called := votes[i] + misses[i]; called > 0 {
+			missRate = float64(misses[i]) / float64(called)
+		}
+		chart.Height = append(chart.Height, (firstWindow+i)*windowSize)
+		chart.Live = append(chart.Live, live)
+		chart.Votes = append(chart.Votes, votes[i])
+		chart.Misses = append(chart.Misses, misses[i])
+		chart.MissRate = append(chart.MissRate, missRate)
+	}
+	return chart
+}
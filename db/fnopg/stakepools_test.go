@@ -0,0 +1,61 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestStakePoolChart(t *testing.T) {
+	const windowSize = 144
+	counts := []stakePoolWindowCount{
+		// Three tickets maturing in window 10: one voted in window 11, one
+		// missed in window 12, and one still live.
+		{matureWindow: 10, exitWindow: 11, poolStatus: dbtypes.PoolStatusVoted, count: 1},
+		{matureWindow: 10, exitWindow: 12, poolStatus: dbtypes.PoolStatusMissed, count: 1},
+		{matureWindow: 10, exitWindow: -1, poolStatus: dbtypes.PoolStatusLive, count: 1},
+		// Two tickets maturing in window 11, both voting in window 12.
+		{matureWindow: 11, exitWindow: 12, poolStatus: dbtypes.PoolStatusVoted, count: 2},
+		// An immature ticket maturing after the last window.
+		{matureWindow: 14, exitWindow: -1, poolStatus: dbtypes.PoolStatusLive, count: 1},
+	}
+
+	got := stakePoolChart(counts, 13, windowSize)
+	want := &dbtypes.StakePoolChart{
+		Height:   []int64{1440, 1584, 1728, 1872},
+		Live:     []int64{3, 4, 1, 1},
+		Votes:    []int64{0, 1, 2, 0},
+		Misses:   []int64{0, 0, 1, 0},
+		MissRate: []float64{0, 0, 1.0 / 3, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stakePoolChart:\n got %+v\nwant %+v", got, want)
+	}
+
+	empty := stakePoolChart(nil, 13, windowSize)
+	if empty.Height == nil || len(empty.Height) != 0 {
+		t.Errorf("expected an empty chart, got %+v", empty)
+	}
+}
+
+func TestDetectedStakePools(t *testing.T) {
+	configured := []*dbtypes.StakePool{
+		{Name: "pool", Addresses: []string{"Fsa", "Fsb"}},
+	}
+	got := detectedStakePools([]string{"Fsa", "Fsc", "Fsb", "Fsd"}, configured)
+	want := []*dbtypes.StakePool{
+		{Name: "Fsc", Addresses: []string{"Fsc"}, Detected: true},
+		{Name: "Fsd", Addresses: []string{"Fsd"}, Detected: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectedStakePools: got %v, want %v", got, want)
+	}
+
+	if got = detectedStakePools(nil, configured); len(got) != 0 {
+		t.Errorf("detectedStakePools: got %d pools, want none", len(got))
+	}
+}
@@ -514,6 +514,17 @@ func (db *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlockG
 		}
 	}
 
+	// The address usage and coin-days destroyed of each block are stored as
+	// blocks are stored after the batch sync, which requires the indexes.
+	log.Infof("Computing the address usage of the synchronized blocks...")
+	if err = populateAddressUsage(db.db); err != nil {
+		return nodeHeight, fmt.Errorf("failed to populate address_usage table: %v", err)
+	}
+	log.Infof("Computing the coin-days destroyed by the synchronized blocks...")
+	if err = populateCoinDaysDestroyed(db.db); err != nil {
+		return nodeHeight, fmt.Errorf("failed to populate coin_days_destroyed table: %v", err)
+	}
+
 	// After sync and indexing, must use upsert statement, which checks for
 	// duplicate entries and updates instead of throwing and error and panicing.
 	db.EnableDuplicateCheckOnInsert(true)
@@ -13,21 +13,34 @@ import (
 )
 
 var createTableStatements = map[string]string{
-	"meta":           internal.CreateMetaTable,
-	"blocks":         internal.CreateBlockTable,
-	"transactions":   internal.CreateTransactionTable,
-	"vins":           internal.CreateVinTable,
-	"vouts":          internal.CreateVoutTable,
-	"block_chain":    internal.CreateBlockPrevNextTable,
-	"addresses":      internal.CreateAddressTable,
-	"tickets":        internal.CreateTicketsTable,
-	"votes":          internal.CreateVotesTable,
-	"misses":         internal.CreateMissesTable,
-	"agendas":        internal.CreateAgendasTable,
-	"agenda_votes":   internal.CreateAgendaVotesTable,
-	"testing":        internal.CreateTestingTable,
-	"proposals":      internal.CreateProposalsTable,
-	"proposal_votes": internal.CreateProposalVotesTable,
+	"meta":                  internal.CreateMetaTable,
+	"blocks":                internal.CreateBlockTable,
+	"transactions":          internal.CreateTransactionTable,
+	"vins":                  internal.CreateVinTable,
+	"vouts":                 internal.CreateVoutTable,
+	"block_chain":           internal.CreateBlockPrevNextTable,
+	"addresses":             internal.CreateAddressTable,
+	"tickets":               internal.CreateTicketsTable,
+	"votes":                 internal.CreateVotesTable,
+	"misses":                internal.CreateMissesTable,
+	"agendas":               internal.CreateAgendasTable,
+	"agenda_votes":          internal.CreateAgendaVotesTable,
+	"testing":               internal.CreateTestingTable,
+	"proposals":             internal.CreateProposalsTable,
+	"proposal_votes":        internal.CreateProposalVotesTable,
+	"address_summary":       internal.CreateAddressSummaryTable,
+	"mempool_txs":           internal.CreateMempoolTxsTable,
+	"mempool_fee_snapshots": internal.CreateMempoolFeeSnapshotsTable,
+	"block_filters":         internal.CreateBlockFiltersTable,
+	"nulldata":              internal.CreateNullDataTable,
+	"redeem_scripts":        internal.CreateRedeemScriptsTable,
+	"ticket_commitments":    internal.CreateTicketCommitmentsTable,
+	"block_miners":          internal.CreateBlockMinersTable,
+	"utxo_ages":             internal.CreateUTXOAgesTable,
+	"address_usage":         internal.CreateAddressUsageTable,
+	"coin_days_destroyed":   internal.CreateCoinDaysDestroyedTable,
+	"mining_pool_config":    internal.CreateMiningPoolConfigTable,
+	"utxo_set_changes":      internal.CreateUTXOSetChangesTable,
 }
 
 var createTypeStatements = map[string]string{
@@ -0,0 +1,155 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// ticketHeightCount is the number of live or immature tickets purchased in the
+// block at height.
+type ticketHeightCount struct {
+	height int64
+	count  int64
+}
+
+// TicketPoolForecast projects the ticket pool over the blocks following the
+// stake database's best block, in bins of binSize blocks. The forecast cannot
+// extend past the expiry of the newest immature tickets, so blocks is limited
+// to the sum of the ticket maturity and expiry.
+func (pgb *ChainDB) TicketPoolForecast(blocks, binSize int64) (*dbtypes.TicketPoolForecast, error) {
+	if blocks <= 0 || binSize <= 0 {
+		return nil, fmt.Errorf("invalid forecast of %d blocks in bins of %d",
+			blocks, binSize)
+	}
+	params := pgb.chainParams
+	lifetime := int64(params.TicketMaturity) + int64(params.TicketExpiry)
+	if blocks > lifetime {
+		blocks = lifetime
+	}
+
+	tip, err := pgb.stakeDB.DBTipBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+	tipHeight := int64(tip.Height)
+	poolSize := int64(pgb.stakeDB.PoolSize())
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	counts, err := retrieveLiveTicketCountsByHeight(ctx, pgb.db, tipHeight-lifetime)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	return ticketPoolForecast(counts, tipHeight, tip.Timestamp, poolSize,
+		blocks, binSize, params), nil
+}
+
+// ticketPoolForecast projects the ticket pool over the blocks following the
+// block at tipHeight, when the pool had poolSize tickets, from the counts of
+// live and immature tickets by purchase height. A ticket purchased at height h
+// joins the pool with block h+TicketMaturity, and is expected to be selected
+// to vote in each later block with probability TicketsPerBlock divided by the
+// pool size, until it expires with block h+TicketMaturity+TicketExpiry.
+func ticketPoolForecast(counts []ticketHeightCount, tipHeight int64, tipTime time.Time,
+	poolSize, blocks, binSize int64, params *chaincfg.Params) *dbtypes.TicketPoolForecast {
+	forecast := &dbtypes.TicketPoolForecast{
+		TipHeight:   tipHeight,
+		TipPoolSize: poolSize,
+		Height:      []int64{},
+		Time:        []int64{},
+		Maturing:    []int64{},
+		Votes:       []float64{},
+		Expiring:    []float64{},
+		PoolSize:    []float64{},
+	}
+	if blocks <= 0 || binSize <= 0 {
+		return forecast
+	}
+
+	// A cohort is the tickets purchased in one block that will expire within
+	// the forecast. first is the index of the first forecast block in which
+	// they may be selected to vote.
+	type cohort struct {
+		count float64
+		first int64
+	}
+	maturing := make([]int64, blocks)
+	expiring := make([][]cohort, blocks)
+	inForecast := func(height int64) bool {
+		return height > tipHeight && height <= tipHeight+blocks
+	}
+	for _, c := range counts {
+		mature := c.height + int64(params.TicketMaturity)
+		expire := mature + int64(params.TicketExpiry)
+		if inForecast(mature) {
+			maturing[mature-tipHeight-1] += c.count
+		}
+		if !inForecast(expire) {
+			continue
+		}
+		first := mature - tipHeight
+		if first < 0 {
+			first = 0
+		}
+		i := expire - tipHeight - 1
+		expiring[i] = append(expiring[i], cohort{float64(c.count), first})
+	}
+
+	// survival[i] is the probability that a ticket in the pool for the first
+	// i forecast blocks is not selected to vote in any of them.
+	survival := make([]float64, blocks+1)
+	survival[0] = 1
+	ticketsPerBlock := float64(params.TicketsPerBlock)
+	pool := float64(poolSize)
+	blockTime := int64(params.TargetTimePerBlock.Seconds())
+
+	var binMaturing int64
+	var binVotes, binExpiring float64
+	for i := int64(0); i < blocks; i++ {
+		votes := ticketsPerBlock
+		if pool < votes {
+			votes = pool
+		}
+		survival[i+1] = survival[i]
+		if pool > 0 {
+			survival[i+1] *= 1 - votes/pool
+		}
+
+		var expired float64
+		for _, c := range expiring[i] {
+			if survival[c.first] > 0 {
+				expired += c.count * survival[i+1] / survival[c.first]
+			}
+		}
+
+		pool += float64(maturing[i]) - votes - expired
+		if pool < 0 {
+			pool = 0
+		}
+
+		binMaturing += maturing[i]
+		binVotes += votes
+		binExpiring += expired
+		if (i+1)%binSize != 0 && i+1 != blocks {
+			continue
+		}
+
+		height := tipHeight + i + 1
+		forecast.Height = append(forecast.Height, height)
+		forecast.Time = append(forecast.Time, tipTime.Unix()+(i+1)*blockTime)
+		forecast.Maturing = append(forecast.Maturing, binMaturing)
+		forecast.Votes = append(forecast.Votes, binVotes)
+		forecast.Expiring = append(forecast.Expiring, binExpiring)
+		forecast.PoolSize = append(forecast.PoolSize, pool)
+		binMaturing, binVotes, binExpiring = 0, 0, 0
+	}
+	return forecast
+}
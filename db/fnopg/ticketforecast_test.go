@@ -0,0 +1,63 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fonero-project/fnod/chaincfg"
+)
+
+func TestTicketPoolForecast(t *testing.T) {
+	params := chaincfg.SimNetParams
+	params.TicketMaturity = 2
+	params.TicketExpiry = 10
+	params.TicketsPerBlock = 1
+	params.TargetTimePerBlock = time.Minute
+
+	counts := []ticketHeightCount{
+		// The entire live pool, expiring with block 102.
+		{height: 90, count: 10},
+		// Immature tickets maturing with block 101.
+		{height: 99, count: 3},
+	}
+	tipTime := time.Unix(1e9, 0)
+
+	got := ticketPoolForecast(counts, 100, tipTime, 10, 5, 2, &params)
+	if !reflect.DeepEqual(got.Height, []int64{102, 104, 105}) {
+		t.Fatalf("heights %v", got.Height)
+	}
+	if !reflect.DeepEqual(got.Time, []int64{1e9 + 120, 1e9 + 240, 1e9 + 300}) {
+		t.Errorf("times %v", got.Time)
+	}
+	if !reflect.DeepEqual(got.Maturing, []int64{3, 0, 0}) {
+		t.Errorf("maturing %v", got.Maturing)
+	}
+
+	compFloats := func(name string, got, want []float64) {
+		if len(got) != len(want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+			return
+		}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", name, got, want)
+				return
+			}
+		}
+	}
+	// 9 of the 10 original tickets are expected to remain after block 101,
+	// and 11/12 of those to remain unselected in block 102, when they expire.
+	compFloats("votes", got.Votes, []float64{2, 2, 0.75})
+	compFloats("expiring", got.Expiring, []float64{8.25, 0, 0})
+	compFloats("pool size", got.PoolSize, []float64{2.75, 0.75, 0})
+
+	empty := ticketPoolForecast(counts, 100, tipTime, 10, 0, 2, &params)
+	if empty.Height == nil || len(empty.Height) != 0 {
+		t.Errorf("expected an empty forecast, got %+v", empty)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+// treasuryCache is the mutex-protected project fund flows, computed for the
+// best block at flows.Height, and the known proposals paid by project fund
+// spends, by txid.
+type treasuryCache struct {
+	mtx       sync.Mutex
+	proposals map[string]string
+	flows     *dbtypes.TreasuryFlows
+}
+
+// treasuryInflow is the amount received by the project fund in one calendar
+// month as either the project fund subsidy or other inflows.
+type treasuryInflow struct {
+	month   time.Time
+	subsidy bool
+	amount  int64
+}
+
+// treasuryCoinbases are count coinbase transactions in one calendar month that
+// each pay amount to the project fund in blocks with the same project fund
+// subsidy, such as the block at height with voters votes.
+type treasuryCoinbases struct {
+	month  time.Time
+	height int64
+	voters uint16
+	amount int64
+	count  int64
+}
+
+// treasurySpendTx is a transaction spending from the project fund, with the
+// amounts it spends from and returns to the project fund.
+type treasurySpendTx struct {
+	txHash   string
+	height   int64
+	time     time.Time
+	sent     int64
+	returned int64
+}
+
+// SetTreasurySpends sets the tokens of the Politeia proposals paid by project
+// fund spends, by txid.
+func (pgb *ChainDB) SetTreasurySpends(proposals map[string]string) {
+	if pgb == nil {
+		return
+	}
+	pgb.treasury.mtx.Lock()
+	pgb.treasury.proposals = proposals
+	pgb.treasury.flows = nil
+	pgb.treasury.mtx.Unlock()
+}
+
+// TreasuryFlows retrieves the inflows and spends of the project fund address
+// and their monthly aggregates. The flows are recomputed at most once per
+// block.
+func (pgb *ChainDB) TreasuryFlows() (*dbtypes.TreasuryFlows, error) {
+	height := pgb.Height()
+
+	pgb.treasury.mtx.Lock()
+	defer pgb.treasury.mtx.Unlock()
+	if flows := pgb.treasury.flows; flows != nil && flows.Height == height {
+		return flows, nil
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	inflows, err := retrieveTreasuryInflows(ctx, pgb.db, pgb.devAddress, pgb.chainParams)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	spends, err := retrieveTreasurySpends(ctx, pgb.db, pgb.devAddress)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	// The balance includes any pruned history.
+	balance, err := pgb.DevBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	flows := treasuryFlows(inflows, spends, pgb.treasury.proposals, balance.TotalUnspent)
+	flows.Address = pgb.devAddress
+	flows.Height = height
+	_, _, flows.TaxPerBlock = txhelpers.RewardsAtBlock(height+1,
+		pgb.chainParams.TicketsPerBlock, pgb.chainParams)
+	flows.Balance = balance.TotalUnspent
+	if balance.Pruned != nil {
+		prunedBefore := balance.Pruned.PrunedBefore
+		flows.PrunedBefore = &prunedBefore
+	}
+
+	pgb.treasury.flows = flows
+	return flows, nil
+}
+
+// treasuryCoinbaseInflows splits the amounts paid to the project fund by
+// coinbase transactions into the project fund subsidy, as given by taxAt for
+// the height and votes of the block, and other inflows paid in excess of the
+// subsidy.
+func treasuryCoinbaseInflows(coinbases []treasuryCoinbases,
+	taxAt func(height int64, voters uint16) int64) []treasuryInflow {
+	inflows := make([]treasuryInflow, 0, len(coinbases))
+	for _, cb := range coinbases {
+		subsidy := taxAt(cb.height, cb.voters)
+		if cb.amount < subsidy {
+			subsidy = cb.amount
+		}
+		if subsidy > 0 {
+			inflows = append(inflows, treasuryInflow{
+				month:   cb.month,
+				subsidy: true,
+				amount:  subsidy * cb.count,
+			})
+		}
+		if other := cb.amount - subsidy; other > 0 {
+			inflows = append(inflows, treasuryInflow{
+				month:  cb.month,
+				amount: other * cb.count,
+			})
+		}
+	}
+	return inflows
+}
+
+// treasuryFlows computes the totals and monthly aggregates of the project fund
+// inflows and spends, and lists the spends newest first. The part of a spend
+// returned to the project fund as change is not spent, and a transaction
+// returning more than it spends is an inflow. proposals maps the txids of
+// spends to the tokens of the Politeia proposals they paid for. The monthly
+// balances end at the current balance, so that they account for any history
+// before the first month, such as pruned history.
+func treasuryFlows(inflows []treasuryInflow, spends []treasurySpendTx,
+	proposals map[string]string, balance int64) *dbtypes.TreasuryFlows {
+	flows := &dbtypes.TreasuryFlows{
+		Monthly: &dbtypes.TreasuryMonthly{
+			Time:      []int64{},
+			Subsidy:   []int64{},
+			OtherIn:   []int64{},
+			Spent:     []int64{},
+			Proposals: []int64{},
+			Balance:   []int64{},
+		},
+		Spends: []*dbtypes.TreasurySpend{},
+	}
+	if len(inflows) == 0 && len(spends) == 0 {
+		return flows
+	}
+
+	monthOf := func(t time.Time) time.Time {
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	var first, last time.Time
+	extend := func(month time.Time) {
+		if first.IsZero() || month.Before(first) {
+			first = month
+		}
+		if month.After(last) {
+			last = month
+		}
+	}
+	for _, in := range inflows {
+		extend(monthOf(in.month))
+	}
+	for _, tx := range spends {
+		extend(monthOf(tx.time))
+	}
+
+	m := flows.Monthly
+	index := make(map[time.Time]int)
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		index[month] = len(m.Time)
+		m.Time = append(m.Time, month.Unix())
+	}
+	n := len(m.Time)
+	m.Subsidy = make([]int64, n)
+	m.OtherIn = make([]int64, n)
+	m.Spent = make([]int64, n)
+	m.Proposals = make([]int64, n)
+	m.Balance = make([]int64, n)
+
+	for _, in := range inflows {
+		i := index[monthOf(in.month)]
+		if in.subsidy {
+			m.Subsidy[i] += in.amount
+			flows.TotalSubsidy += in.amount
+		} else {
+			m.OtherIn[i] += in.amount
+			flows.TotalOtherIn += in.amount
+		}
+	}
+
+	for j := len(spends) - 1; j >= 0; j-- {
+		tx := spends[j]
+		i := index[monthOf(tx.time)]
+		amount := tx.sent - tx.returned
+		if amount < 0 {
+			m.OtherIn[i] -= amount
+			flows.TotalOtherIn -= amount
+			continue
+		}
+		spend := &dbtypes.TreasurySpend{
+			TxID:     tx.txHash,
+			Height:   tx.height,
+			Time:     dbtypes.NewTimeDef(tx.time),
+			Amount:   amount,
+			Proposal: proposals[tx.txHash],
+		}
+		m.Spent[i] += amount
+		if spend.Proposal != "" {
+			m.Proposals[i] += amount
+		}
+		flows.TotalSpent += amount
+		flows.Spends = append(flows.Spends, spend)
+	}
+
+	// The balance before the first month is the current balance less the
+	// net flows of all of the months.
+	var net int64
+	for i := range m.Time {
+		net += m.Subsidy[i] + m.OtherIn[i] - m.Spent[i]
+		m.Balance[i] = net
+	}
+	for i := range m.Balance {
+		m.Balance[i] += balance - net
+	}
+	return flows
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTreasuryFlows(t *testing.T) {
+	month := func(m time.Month, day int) time.Time {
+		return time.Date(2019, m, day, 12, 0, 0, 0, time.UTC)
+	}
+	inflows := []treasuryInflow{
+		{month: month(1, 1), subsidy: true, amount: 100},
+		{month: month(1, 1), amount: 5},
+		// No flows in February.
+		{month: month(3, 1), subsidy: true, amount: 100},
+	}
+	spends := []treasurySpendTx{
+		{txHash: "a", height: 10, time: month(1, 20), sent: 60, returned: 10},
+		{txHash: "b", height: 20, time: month(3, 5), sent: 40, returned: 0},
+		// Returns more than it spends.
+		{txHash: "c", height: 30, time: month(4, 2), sent: 10, returned: 12},
+	}
+	proposals := map[string]string{"b": "token"}
+
+	flows := treasuryFlows(inflows, spends, proposals, 117)
+	m := flows.Monthly
+	wantTime := []int64{
+		time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	if !reflect.DeepEqual(m.Time, wantTime) {
+		t.Fatalf("months %v, want %v", m.Time, wantTime)
+	}
+
+	comp := func(name string, got, want []int64) {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	comp("subsidy", m.Subsidy, []int64{100, 0, 100, 0})
+	comp("other in", m.OtherIn, []int64{5, 0, 0, 2})
+	comp("spent", m.Spent, []int64{50, 0, 40, 0})
+	comp("proposals", m.Proposals, []int64{0, 0, 40, 0})
+	comp("balance", m.Balance, []int64{55, 55, 115, 117})
+
+	if flows.TotalSubsidy != 200 || flows.TotalOtherIn != 7 || flows.TotalSpent != 90 {
+		t.Errorf("unexpected totals %d, %d, %d", flows.TotalSubsidy,
+			flows.TotalOtherIn, flows.TotalSpent)
+	}
+
+	if len(flows.Spends) != 2 || flows.Spends[0].TxID != "b" ||
+		flows.Spends[0].Proposal != "token" || flows.Spends[1].TxID != "a" ||
+		flows.Spends[1].Amount != 50 || flows.Spends[1].Proposal != "" {
+		t.Errorf("unexpected spends %+v", flows.Spends)
+	}
+
+	// History before the first month, e.g. pruned history, is accounted for
+	// by the current balance.
+	seeded := treasuryFlows(inflows, spends, proposals, 1117)
+	comp("seeded balance", seeded.Monthly.Balance, []int64{1055, 1055, 1115, 1117})
+
+	empty := treasuryFlows(nil, nil, nil, 0)
+	if empty.Monthly.Time == nil || len(empty.Monthly.Time) != 0 || empty.Spends == nil {
+		t.Errorf("expected empty flows, got %+v", empty)
+	}
+}
+
+func TestTreasuryCoinbaseInflows(t *testing.T) {
+	month := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The subsidy is 10 per vote.
+	taxAt := func(height int64, voters uint16) int64 {
+		return 10 * int64(voters)
+	}
+	coinbases := []treasuryCoinbases{
+		// Paying exactly the subsidy.
+		{month: month, height: 100, voters: 5, amount: 50, count: 3},
+		// Paying more than the subsidy.
+		{month: month, height: 200, voters: 4, amount: 45, count: 2},
+		// Paying less than the subsidy.
+		{month: month, height: 300, voters: 3, amount: 20, count: 1},
+		// No subsidy.
+		{month: month, height: 1, voters: 0, amount: 7, count: 1},
+	}
+	got := treasuryCoinbaseInflows(coinbases, taxAt)
+	want := []treasuryInflow{
+		{month: month, subsidy: true, amount: 150},
+		{month: month, subsidy: true, amount: 80},
+		{month: month, amount: 10},
+		{month: month, subsidy: true, amount: 20},
+		{month: month, amount: 7},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("treasuryCoinbaseInflows: got %+v, want %+v", got, want)
+	}
+}
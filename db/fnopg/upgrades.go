@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnodata/db/fnopg/internal"
 )
 
@@ -23,7 +24,7 @@ const (
 	// This includes changes such as creating tables, adding/deleting columns,
 	// adding/deleting indexes or any other operations that create, delete, or
 	// modify the definition of any database relation.
-	schemaVersion = 1
+	schemaVersion = 18
 
 	// maintVersion indicates when certain maintenance operations should be
 	// performed for the same compatVersion and schemaVersion. Such operations
@@ -41,6 +42,25 @@ var (
 	}
 
 	legacyDatabaseVersion = &DatabaseVersion{compatVersion, 0, 0}
+
+	// upgradeTables maps the tables introduced by schema upgrades to the schema
+	// version that created them. These tables may be missing from a database
+	// of the current compatibility version that has not yet been upgraded.
+	upgradeTables = map[string]uint32{
+		"address_summary":       2,
+		"mempool_txs":           3,
+		"mempool_fee_snapshots": 3,
+		"block_filters":         4,
+		"nulldata":              5,
+		"redeem_scripts":        6,
+		"ticket_commitments":    7,
+		"block_miners":          8,
+		"utxo_ages":             10,
+		"address_usage":         12,
+		"coin_days_destroyed":   13,
+		"mining_pool_config":    17,
+		"utxo_set_changes":      18,
+	}
 )
 
 // DatabaseVersion models a database version.
@@ -149,7 +169,7 @@ func updateSchemaVersion(db *sql.DB, schema uint32) error {
 	return err
 }
 
-func UpgradeDatabase(db *sql.DB, bg BlockGetter) (bool, error) {
+func UpgradeDatabase(db *sql.DB, bg BlockGetter, params *chaincfg.Params) (bool, error) {
 	initVer, upgradeType, err := versionCheck(db)
 	if err != nil {
 		return false, err
@@ -161,7 +181,7 @@ func UpgradeDatabase(db *sql.DB, bg BlockGetter) (bool, error) {
 	case Upgrade, Maintenance:
 		// Automatic upgrade is supported. Attempt to upgrade from initVer ->
 		// targetDatabaseVersion.
-		return upgradeDatabase(db, *initVer, *targetDatabaseVersion, bg)
+		return upgradeDatabase(db, *initVer, *targetDatabaseVersion, bg, params)
 	case TimeTravel:
 		return false, fmt.Errorf("the current table version is newer than supported: "+
 			"%v > %v", initVer, targetDatabaseVersion)
@@ -172,16 +192,16 @@ func UpgradeDatabase(db *sql.DB, bg BlockGetter) (bool, error) {
 	}
 }
 
-func upgradeDatabase(db *sql.DB, current, target DatabaseVersion, bg BlockGetter) (bool, error) {
+func upgradeDatabase(db *sql.DB, current, target DatabaseVersion, bg BlockGetter, params *chaincfg.Params) (bool, error) {
 	switch current.compat {
 	case 1:
-		return compatVersion1Upgrades(db, current, target, bg)
+		return compatVersion1Upgrades(db, current, target, bg, params)
 	default:
 		return false, fmt.Errorf("unsupported DB compatibility version %d", current.compat)
 	}
 }
 
-func compatVersion1Upgrades(db *sql.DB, current, target DatabaseVersion, _ BlockGetter) (bool, error) {
+func compatVersion1Upgrades(db *sql.DB, current, target DatabaseVersion, bg BlockGetter, params *chaincfg.Params) (bool, error) {
 	upgradeCheck := func() (done bool, err error) {
 		switch current.NeededToReach(&target) {
 		case OK:
@@ -225,7 +245,307 @@ func compatVersion1Upgrades(db *sql.DB, current, target DatabaseVersion, _ Block
 		// --> noop, but would switch on current.maint
 
 		// Perform upgrade to schema v2.
-		// --> schema v2 not defined yet.
+		if err = createUpgradeTables(db, 2); err != nil {
+			return false, err
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 2:
+		// Perform schema v2 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v3.
+		if err = createUpgradeTables(db, 3); err != nil {
+			return false, err
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 3:
+		// Perform schema v3 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v4.
+		if err = createUpgradeTables(db, 4); err != nil {
+			return false, err
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 4:
+		// Perform schema v4 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v5.
+		if err = createUpgradeTables(db, 5); err != nil {
+			return false, err
+		}
+		log.Infof("Indexing nulldata outputs. This may take a while...")
+		if err = populateNullData(db); err != nil {
+			return false, fmt.Errorf("failed to populate nulldata table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 5:
+		// Perform schema v5 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v6.
+		if err = createUpgradeTables(db, 6); err != nil {
+			return false, err
+		}
+		log.Infof("Decoding P2SH redeem scripts. This may take a while...")
+		if err = populateRedeemScripts(db, bg, params); err != nil {
+			return false, fmt.Errorf("failed to populate redeem_scripts table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 6:
+		// Perform schema v6 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v7.
+		if err = createUpgradeTables(db, 7); err != nil {
+			return false, err
+		}
+		log.Infof("Decoding ticket commitments. This may take a while...")
+		if err = populateTicketCommitments(db, params); err != nil {
+			return false, fmt.Errorf("failed to populate ticket_commitments table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 7:
+		// Perform schema v7 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v8.
+		if err = createUpgradeTables(db, 8); err != nil {
+			return false, err
+		}
+		log.Infof("Indexing block coinbase data. This may take a while...")
+		if err = populateBlockMiners(db, bg, params); err != nil {
+			return false, fmt.Errorf("failed to populate block_miners table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 8:
+		// Perform schema v8 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v9.
+		if _, err = addBlocksColumnsForFeeRates(db); err != nil {
+			return false, fmt.Errorf("failed to add fee rate columns to blocks table: %v", err)
+		}
+		log.Infof("Added fee rate columns to the blocks table. The fee rates of " +
+			"existing blocks are computed by rebuilddb2 with the --feerates option.")
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 9:
+		// Perform schema v9 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v10.
+		if err = createUpgradeTables(db, 10); err != nil {
+			return false, err
+		}
+		log.Infof("Created the utxo_ages table. The UTXO age distributions " +
+			"are computed after the next block.")
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 10:
+		// Perform schema v10 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v11.
+		log.Infof("Indexing nulldata payloads. This may take a while...")
+		if err = IndexNullDataTableOnPayloadHex(db); err != nil {
+			return false, fmt.Errorf("failed to index nulldata payloads: %v", err)
+		}
+		if err = IndexNullDataTableOnPayloadTrgm(db); err != nil {
+			return false, fmt.Errorf("failed to index nulldata payload trigrams: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 11:
+		// Perform schema v11 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v12.
+		if err = createUpgradeTables(db, 12); err != nil {
+			return false, err
+		}
+		log.Infof("Computing the address usage of each block. This may take a while...")
+		if err = populateAddressUsage(db); err != nil {
+			return false, fmt.Errorf("failed to populate address_usage table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 12:
+		// Perform schema v12 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v13.
+		if err = createUpgradeTables(db, 13); err != nil {
+			return false, err
+		}
+		log.Infof("Computing the coin-days destroyed by each block. This may take a while...")
+		if err = populateCoinDaysDestroyed(db); err != nil {
+			return false, fmt.Errorf("failed to populate coin_days_destroyed table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 13:
+		// Perform schema v13 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v14.
+		if err = IndexAddressSummaryOnPrunedBefore(db); err != nil {
+			return false, fmt.Errorf("failed to index address_summary table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 14:
+		// Perform schema v14 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v15.
+		mempoolIndexes := []indexingInfo{
+			{Msg: "mempool_txs table on removal reason", IndexFunc: IndexMempoolTxsTableOnRemovalReason},
+			{Msg: "mempool_txs table on removed time", IndexFunc: IndexMempoolTxsTableOnRemovedTime},
+			{Msg: "mempool_fee_snapshots table on time", IndexFunc: IndexMempoolFeeSnapshotsTableOnTime},
+		}
+		for _, idx := range mempoolIndexes {
+			if err = idx.IndexFunc(db); err != nil {
+				return false, fmt.Errorf("failed to index %s: %v", idx.Msg, err)
+			}
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 15:
+		// Perform schema v15 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v16.
+		log.Infof("Computing the missing filter headers of the main chain blocks...")
+		if err = populateBlockFilterHeaders(db, bg); err != nil {
+			return false, fmt.Errorf("failed to compute filter headers: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 16:
+		// Perform schema v16 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v17.
+		if err = createUpgradeTables(db, 17); err != nil {
+			return false, err
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 17:
+		// Perform schema v17 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v18. The UTXO set changes of blocks whose
+		// spent outputs were already pruned are incomplete.
+		if err = createUpgradeTables(db, 18); err != nil {
+			return false, err
+		}
+		log.Infof("Computing the UTXO set changes of each block. This may take a while...")
+		if err = populateUTXOSetChanges(db); err != nil {
+			return false, fmt.Errorf("failed to populate utxo_set_changes table: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+
+		// Continue to upgrades for the next schema version.
+		fallthrough
+	case 18:
+		// Perform schema v18 maintenance.
+		// --> noop, but would switch on current.maint
+
+		// Perform upgrade to schema v19.
+		// --> schema v19 not defined yet.
 
 		// No further upgrades.
 		return upgradeCheck()
@@ -244,3 +564,29 @@ func removeTableComments(db *sql.DB) {
 		}
 	}
 }
+
+// addBlocksColumnsForFeeRates adds the fee rate statistics columns introduced
+// by schema version 9 to the blocks table.
+func addBlocksColumnsForFeeRates(db *sql.DB) (bool, error) {
+	// The new columns and their data types
+	newColumns := []newColumn{
+		{"fee_rates", "INT8[]", ""},
+		{"ticket_fee_rates", "INT8[]", ""},
+	}
+
+	return addNewColumnsIfNotFound(db, "blocks", newColumns)
+}
+
+// createUpgradeTables creates the tables introduced by the given schema
+// version.
+func createUpgradeTables(db *sql.DB, schema uint32) error {
+	for tableName, ver := range upgradeTables {
+		if ver != schema {
+			continue
+		}
+		if err := CreateTable(db, tableName); err != nil {
+			return fmt.Errorf("failed to create %s table: %v", tableName, err)
+		}
+	}
+	return nil
+}
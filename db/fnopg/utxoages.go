@@ -0,0 +1,215 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// secondsPerDay is the length of the UTC days of the UTXO age distributions.
+const secondsPerDay = 86400
+
+// rewindCounter counts the rewinds of the stored UTXO age distributions, so
+// that distributions computed from a main chain that has since been
+// reorganized are discarded.
+type rewindCounter struct {
+	mtx sync.Mutex
+	n   uint64
+}
+
+// count returns the number of rewinds.
+func (c *rewindCounter) count() uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.n
+}
+
+// utxoAgeCell is the number and value of the outputs created on one UTC day and
+// spent on another, with the days counted since the epoch. spent is -1 for
+// unspent outputs.
+type utxoAgeCell struct {
+	created int64
+	spent   int64
+	count   int64
+	value   int64
+}
+
+// utxoAgeBand is the index of the age band of an output that is ageDays old.
+// See dbtypes.UTXOAgeBandDays.
+func utxoAgeBand(ageDays int64) int {
+	for i, maxDays := range dbtypes.UTXOAgeBandDays {
+		if ageDays <= maxDays {
+			return i
+		}
+	}
+	return len(dbtypes.UTXOAgeBandDays)
+}
+
+// utxoAgeDistributions computes the UTXO age distributions at the start of the
+// days firstDay through lastDay, which is the end of the previous UTC day. An
+// output is unspent at the start of a day if it was created on an earlier day
+// and spent on that day or later. Its age is the number of days since the
+// start of the day it was created, so outputs created the previous day are in
+// the first band.
+func utxoAgeDistributions(cells []utxoAgeCell, firstDay, lastDay int64) []*dbtypes.UTXOAges {
+	if lastDay < firstDay {
+		return nil
+	}
+	// The cells enter the unspent set on the first day after they are created,
+	// and leave it the day after they are spent.
+	numDays := lastDay - firstDay + 1
+	enter := make([][]int, numDays)
+	leave := make([][]int, numDays)
+	for i, c := range cells {
+		first, last := c.created+1, lastDay
+		if first < firstDay {
+			first = firstDay
+		}
+		if c.spent >= 0 && c.spent < last {
+			last = c.spent
+		}
+		if first > last {
+			continue
+		}
+		enter[first-firstDay] = append(enter[first-firstDay], i)
+		if last < lastDay {
+			leave[last+1-firstDay] = append(leave[last+1-firstDay], i)
+		}
+	}
+
+	// The unspent count and value by the day the outputs were created.
+	counts := make(map[int64]int64)
+	values := make(map[int64]int64)
+	numBands := len(dbtypes.UTXOAgeBandDays) + 1
+	ages := make([]*dbtypes.UTXOAges, 0, numDays)
+	for d := int64(0); d < numDays; d++ {
+		for _, i := range leave[d] {
+			c := cells[i]
+			counts[c.created] -= c.count
+			values[c.created] -= c.value
+			if counts[c.created] == 0 && values[c.created] == 0 {
+				delete(counts, c.created)
+				delete(values, c.created)
+			}
+		}
+		for _, i := range enter[d] {
+			c := cells[i]
+			counts[c.created] += c.count
+			values[c.created] += c.value
+		}
+
+		day := firstDay + d
+		dist := &dbtypes.UTXOAges{
+			Time:    day * secondsPerDay,
+			Counts:  make([]int64, numBands),
+			Amounts: make([]int64, numBands),
+		}
+		for created, count := range counts {
+			band := utxoAgeBand(day - created)
+			dist.Counts[band] += count
+			dist.Amounts[band] += values[created]
+		}
+		ages = append(ages, dist)
+	}
+	return ages
+}
+
+// UpdateUTXOAges computes and stores the UTXO age distributions at the end of
+// each UTC day that has completed since the last stored distribution. If an
+// update is already in progress, UpdateUTXOAges returns immediately. The
+// distributions are discarded if RewindUTXOAges is called during the update.
+func (pgb *ChainDB) UpdateUTXOAges() error {
+	if !pgb.utxoAgesLock.TryLock() {
+		log.Debugf("UTXO age update already in progress.")
+		return nil
+	}
+	defer pgb.utxoAgesLock.Unlock()
+
+	rewinds := pgb.utxoAgesRewinds.count()
+
+	lastTime, err := retrieveLastUTXOAgesTime(pgb.ctx, pgb.db)
+	if err != nil {
+		return err
+	}
+	firstDay := pgb.chainParams.GenesisBlock.Header.Timestamp.Unix()/secondsPerDay + 1
+	if lastTime.Valid {
+		firstDay = lastTime.Time.Unix()/secondsPerDay + 1
+	}
+
+	// The days before the day of the best block are complete.
+	bestTime, err := pgb.BlockTimeByHeight(pgb.Height())
+	if err != nil {
+		return err
+	}
+	lastDay := bestTime / secondsPerDay
+	if firstDay > lastDay {
+		return nil
+	}
+
+	start := time.Now()
+	// Only the outputs created through lastDay affect the distributions.
+	cells, err := retrieveUTXOAgeCells(pgb.ctx, pgb.db,
+		time.Unix(firstDay*secondsPerDay, 0),
+		time.Unix((lastDay+1)*secondsPerDay, 0))
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+	ages := utxoAgeDistributions(cells, firstDay, lastDay)
+
+	pgb.utxoAgesRewinds.mtx.Lock()
+	defer pgb.utxoAgesRewinds.mtx.Unlock()
+	if pgb.utxoAgesRewinds.n != rewinds {
+		log.Debugf("Discarding the UTXO age distributions computed during a " +
+			"chain reorganization.")
+		return nil
+	}
+	if err = upsertUTXOAges(pgb.db, ages); err != nil {
+		return err
+	}
+	log.Infof("Stored the UTXO age distributions of %d days in %v.", len(ages),
+		time.Since(start))
+	return nil
+}
+
+// RewindUTXOAges deletes the stored UTXO age distributions measured after the
+// main chain block at height, such as the common ancestor of a chain
+// reorganization, so that UpdateUTXOAges computes them again from the new main
+// chain.
+func (pgb *ChainDB) RewindUTXOAges(height int64) error {
+	blockTime, err := pgb.BlockTimeByHeight(height)
+	if err != nil {
+		return err
+	}
+
+	pgb.utxoAgesRewinds.mtx.Lock()
+	defer pgb.utxoAgesRewinds.mtx.Unlock()
+	pgb.utxoAgesRewinds.n++
+	numDeleted, err := deleteUTXOAgesAfter(pgb.db, time.Unix(blockTime, 0))
+	if err != nil {
+		return err
+	}
+	if numDeleted > 0 {
+		log.Infof("Deleted the UTXO age distributions of %d days after block %d.",
+			numDeleted, height)
+	}
+	return nil
+}
+
+// UTXOAges retrieves the daily history of the UTXO age distribution.
+func (pgb *ChainDB) UTXOAges() (*dbtypes.UTXOAgeDistribution, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	days, err := retrieveUTXOAges(ctx, pgb.db)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	return &dbtypes.UTXOAgeDistribution{
+		Bands: dbtypes.UTXOAgeBandLabels,
+		Days:  days,
+	}, nil
+}
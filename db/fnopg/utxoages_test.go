@@ -0,0 +1,65 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUTXOAgeBand(t *testing.T) {
+	tests := []struct {
+		ageDays int64
+		band    int
+	}{
+		{1, 0},
+		{2, 1},
+		{7, 1},
+		{8, 2},
+		{1825, 8},
+		{1826, 9},
+	}
+	for _, test := range tests {
+		if band := utxoAgeBand(test.ageDays); band != test.band {
+			t.Errorf("utxoAgeBand(%d): got %d, want %d", test.ageDays, band, test.band)
+		}
+	}
+}
+
+func TestUTXOAgeDistributions(t *testing.T) {
+	cells := []utxoAgeCell{
+		{created: 10, spent: -1, count: 1, value: 100},
+		{created: 10, spent: 12, count: 2, value: 50},
+		// Spent the day it was created.
+		{created: 12, spent: 12, count: 1, value: 7},
+		{created: 3, spent: -1, count: 1, value: 1000},
+	}
+	ages := utxoAgeDistributions(cells, 11, 13)
+	if len(ages) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(ages))
+	}
+
+	want := []struct {
+		counts, amounts []int64
+	}{
+		{[]int64{3, 0, 1, 0, 0, 0, 0, 0, 0, 0}, []int64{150, 0, 1000, 0, 0, 0, 0, 0, 0, 0}},
+		{[]int64{0, 3, 1, 0, 0, 0, 0, 0, 0, 0}, []int64{0, 150, 1000, 0, 0, 0, 0, 0, 0, 0}},
+		{[]int64{0, 1, 1, 0, 0, 0, 0, 0, 0, 0}, []int64{0, 100, 1000, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for i, w := range want {
+		if ages[i].Time != int64(11+i)*secondsPerDay {
+			t.Errorf("day %d: unexpected time %d", i, ages[i].Time)
+		}
+		if !reflect.DeepEqual(ages[i].Counts, w.counts) {
+			t.Errorf("day %d: got counts %v, want %v", i, ages[i].Counts, w.counts)
+		}
+		if !reflect.DeepEqual(ages[i].Amounts, w.amounts) {
+			t.Errorf("day %d: got amounts %v, want %v", i, ages[i].Amounts, w.amounts)
+		}
+	}
+
+	if ages = utxoAgeDistributions(cells, 14, 13); ages != nil {
+		t.Errorf("expected no days, got %d", len(ages))
+	}
+}
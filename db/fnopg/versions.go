@@ -0,0 +1,100 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/db/fnopg/internal"
+)
+
+// versionCount is the number of blocks or votes with a version in one stake
+// version interval.
+type versionCount struct {
+	interval int64
+	version  int64
+	count    int64
+}
+
+// VersionHistory retrieves the adoption of block, stake, and vote versions in
+// each stake version interval. If intervals is positive, only the most recent
+// intervals, including the current partial interval, are retrieved.
+func (pgb *ChainDB) VersionHistory(intervals int64) (*dbtypes.VersionHistory, error) {
+	intervalSize := pgb.chainParams.StakeVersionInterval
+	lastInterval := pgb.Height() / intervalSize
+	var firstInterval int64
+	if intervals > 0 && lastInterval-intervals+1 > 0 {
+		firstInterval = lastInterval - intervals + 1
+	}
+	minHeight := firstInterval * intervalSize
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	var counts [3][]versionCount
+	stmts := [3]string{internal.SelectBlockVersionCounts,
+		internal.SelectStakeVersionCounts, internal.SelectVoteVersionCounts}
+	for i, stmt := range stmts {
+		var err error
+		counts[i], err = retrieveVersionCounts(ctx, pgb.db, stmt, intervalSize, minHeight)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	history := &dbtypes.VersionHistory{
+		IntervalSize:  intervalSize,
+		Height:        make([]int64, 0, lastInterval-firstInterval+1),
+		BlockVersions: versionShares(counts[0], firstInterval, lastInterval),
+		StakeVersions: versionShares(counts[1], firstInterval, lastInterval),
+		VoteVersions:  versionShares(counts[2], firstInterval, lastInterval),
+	}
+	for i := firstInterval; i <= lastInterval; i++ {
+		history.Height = append(history.Height, i*intervalSize)
+	}
+	return history, nil
+}
+
+// versionShares makes the VersionShares of each version, in ascending order,
+// from the counts of blocks or votes by version in the intervals firstInterval
+// through lastInterval.
+func versionShares(counts []versionCount, firstInterval, lastInterval int64) []*dbtypes.VersionShares {
+	shares := []*dbtypes.VersionShares{}
+	if lastInterval < firstInterval {
+		return shares
+	}
+	n := lastInterval - firstInterval + 1
+	totals := make([]int64, n)
+	byVersion := make(map[int64]*dbtypes.VersionShares)
+	for _, c := range counts {
+		if c.interval < firstInterval || c.interval > lastInterval {
+			continue
+		}
+		vs, found := byVersion[c.version]
+		if !found {
+			vs = &dbtypes.VersionShares{
+				Version: c.version,
+				Count:   make([]int64, n),
+				Percent: make([]float64, n),
+			}
+			byVersion[c.version] = vs
+			shares = append(shares, vs)
+		}
+		vs.Count[c.interval-firstInterval] += c.count
+		totals[c.interval-firstInterval] += c.count
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Version < shares[j].Version
+	})
+	for _, vs := range shares {
+		for i, count := range vs.Count {
+			if totals[i] > 0 {
+				vs.Percent[i] = 100 * float64(count) / float64(totals[i])
+			}
+		}
+	}
+	return shares
+}
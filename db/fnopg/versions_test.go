@@ -0,0 +1,35 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+func TestVersionShares(t *testing.T) {
+	counts := []versionCount{
+		// Interval 1 precedes the first interval and is ignored.
+		{interval: 1, version: 4, count: 10},
+		{interval: 2, version: 4, count: 3},
+		{interval: 2, version: 5, count: 1},
+		// No blocks or votes in interval 3.
+		{interval: 4, version: 5, count: 2},
+	}
+
+	got := versionShares(counts, 2, 4)
+	want := []*dbtypes.VersionShares{
+		{Version: 4, Count: []int64{3, 0, 0}, Percent: []float64{75, 0, 0}},
+		{Version: 5, Count: []int64{1, 0, 2}, Percent: []float64{25, 0, 100}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("versionShares:\n got %+v\nwant %+v", got, want)
+	}
+
+	if empty := versionShares(nil, 2, 4); empty == nil || len(empty) != 0 {
+		t.Errorf("expected no shares, got %+v", empty)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	apitypes "github.com/fonero-project/fnodata/api/types"
+	"github.com/fonero-project/fnodata/db/cache"
+	"github.com/fonero-project/fnodata/db/dbtypes"
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+// maxXpubBranchAddresses is the maximum number of addresses derived from each
+// branch of an extended public key during address discovery.
+const maxXpubBranchAddresses = 10000
+
+// maxXpubCacheEntries is the maximum number of address discovery results kept
+// in the xpub cache before it is cleared.
+const maxXpubCacheEntries = 1000
+
+// xpubCache is the mutex-protected cache of extended public key address
+// discovery results for the best block with hash, keyed by xpub and gap limit.
+type xpubCache struct {
+	mtx     sync.Mutex
+	hash    string
+	results map[string]*xpubResult
+}
+
+// xpubResult is the result of address discovery for an extended public key.
+type xpubResult struct {
+	used, unused []*dbtypes.XpubAddress
+}
+
+// xpubCacheKey is the key of the xpub cache for the given xpub and gap limit.
+func xpubCacheKey(xpub string, gapLimit int) string {
+	return xpub + ":" + strconv.Itoa(gapLimit)
+}
+
+// get retrieves the cached result for the key if it was discovered for the
+// best block with the given hash.
+func (xc *xpubCache) get(key, hash string) (*xpubResult, bool) {
+	xc.mtx.Lock()
+	defer xc.mtx.Unlock()
+	if xc.hash != hash {
+		return nil, false
+	}
+	res, found := xc.results[key]
+	return res, found
+}
+
+// set stores the result for the key, discovered for the best block with the
+// given hash. The cache is cleared when the best block changes or the cache is
+// full.
+func (xc *xpubCache) set(key, hash string, res *xpubResult) {
+	xc.mtx.Lock()
+	defer xc.mtx.Unlock()
+	if xc.hash != hash || len(xc.results) >= maxXpubCacheEntries {
+		xc.hash = hash
+		xc.results = make(map[string]*xpubResult)
+	}
+	xc.results[key] = res
+}
+
+// XpubAddresses discovers the addresses of the external and internal branches
+// of an extended public key. For each branch, addresses are derived in order
+// until gapLimit consecutive addresses have no history. The used addresses,
+// with their balances, and the unused addresses derived during discovery are
+// returned. Results are cached until the best block changes.
+func (pgb *ChainDB) XpubAddresses(xpub string, gapLimit int) (used, unused []*dbtypes.XpubAddress, err error) {
+	key, err := txhelpers.DecodeXpub(xpub, pgb.chainParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheKey := xpubCacheKey(xpub, gapLimit)
+	hash := pgb.BestBlockHashStr()
+	if res, found := pgb.xpubs.get(cacheKey, hash); found {
+		return res.used, res.unused, nil
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	isUsed := func(addrs []string) (map[string]bool, error) {
+		return retrieveUsedAddresses(ctx, pgb.db, addrs)
+	}
+	branches := []uint32{txhelpers.ExternalBranch, txhelpers.InternalBranch}
+	for _, branch := range branches {
+		derive := func(start, count uint32) ([]txhelpers.BranchAddress, error) {
+			return txhelpers.XpubBranchAddresses(key, branch, start, count,
+				pgb.chainParams)
+		}
+		branchUsed, branchUnused, err := discoverXpubBranch(branch, gapLimit,
+			derive, isUsed)
+		if err != nil {
+			return nil, nil, pgb.replaceCancelError(err)
+		}
+		used = append(used, branchUsed...)
+		unused = append(unused, branchUnused...)
+	}
+
+	// Only the used addresses have a balance to look up. Balances not in the
+	// address cache are retrieved within the same timeout as the discovery.
+	bestHash, height := pgb.BestBlock()
+	blockID := cache.NewBlockID(bestHash, height)
+	for _, xa := range used {
+		bal, validHeight := pgb.AddressCache.Balance(xa.Address)
+		if bal == nil || *bestHash != validHeight.Hash {
+			bal, err = RetrieveAddressBalance(ctx, pgb.db, xa.Address)
+			if err != nil {
+				return nil, nil, pgb.replaceCancelError(err)
+			}
+			pgb.AddressCache.StoreBalance(xa.Address, bal, blockID)
+		}
+		xa.Balance = bal
+	}
+
+	pgb.xpubs.set(cacheKey, hash, &xpubResult{used: used, unused: unused})
+	return used, unused, nil
+}
+
+// discoverXpubBranch discovers the addresses of one branch of an extended
+// public key. Addresses are derived in batches of gapLimit with derive, and
+// the used addresses of each batch are identified with isUsed, until gapLimit
+// consecutive addresses are unused or maxXpubBranchAddresses indexes have been
+// derived. The balances of the used addresses are not set.
+func discoverXpubBranch(branch uint32, gapLimit int,
+	derive func(start, count uint32) ([]txhelpers.BranchAddress, error),
+	isUsed func(addrs []string) (map[string]bool, error)) (used, unused []*dbtypes.XpubAddress, err error) {
+	if gapLimit <= 0 {
+		return nil, nil, fmt.Errorf("invalid gap limit %d", gapLimit)
+	}
+
+	batchSize := uint32(gapLimit)
+	var gap int
+	for start := uint32(0); start < maxXpubBranchAddresses; start += batchSize {
+		count := batchSize
+		if start+count > maxXpubBranchAddresses {
+			count = maxXpubBranchAddresses - start
+		}
+		addrs, err := derive(start, count)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			batch = append(batch, addr.Address)
+		}
+		usedAddrs, err := isUsed(batch)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, addr := range addrs {
+			xa := &dbtypes.XpubAddress{
+				Address: addr.Address,
+				Branch:  branch,
+				Index:   addr.Index,
+			}
+			if usedAddrs[addr.Address] {
+				xa.Used = true
+				used = append(used, xa)
+				gap = 0
+				continue
+			}
+
+			unused = append(unused, xa)
+			gap++
+			if gap >= gapLimit {
+				return used, unused, nil
+			}
+		}
+	}
+
+	return used, unused, nil
+}
+
+// AddressesTransactions combines the transaction history of the given
+// addresses, such as the used addresses of an extended public key. For each
+// transaction, the amounts sent from and received by all of the addresses are
+// totaled. A page of N transactions, skipping offset, is returned with the
+// total number of transactions. The transactions are sorted by block time,
+// most recent first.
+func (pgb *ChainDB) AddressesTransactions(addresses []string, N, offset int64) ([]*apitypes.XpubTx, int64, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	total, err := retrieveAddressesMergedTxnsCount(ctx, pgb.db, addresses)
+	if err != nil {
+		return nil, 0, pgb.replaceCancelError(err)
+	}
+	if total == 0 || offset >= total {
+		return []*apitypes.XpubTx{}, total, nil
+	}
+
+	txns, err := retrieveAddressesMergedTxns(ctx, pgb.db, addresses, N, offset)
+	if err != nil {
+		return nil, 0, pgb.replaceCancelError(err)
+	}
+	return txns, total, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package fnopg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fonero-project/fnodata/txhelpers"
+)
+
+func TestDiscoverXpubBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		gapLimit   int
+		used       []uint32
+		skip       []uint32
+		wantUsed   []uint32
+		wantUnused int
+		wantDerive int
+	}{
+		{"none used", 5, nil, nil, nil, 5, 1},
+		{"first used", 5, []uint32{0}, nil, []uint32{0}, 5, 2},
+		{"gap within limit", 3, []uint32{0, 3, 6}, nil, []uint32{0, 3, 6}, 7, 4},
+		{"gap at limit", 3, []uint32{0, 4}, nil, []uint32{0}, 3, 2},
+		{"invalid child skipped", 2, []uint32{0, 3}, []uint32{2}, []uint32{0, 3}, 3, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			used := make(map[string]bool)
+			for _, i := range test.used {
+				used[fmt.Sprintf("addr%d", i)] = true
+			}
+			skip := make(map[uint32]bool)
+			for _, i := range test.skip {
+				skip[i] = true
+			}
+
+			var numDerive, numUsedQueries int
+			derive := func(start, count uint32) ([]txhelpers.BranchAddress, error) {
+				numDerive++
+				var addrs []txhelpers.BranchAddress
+				for i := start; i < start+count; i++ {
+					if skip[i] {
+						continue
+					}
+					addrs = append(addrs, txhelpers.BranchAddress{
+						Address: fmt.Sprintf("addr%d", i),
+						Index:   i,
+					})
+				}
+				return addrs, nil
+			}
+			isUsed := func(addrs []string) (map[string]bool, error) {
+				numUsedQueries++
+				found := make(map[string]bool)
+				for _, addr := range addrs {
+					if used[addr] {
+						found[addr] = true
+					}
+				}
+				return found, nil
+			}
+
+			gotUsed, gotUnused, err := discoverXpubBranch(txhelpers.ExternalBranch,
+				test.gapLimit, derive, isUsed)
+			if err != nil {
+				t.Fatalf("discoverXpubBranch: %v", err)
+			}
+			if numDerive != test.wantDerive || numUsedQueries != test.wantDerive {
+				t.Errorf("got %d derivations and %d used queries, want %d",
+					numDerive, numUsedQueries, test.wantDerive)
+			}
+			if len(gotUsed) != len(test.wantUsed) {
+				t.Fatalf("got %d used addresses, want %d", len(gotUsed), len(test.wantUsed))
+			}
+			for i, xa := range gotUsed {
+				if !xa.Used || xa.Index != test.wantUsed[i] ||
+					xa.Address != fmt.Sprintf("addr%d", test.wantUsed[i]) {
+					t.Errorf("used address %d: got %s at index %d (used %v), want index %d",
+						i, xa.Address, xa.Index, xa.Used, test.wantUsed[i])
+				}
+			}
+			if len(gotUnused) != test.wantUnused {
+				t.Errorf("got %d unused addresses, want %d", len(gotUnused), test.wantUnused)
+			}
+			for _, xa := range gotUnused {
+				if xa.Used || skip[xa.Index] {
+					t.Errorf("unexpected unused address %s at index %d", xa.Address, xa.Index)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscoverXpubBranchError(t *testing.T) {
+	derive := func(start, count uint32) ([]txhelpers.BranchAddress, error) {
+		return []txhelpers.BranchAddress{{Address: "addr0"}}, nil
+	}
+	isUsed := func(addrs []string) (map[string]bool, error) {
+		return nil, fmt.Errorf("query failed")
+	}
+	if _, _, err := discoverXpubBranch(txhelpers.ExternalBranch, 5, derive, isUsed); err == nil {
+		t.Error("expected an error from the used address query")
+	}
+	if _, _, err := discoverXpubBranch(txhelpers.ExternalBranch, 0, derive, isUsed); err == nil {
+		t.Error("expected an error for a zero gap limit")
+	}
+}
+
+func TestXpubCache(t *testing.T) {
+	var xc xpubCache
+	res := &xpubResult{}
+	key := xpubCacheKey("xpub", 20)
+	if _, found := xc.get(key, "a"); found {
+		t.Fatal("unexpected result in empty cache")
+	}
+	xc.set(key, "a", res)
+	if got, found := xc.get(key, "a"); !found || got != res {
+		t.Error("cached result not found")
+	}
+	if _, found := xc.get(xpubCacheKey("xpub", 10), "a"); found {
+		t.Error("unexpected result for a different gap limit")
+	}
+	if _, found := xc.get(key, "b"); found {
+		t.Error("unexpected result for a different best block")
+	}
+	xc.set(xpubCacheKey("other", 20), "b", res)
+	if _, found := xc.get(key, "b"); found {
+		t.Error("cache not cleared for a new best block")
+	}
+}
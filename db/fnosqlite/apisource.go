@@ -1550,18 +1550,33 @@ func (db *WiredDB) GetExplorerTx(txid string) *exptypes.TxInfo {
 		if err != nil {
 			log.Warnf("Failed to determine if tx out is spent for output %d of tx %s", i, txid)
 		}
-		var opReturn string
+		var opReturn, nullDataText string
 		if strings.Contains(vout.ScriptPubKey.Asm, "OP_RETURN") {
 			opReturn = vout.ScriptPubKey.Asm
+			if payload, ok := txhelpers.NullDataPayload(msgTx.TxOut[i].PkScript); ok {
+				nullDataText = txhelpers.NullDataText(payload)
+			}
+		}
+		// The odd numbered outputs of tickets are commitments.
+		var commitment *txhelpers.TicketCommitment
+		if tx.IsTicket() && i%2 == 1 {
+			commitment, err = txhelpers.DecodeTicketCommitment(msgTx.TxOut[i].PkScript, db.params)
+			if err != nil {
+				log.Warnf("Failed to decode commitment output %d of ticket %s: %v", i, txid, err)
+			} else {
+				commitment.Index = uint32(i)
+			}
 		}
 		outputs = append(outputs, exptypes.Vout{
 			Addresses:       vout.ScriptPubKey.Addresses,
 			Amount:          vout.Value,
 			FormattedAmount: humanize.Commaf(vout.Value),
 			OP_RETURN:       opReturn,
+			NullDataText:    nullDataText,
 			Type:            vout.ScriptPubKey.Type,
 			Spent:           txout == nil,
 			Index:           vout.N,
+			Commitment:      commitment,
 		})
 	}
 	tx.Vout = outputs
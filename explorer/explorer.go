@@ -106,8 +106,20 @@ type explorerDataSource interface {
 	PosIntervals(limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error)
 	TimeBasedIntervals(timeGrouping dbtypes.TimeBasedGrouping, limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error)
 	AgendasVotesSummary(agendaID string) (summary *dbtypes.AgendaSummary, err error)
+	AgendaVotesByAddress(agendaID string, limit int) ([]*dbtypes.AgendaAddressVotes, error)
 	BlockTimeByHeight(height int64) (int64, error)
 	LastPiParserSync() time.Time
+	RedeemScript(address string) (*dbtypes.RedeemScript, error)
+	RedeemScriptsByAddress(address string) ([]*dbtypes.RedeemScript, error)
+	StakePool(nameOrAddress string) *dbtypes.StakePool
+	StakePoolStats() ([]*dbtypes.StakePoolStats, error)
+	TreasuryFlows() (*dbtypes.TreasuryFlows, error)
+	MiningPools() []*dbtypes.MiningPool
+	MiningPoolStats(window int64) ([]*dbtypes.MiningPoolStats, error)
+	AddressLabel(address string) *dbtypes.AddressLabel
+	AddressLabels() *dbtypes.AddressLabels
+	SearchAddressLabels(query string) []*dbtypes.AddressLabel
+	MatchAddressLabels(label string) []*dbtypes.AddressLabel
 }
 
 // politeiaBackend implements methods that manage proposals db data.
@@ -117,6 +129,8 @@ type politeiaBackend interface {
 	AllProposals(offset, rowsCount int, filterByVoteStatus ...int) (proposals []*pitypes.ProposalInfo, totalCount int, err error)
 	ProposalByToken(proposalToken string) (*pitypes.ProposalInfo, error)
 	ProposalByRefID(RefID string) (*pitypes.ProposalInfo, error)
+	ProposalArchive(proposalToken string) (*pitypes.ProposalArchive, error)
+	SearchProposals(query string, offset, rowsCount int) ([]*pitypes.ProposalSearchResult, int, error)
 }
 
 // agendaBackend implements methods that manage agendas db data.
@@ -234,6 +248,12 @@ func (exp *explorerUI) SetDBsSyncing(syncing bool) {
 	exp.wsHub.SetDBsSyncing(syncing)
 }
 
+// addressLabel is the addressLabel template function, which gets the label of
+// a known address, or nil if the address is unlabeled.
+func (exp *explorerUI) addressLabel(address string) *dbtypes.AddressLabel {
+	return exp.explorerSource.AddressLabel(address)
+}
+
 func (exp *explorerUI) reloadTemplates() error {
 	return exp.templates.reloadTemplates()
 }
@@ -348,13 +368,16 @@ func New(cfg *ExplorerConfig) *explorerUI {
 	log.Infof("Mean Voting Blocks calculated: %d", exp.pageData.HomeInfo.Params.MeanVotingBlocks)
 
 	commonTemplates := []string{"extras"}
-	exp.templates = newTemplates(cfg.Viewsfolder, commonTemplates, makeTemplateFuncMap(exp.ChainParams))
+	helpers := makeTemplateFuncMap(exp.ChainParams)
+	helpers["addressLabel"] = exp.addressLabel
+	exp.templates = newTemplates(cfg.Viewsfolder, commonTemplates, helpers)
 
 	tmpls := []string{"home", "explorer", "mempool", "block", "tx", "address",
 		"rawtx", "status", "parameters", "agenda", "agendas", "charts",
 		"sidechains", "disapproved", "ticketpool", "nexthome", "statistics",
 		"windows", "timelisting", "addresstable", "proposals", "proposal",
-		"market", "insight_root"}
+		"market", "insight_root", "stakepools", "stakepool", "treasury",
+		"miners", "labels"}
 
 	for _, name := range tmpls {
 		if err := exp.templates.addTemplate(name); err != nil {
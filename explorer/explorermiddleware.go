@@ -26,6 +26,7 @@ const (
 	ctxAddress
 	ctxAgendaId
 	ctxProposalRefID
+	ctxStakePool
 )
 
 const (
@@ -151,6 +152,15 @@ func getAgendaIDCtx(r *http.Request) string {
 	return hash
 }
 
+func getStakePoolCtx(r *http.Request) string {
+	pool, ok := r.Context().Value(ctxStakePool).(string)
+	if !ok {
+		log.Trace("Stake pool not set")
+		return ""
+	}
+	return pool
+}
+
 func getProposalTokenCtx(r *http.Request) string {
 	hash, ok := r.Context().Value(ctxProposalRefID).(string)
 	if !ok {
@@ -207,6 +217,16 @@ func ProposalPathCtx(next http.Handler) http.Handler {
 	})
 }
 
+// StakePoolPathCtx embeds "pool", a stake pool name or address, into the
+// request context
+func StakePoolPathCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		ctx := context.WithValue(r.Context(), ctxStakePool, pool)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // MenuFormParser parses a form submission from the navigation menu.
 func MenuFormParser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
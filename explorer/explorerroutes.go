@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnod/chaincfg/chainhash"
 	"github.com/fonero-project/fnod/fnojson"
@@ -28,7 +29,6 @@ import (
 	"github.com/fonero-project/fnodata/gov/agendas"
 	pitypes "github.com/fonero-project/fnodata/gov/politeia/types"
 	"github.com/fonero-project/fnodata/txhelpers"
-	humanize "github.com/dustin/go-humanize"
 )
 
 var dummyRequest = new(http.Request)
@@ -105,6 +105,9 @@ func (e expStatus) IsSyncing() bool {
 // number of blocks displayed on /nexthome
 const homePageBlocksMaxCount = 30
 
+// number of voting addresses displayed on /agenda
+const agendaVotingAddressesCount = 25
+
 // netName returns the name used when referring to a fonero network.
 func netName(chainParams *chaincfg.Params) string {
 	if chainParams == nil {
@@ -703,6 +706,197 @@ func (exp *explorerUI) Ticketpool(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, str)
 }
 
+// StakePoolsPage is the page handler for the "/stakepools" path.
+func (exp *explorerUI) StakePoolsPage(w http.ResponseWriter, r *http.Request) {
+	stats, err := exp.explorerSource.StakePoolStats()
+	if exp.timeoutErrorPage(w, err, "StakePoolStats") {
+		return
+	}
+	if err != nil {
+		log.Errorf("Unable to get stake pool stats: %v", err)
+		exp.StatusPage(w, defaultErrorCode,
+			"failed to retrieve stake pool stats", "", ExpStatusError)
+		return
+	}
+
+	str, err := exp.templates.execTemplateToString("stakepools", struct {
+		*CommonPageData
+		Data []*dbtypes.StakePoolStats
+	}{
+		CommonPageData: exp.commonData(r),
+		Data:           stats,
+	})
+
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
+// MinersPage is the page handler for the "/miners" path.
+func (exp *explorerUI) MinersPage(w http.ResponseWriter, r *http.Request) {
+	// The shares of the mining pools over the last two weeks of blocks.
+	window := int64(14 * 24 * time.Hour / exp.ChainParams.TargetTimePerBlock)
+	stats, err := exp.explorerSource.MiningPoolStats(window)
+	if exp.timeoutErrorPage(w, err, "MiningPoolStats") {
+		return
+	}
+	if err != nil {
+		log.Errorf("Unable to get mining pool stats: %v", err)
+		exp.StatusPage(w, defaultErrorCode,
+			"failed to retrieve mining pool stats", "", ExpStatusError)
+		return
+	}
+
+	str, err := exp.templates.execTemplateToString("miners", struct {
+		*CommonPageData
+		Data   []*dbtypes.MiningPoolStats
+		Pools  []*dbtypes.MiningPool
+		Window int64
+	}{
+		CommonPageData: exp.commonData(r),
+		Data:           stats,
+		Pools:          exp.explorerSource.MiningPools(),
+		Window:         window,
+	})
+
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
+// LabelsPage is the page handler for the "/labels" path. The labels are
+// filtered by the optional search query.
+func (exp *explorerUI) LabelsPage(w http.ResponseWriter, r *http.Request) {
+	labels := exp.explorerSource.AddressLabels()
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+	data := labels.Labels
+	if search != "" {
+		data = exp.explorerSource.SearchAddressLabels(search)
+	}
+
+	str, err := exp.templates.execTemplateToString("labels", struct {
+		*CommonPageData
+		Data    []*dbtypes.AddressLabel
+		Version int64
+		Search  string
+	}{
+		CommonPageData: exp.commonData(r),
+		Data:           data,
+		Version:        labels.Version,
+		Search:         search,
+	})
+
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
+// TreasuryPage is the page handler for the "/treasury" path.
+func (exp *explorerUI) TreasuryPage(w http.ResponseWriter, r *http.Request) {
+	flows, err := exp.explorerSource.TreasuryFlows()
+	if exp.timeoutErrorPage(w, err, "TreasuryFlows") {
+		return
+	}
+	if err != nil {
+		log.Errorf("Unable to get treasury flows: %v", err)
+		exp.StatusPage(w, defaultErrorCode,
+			"failed to retrieve the project fund flows", "", ExpStatusError)
+		return
+	}
+
+	// Look up the proposals paid by the spends. Missing proposals are only
+	// shown by token.
+	proposals := make(map[string]*pitypes.ProposalInfo)
+	for _, spend := range flows.Spends {
+		if spend.Proposal == "" || proposals[spend.Proposal] != nil {
+			continue
+		}
+		proposal, err := exp.proposalsSource.ProposalByToken(spend.Proposal)
+		if err == nil {
+			proposals[spend.Proposal] = proposal
+		}
+	}
+
+	str, err := exp.templates.execTemplateToString("treasury", struct {
+		*CommonPageData
+		Data      *dbtypes.TreasuryFlows
+		Proposals map[string]*pitypes.ProposalInfo
+	}{
+		CommonPageData: exp.commonData(r),
+		Data:           flows,
+		Proposals:      proposals,
+	})
+
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
+// StakePoolPage is the page handler for the "/stakepool/{pool}" path.
+func (exp *explorerUI) StakePoolPage(w http.ResponseWriter, r *http.Request) {
+	pool := exp.explorerSource.StakePool(getStakePoolCtx(r))
+	if pool == nil {
+		exp.StatusPage(w, defaultErrorCode, "the stake pool given seems to not exist",
+			"", ExpStatusNotFound)
+		return
+	}
+
+	stats, err := exp.explorerSource.StakePoolStats()
+	if exp.timeoutErrorPage(w, err, "StakePoolStats") {
+		return
+	}
+	if err != nil {
+		log.Errorf("Unable to get stake pool stats: %v", err)
+		exp.StatusPage(w, defaultErrorCode,
+			"failed to retrieve stake pool stats", "", ExpStatusError)
+		return
+	}
+	poolStats := &dbtypes.StakePoolStats{StakePool: pool}
+	for _, ps := range stats {
+		if ps.Name == pool.Name {
+			poolStats = ps
+			break
+		}
+	}
+
+	str, err := exp.templates.execTemplateToString("stakepool", struct {
+		*CommonPageData
+		Data *dbtypes.StakePoolStats
+	}{
+		CommonPageData: exp.commonData(r),
+		Data:           poolStats,
+	})
+
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
 // TxPage is the page handler for the "/tx" path.
 func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 	// attempt to get tx hash string from URL path
@@ -789,11 +983,14 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 
 		// Convert to explorer.Vout, getting spending information from DB.
 		for iv := range vouts {
-			// Check pkScript for OP_RETURN
-			var opReturn string
+			// Check pkScript for OP_RETURN, and decode any text it carries.
+			var opReturn, nullDataText string
 			asm, _ := txscript.DisasmString(vouts[iv].ScriptPubKey)
 			if strings.Contains(asm, "OP_RETURN") {
 				opReturn = asm
+				if payload, ok := txhelpers.NullDataPayload(vouts[iv].ScriptPubKey); ok {
+					nullDataText = txhelpers.NullDataText(payload)
+				}
 			}
 			// Determine if the outpoint is spent
 			spendingTx, _, _, err := exp.explorerSource.SpendingTransaction(hash, vouts[iv].TxIndex)
@@ -804,15 +1001,28 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 				log.Warnf("SpendingTransaction failed for outpoint %s:%d: %v",
 					hash, vouts[iv].TxIndex, err)
 			}
+			// The odd numbered outputs of tickets are commitments.
+			var commitment *txhelpers.TicketCommitment
+			if tx.IsTicket() && vouts[iv].TxIndex%2 == 1 {
+				commitment, err = txhelpers.DecodeTicketCommitment(vouts[iv].ScriptPubKey, exp.ChainParams)
+				if err != nil {
+					log.Warnf("Failed to decode commitment output %d of ticket %s: %v",
+						vouts[iv].TxIndex, hash, err)
+				} else {
+					commitment.Index = vouts[iv].TxIndex
+				}
+			}
 			amount := fnoutil.Amount(int64(vouts[iv].Value)).ToCoin()
 			tx.Vout = append(tx.Vout, types.Vout{
 				Addresses:       vouts[iv].ScriptPubKeyData.Addresses,
 				Amount:          amount,
 				FormattedAmount: humanize.Commaf(amount),
 				Type:            txhelpers.TxTypeToString(int(vouts[iv].TxType)),
-				Spent:           spendingTx != "",
+				Spent:           spendingTx != "" || vouts[iv].Pruned,
 				OP_RETURN:       opReturn,
+				NullDataText:    nullDataText,
 				Index:           vouts[iv].TxIndex,
+				Commitment:      commitment,
 			})
 		}
 
@@ -1132,6 +1342,17 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 			vin.DisplayText = vin.Txid + ":" + voutStr
 			vin.TextIsHash = true
 			vin.Link = "/tx/" + vin.Txid + "/out/" + voutStr
+
+			// Decode the redeem script of an input spending a P2SH output.
+			if vin.ScriptSig != nil && len(vin.Addresses) == 1 {
+				sigScript, err := hex.DecodeString(vin.ScriptSig.Hex)
+				if err == nil {
+					rs, err := txhelpers.SigScriptRedeemScript(sigScript, exp.ChainParams)
+					if err == nil && rs.Address == vin.Addresses[0] {
+						vin.RedeemScript = rs
+					}
+				}
+			}
 		}
 	}
 
@@ -1189,6 +1410,8 @@ func (exp *explorerUI) AddressPage(w http.ResponseWriter, r *http.Request) {
 		Data         *dbtypes.AddressInfo
 		CRLFDownload bool
 		FiatBalance  *exchanges.Conversion
+		RedeemScript *dbtypes.RedeemScript
+		P2SHScripts  []*dbtypes.RedeemScript
 	}
 
 	// Grab the URL query parameters
@@ -1263,6 +1486,21 @@ func (exp *explorerUI) AddressPage(w http.ResponseWriter, r *http.Request) {
 	addrData.IsDummyAddress = isZeroAddress // may be redundant
 	addrData.Path = r.URL.Path
 
+	// Get the redeem script of a P2SH address that has been spent from, or the
+	// P2SH scripts that include a P2PKH address, such as multisig scripts.
+	var redeemScript *dbtypes.RedeemScript
+	var p2shScripts []*dbtypes.RedeemScript
+	if !isZeroAddress {
+		if addrType == txhelpers.AddressTypeP2SH {
+			redeemScript, err = exp.explorerSource.RedeemScript(address)
+		} else {
+			p2shScripts, err = exp.explorerSource.RedeemScriptsByAddress(address)
+		}
+		if err != nil && err != sql.ErrNoRows {
+			log.Warnf("Failed to retrieve redeem scripts for %s: %v", address, err)
+		}
+	}
+
 	// If exchange monitoring is active, prepare a fiat balance conversion
 	conversion := exp.xcBot.Conversion(fnoutil.Amount(addrData.Balance.TotalUnspent).ToCoin())
 
@@ -1276,6 +1514,8 @@ func (exp *explorerUI) AddressPage(w http.ResponseWriter, r *http.Request) {
 		Data:           addrData,
 		CRLFDownload:   UseCRLF,
 		FiatBalance:    conversion,
+		RedeemScript:   redeemScript,
+		P2SHScripts:    p2shScripts,
 	}
 	str, err := exp.templates.execTemplateToString("address", pageData)
 	if err != nil {
@@ -1503,10 +1743,37 @@ func (exp *explorerUI) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Remaining possibilities are hashes, so verify the string is a hash.
+	// Remaining possibilities are hashes, and the labels of known addresses and
+	// the text of proposals. Match the labels exactly, then search the
+	// proposals, if the string is not a hash. Partial label matches are listed
+	// on the labels page.
 	if _, err = chainhash.NewHashFromStr(searchStr); err != nil {
+		labels := exp.explorerSource.MatchAddressLabels(searchStr)
+		switch {
+		case len(labels) == 1:
+			http.Redirect(w, r, "/address/"+labels[0].Address, http.StatusFound)
+			return
+		case len(labels) > 1:
+			http.Redirect(w, r, "/labels?search="+url.QueryEscape(searchStr),
+				http.StatusFound)
+			return
+		}
+
+		results, count, err := exp.proposalsSource.SearchProposals(searchStr, 0, 1)
+		if err != nil {
+			log.Errorf("Searching proposals failed: %v", err)
+		}
+		switch {
+		case count == 1:
+			http.Redirect(w, r, "/proposal/"+results[0].RefID, http.StatusFound)
+			return
+		case count > 1:
+			http.Redirect(w, r, "/proposals?search="+url.QueryEscape(searchStr),
+				http.StatusFound)
+			return
+		}
 		exp.StatusPage(w, "search failed",
-			"Search string is not a valid hash or address: "+searchStr,
+			"Search string is not a valid hash or address, and matches no address labels or proposals: "+searchStr,
 			"", ExpStatusNotFound)
 		return
 	}
@@ -1685,6 +1952,11 @@ func (exp *explorerUI) AgendaPage(w http.ResponseWriter, r *http.Request) {
 		totalVotes += agendaInfo.Choices[index].Count
 	}
 
+	addrVotes, err := exp.explorerSource.AgendaVotesByAddress(agendaId, agendaVotingAddressesCount)
+	if err != nil {
+		log.Errorf("fetching agenda votes by voting address failed: %v", err)
+	}
+
 	ruleChangeI := exp.ChainParams.RuleChangeActivationInterval
 	qVotes := uint32(float64(ruleChangeI) * agendaInfo.QuorumProgress)
 
@@ -1713,6 +1985,8 @@ func (exp *explorerUI) AgendaPage(w http.ResponseWriter, r *http.Request) {
 		BlocksLeft    int64
 		TimeRemaining string
 		TotalVotes    uint32
+		AddressVotes  []*dbtypes.AgendaAddressVotes
+		Prediction    *dbtypes.AgendaPrediction
 	}{
 		CommonPageData: exp.commonData(r),
 		Ai:             agendaInfo,
@@ -1723,6 +1997,8 @@ func (exp *explorerUI) AgendaPage(w http.ResponseWriter, r *http.Request) {
 		BlocksLeft:     blocksLeft,
 		TimeRemaining:  timeLeft,
 		TotalVotes:     totalVotes,
+		AddressVotes:   addrVotes,
+		Prediction:     exp.voteTracker.Prediction(agendaId),
 	})
 
 	if err != nil {
@@ -1775,6 +2051,12 @@ func (exp *explorerUI) ProposalPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The archived proposal text is shown even if Politeia is unavailable.
+	archive, err := exp.proposalsSource.ProposalArchive(proposalInfo.TokenVal)
+	if err != nil {
+		log.Errorf("ProposalArchive failed: %v", err)
+	}
+
 	// Proposals whose voting hasn't commenced do not have an end height assigned yet.
 	// Ignore the error returned since the endheight variable will be a zero value.
 	endHeight, _ := strconv.ParseInt(proposalInfo.ProposalVotes.Endheight, 10, 64)
@@ -1794,11 +2076,13 @@ func (exp *explorerUI) ProposalPage(w http.ResponseWriter, r *http.Request) {
 	str, err := exp.templates.execTemplateToString("proposal", struct {
 		*CommonPageData
 		Data          *pitypes.ProposalInfo
+		Archive       *pitypes.ProposalArchive
 		PoliteiaURL   string
 		TimeRemaining string
 	}{
 		CommonPageData: exp.commonData(r),
 		Data:           proposalInfo,
+		Archive:        archive,
 		PoliteiaURL:    exp.politeiaAPIURL,
 		TimeRemaining:  timeLeft,
 	})
@@ -1827,11 +2111,24 @@ func (exp *explorerUI) ProposalsPage(w http.ResponseWriter, r *http.Request) {
 
 	var count int
 	var proposals []*pitypes.ProposalInfo
+	var snippets map[string]string
 
 	// Check if filter by votes status query parameter was passed. Ignore the
-	// error message if it occurs.
+	// error message if it occurs. A full-text search takes precedence over the
+	// filter.
 	filterBy, _ := strconv.Atoi(r.URL.Query().Get("byvotestatus"))
-	if filterBy > 0 {
+	searchStr := strings.TrimSpace(r.URL.Query().Get("search"))
+	if searchStr != "" {
+		var results []*pitypes.ProposalSearchResult
+		results, count, err = exp.proposalsSource.SearchProposals(searchStr,
+			int(offset), int(rowsCount))
+		snippets = make(map[string]string, len(results))
+		for _, result := range results {
+			proposals = append(proposals, result.ProposalInfo)
+			snippets[result.TokenVal] = result.Snippet
+		}
+		filterBy = 0
+	} else if filterBy > 0 {
 		proposals, count, err = exp.proposalsSource.AllProposals(int(offset),
 			int(rowsCount), filterBy)
 	} else {
@@ -1848,8 +2145,10 @@ func (exp *explorerUI) ProposalsPage(w http.ResponseWriter, r *http.Request) {
 	str, err := exp.templates.execTemplateToString("proposals", struct {
 		*CommonPageData
 		Proposals     []*pitypes.ProposalInfo
+		Snippets      map[string]string
 		VotesStatus   map[pitypes.VoteStatusType]string
 		VStatusFilter int
+		SearchQuery   string
 		Offset        int64
 		Limit         int64
 		TotalCount    int64
@@ -1859,7 +2158,9 @@ func (exp *explorerUI) ProposalsPage(w http.ResponseWriter, r *http.Request) {
 	}{
 		CommonPageData: exp.commonData(r),
 		Proposals:      proposals,
+		Snippets:       snippets,
 		VotesStatus:    pitypes.VotesStatuses(),
+		SearchQuery:    searchStr,
 		Offset:         int64(offset),
 		Limit:          int64(rowsCount),
 		VStatusFilter:  filterBy,
@@ -332,6 +332,7 @@ type Vin struct {
 	DisplayText     string
 	TextIsHash      bool
 	Link            string
+	RedeemScript    *txhelpers.RedeemScript
 }
 
 // Vout models basic data about a tx output for display
@@ -342,7 +343,9 @@ type Vout struct {
 	Type            string
 	Spent           bool
 	OP_RETURN       string
+	NullDataText    string
 	Index           uint32
+	Commitment      *txhelpers.TicketCommitment
 }
 
 // TrimmedBlockInfo models data needed to display block info on the new home page
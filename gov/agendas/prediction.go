@@ -0,0 +1,69 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package agendas
+
+import (
+	"math"
+
+	"github.com/fonero-project/fnodata/db/dbtypes"
+)
+
+// predictAgenda projects the outcome of the vote on an agenda at the end of the
+// current rule change interval, in which rciMined blocks have been mined and
+// remainingBlocks remain. If the agenda expires before the end of the
+// interval, it fails regardless of the votes. When no votes have yet been
+// cast for or against the agenda, the expected remaining votes abstain.
+func predictAgenda(agenda *AgendaSummary, rciMined, remainingBlocks uint32,
+	ticketsPerBlock uint16, expires bool) *dbtypes.AgendaPrediction {
+	scenario := func(aye, nay, abstain uint32) dbtypes.AgendaScenario {
+		s := dbtypes.AgendaScenario{
+			Aye:     aye,
+			Nay:     nay,
+			Abstain: abstain,
+			Outcome: statusStarted,
+		}
+		nonAbstain := aye + nay
+		if nonAbstain > 0 {
+			s.Approval = float32(aye) / float32(nonAbstain)
+		}
+		s.QuorumAchieved = nonAbstain >= agenda.Quorum
+		switch {
+		case expires:
+			s.Outcome = statusFailed
+		case !s.QuorumAchieved:
+		case s.Approval >= agenda.PassThreshold:
+			s.Outcome = statusLocked
+		case float32(nay)/float32(nonAbstain) >= agenda.PassThreshold:
+			s.Outcome = statusFailed
+		}
+		return s
+	}
+
+	prediction := &dbtypes.AgendaPrediction{
+		RemainingBlocks: remainingBlocks,
+	}
+	remainingVotes := remainingBlocks * uint32(ticketsPerBlock)
+	cast := agenda.Aye + agenda.Nay + agenda.Abstain
+	if possible := rciMined * uint32(ticketsPerBlock); possible > 0 {
+		prediction.Participation = float32(cast) / float32(possible)
+	}
+
+	expectedVotes := float64(prediction.Participation) * float64(remainingVotes)
+	var expectedAye, expectedNay, expectedAbstain float64
+	if nonAbstain := agenda.Aye + agenda.Nay; nonAbstain > 0 {
+		expectedAbstain = expectedVotes * float64(agenda.Abstain) / float64(cast)
+		expectedAye = (expectedVotes - expectedAbstain) * float64(agenda.Aye) / float64(nonAbstain)
+		expectedNay = expectedVotes - expectedAbstain - expectedAye
+	} else {
+		expectedAbstain = expectedVotes
+	}
+
+	prediction.Best = scenario(agenda.Aye+remainingVotes, agenda.Nay, agenda.Abstain)
+	prediction.Expected = scenario(agenda.Aye+uint32(math.Round(expectedAye)),
+		agenda.Nay+uint32(math.Round(expectedNay)),
+		agenda.Abstain+uint32(math.Round(expectedAbstain)))
+	prediction.Worst = scenario(agenda.Aye, agenda.Nay+remainingVotes, agenda.Abstain)
+	prediction.Certain = prediction.Best.Outcome == prediction.Worst.Outcome
+	return prediction
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package agendas
+
+import (
+	"testing"
+)
+
+func TestPredictAgenda(t *testing.T) {
+	agenda := func(aye, nay, abstain uint32) *AgendaSummary {
+		return &AgendaSummary{
+			Aye:           aye,
+			Nay:           nay,
+			Abstain:       abstain,
+			Quorum:        4032,
+			PassThreshold: 0.75,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		agenda           *AgendaSummary
+		mined, remaining uint32
+		expires          bool
+		best, expected   string
+		worst            string
+		expectedAye      uint32
+		certain          bool
+	}{
+		{
+			name:        "winning",
+			agenda:      agenda(15000, 1000, 1000),
+			mined:       4032,
+			remaining:   4032,
+			best:        statusLocked,
+			expected:    statusLocked,
+			worst:       statusStarted,
+			expectedAye: 30000,
+		},
+		{
+			name:        "expiring",
+			agenda:      agenda(15000, 1000, 1000),
+			mined:       4032,
+			remaining:   4032,
+			expires:     true,
+			best:        statusFailed,
+			expected:    statusFailed,
+			worst:       statusFailed,
+			expectedAye: 30000,
+			certain:     true,
+		},
+		{
+			name:        "locked",
+			agenda:      agenda(31000, 0, 0),
+			mined:       7056,
+			remaining:   1008,
+			best:        statusLocked,
+			expected:    statusLocked,
+			worst:       statusLocked,
+			expectedAye: 31000 + 4429,
+			certain:     true,
+		},
+		{
+			name:        "no quorum",
+			agenda:      agenda(0, 0, 100),
+			mined:       4032,
+			remaining:   4032,
+			best:        statusLocked,
+			expected:    statusStarted,
+			worst:       statusFailed,
+			expectedAye: 0,
+		},
+	}
+
+	for _, test := range tests {
+		p := predictAgenda(test.agenda, test.mined, test.remaining, 5, test.expires)
+		if p.Best.Outcome != test.best || p.Expected.Outcome != test.expected ||
+			p.Worst.Outcome != test.worst {
+			t.Errorf("%s: outcomes %s, %s, %s, expected %s, %s, %s", test.name,
+				p.Best.Outcome, p.Expected.Outcome, p.Worst.Outcome,
+				test.best, test.expected, test.worst)
+		}
+		if p.Expected.Aye != test.expectedAye {
+			t.Errorf("%s: expected %d ayes, got %d", test.name, test.expectedAye,
+				p.Expected.Aye)
+		}
+		if p.Certain != test.certain {
+			t.Errorf("%s: certain = %v", test.name, p.Certain)
+		}
+		if p.RemainingBlocks != test.remaining {
+			t.Errorf("%s: %d remaining blocks", test.name, p.RemainingBlocks)
+		}
+	}
+}
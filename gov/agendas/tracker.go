@@ -6,9 +6,11 @@ package agendas
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/fonero-project/fnod/chaincfg"
 	"github.com/fonero-project/fnod/fnojson"
+	"github.com/fonero-project/fnodata/db/dbtypes"
 )
 
 const (
@@ -57,6 +59,8 @@ type AgendaSummary struct {
 	IsLocked        bool    `json:"is_locked"`
 	IsFailed        bool    `json:"is_failed"`
 	IsActive        bool    `json:"is_active"`
+	// Prediction is the projected outcome of an agenda being voted on.
+	Prediction *dbtypes.AgendaPrediction `json:"prediction,omitempty"`
 }
 
 // VoteSummary summarizes the current state of consensus voting. VoteSummary is
@@ -418,6 +422,15 @@ func (tracker *VoteTracker) newVoteSummary() *VoteSummary {
 			agendaSummary.VotingTriggered = true
 			summary.VotingTriggered = true
 		}
+		if agendaSummary.IsVoting {
+			// The agenda expires if its expiration time passes before the end
+			// of the rule change interval.
+			remainingBlocks := summary.RCIBlocks - summary.RCIMined
+			rciEnd := time.Now().Unix() + int64(remainingBlocks)*tracker.blockTime
+			agendaSummary.Prediction = predictAgenda(&agendaSummary, summary.RCIMined,
+				remainingBlocks, tracker.params.TicketsPerBlock,
+				int64(agenda.ExpireTime) <= rciEnd)
+		}
 
 		summary.Agendas[idx] = agendaSummary
 	}
@@ -472,6 +485,21 @@ func (tracker *VoteTracker) Summary() *VoteSummary {
 	return tracker.summary
 }
 
+// Prediction is the projected outcome of the vote on the agenda with the given
+// ID, or nil if the agenda is not being voted on.
+func (tracker *VoteTracker) Prediction(agendaID string) *dbtypes.AgendaPrediction {
+	summary := tracker.Summary()
+	if summary == nil {
+		return nil
+	}
+	for idx := range summary.Agendas {
+		if summary.Agendas[idx].ID == agendaID {
+			return summary.Agendas[idx].Prediction
+		}
+	}
+	return nil
+}
+
 // for testing
 func spoof(summary *VoteSummary) {
 	log.Infof("Spoofing vote data for testing. Don't forget to remove this call.")
@@ -128,3 +128,41 @@ func RetrieveProposalByToken(client *http.Client, APIRootPath, token string) (*p
 
 	return &proposal, nil
 }
+
+// RetrieveProposalVoteResults returns the votes cast by the individual tickets
+// on the proposal identified by the token hash provided. Data returned is
+// queried from Politeia API.
+func RetrieveProposalVoteResults(client *http.Client, APIRootPath, token string) (*pitypes.VoteResults, error) {
+	voteResultsRoute := APIRootPath + DropURLRegex(piapi.RouteVoteResults, token)
+	data, err := HandleGetRequests(client, voteResultsRoute)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving %s proposal vote results failed: %v", token, err)
+	}
+
+	var results pitypes.VoteResults
+	err = json.Unmarshal(data, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+// RetrieveProposalFiles returns the version and the files, including the
+// markdown index file, of the proposal identified by the token hash provided.
+// Data returned is queried from Politeia API.
+func RetrieveProposalFiles(client *http.Client, APIRootPath, token string) (*pitypes.ProposalFiles, error) {
+	proposalRoute := APIRootPath + DropURLRegex(piapi.RouteProposalDetails, token)
+	data, err := HandleGetRequests(client, proposalRoute)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving %s proposal files failed: %v", token, err)
+	}
+
+	var files pitypes.ProposalFiles
+	err = json.Unmarshal(data, &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &files, nil
+}
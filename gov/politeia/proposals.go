@@ -6,6 +6,7 @@
 package politeia
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
@@ -35,12 +36,24 @@ var (
 // dbinfo defines the property that holds the db version.
 const dbinfo = "_proposals.db_"
 
-// ProposalDB defines the common data needed to query the proposals db.
+// ticketVotesSync defines the bucket that holds the proposal's total votes
+// count when its ticket votes were last synced.
+const ticketVotesSync = "_ticket_votes_sync_"
+
+// proposalIndexFile is the name of the proposal file holding the markdown
+// text of the proposal.
+const proposalIndexFile = "index.md"
+
+// ProposalDB defines the common data needed to query the proposals db. mtx
+// guards writes to the db, lastSync and index, and is never held while
+// fetching from Politeia. syncMtx serializes the syncs with Politeia.
 type ProposalDB struct {
 	mtx        sync.RWMutex
+	syncMtx    sync.Mutex
 	dbP        *storm.DB
 	client     *http.Client
 	lastSync   int64
+	index      *searchIndex
 	APIURLpath string
 }
 
@@ -110,6 +123,12 @@ func NewProposalsDB(politeiaURL, dbPath string) (*ProposalDB, error) {
 		APIURLpath: versionedPath,
 	}
 
+	// Index the proposals archived by previous syncs so that they can be
+	// searched before the first sync with Politeia completes.
+	if err = proposalDB.indexProposals(); err != nil {
+		return nil, fmt.Errorf("indexing proposals failed: %v", err)
+	}
+
 	return proposalDB, nil
 }
 
@@ -180,6 +199,8 @@ func (db *ProposalDB) saveProposals(URLParams string) (int, error) {
 	}
 
 	// Save all the proposals
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
 	for i, val := range publicProposals.Data {
 		var err error
 		if val.RefID, err = generateCustomID(val.Name); err != nil {
@@ -286,6 +307,32 @@ func (db *ProposalDB) proposal(searchBy, searchTerm string) (*pitypes.ProposalIn
 	return &pInfo, nil
 }
 
+// ProposalTicketVotes returns the votes cast by tickets on the proposal
+// identified by the provided token. If ticket is not empty, only the vote cast
+// by that ticket is returned.
+func (db *ProposalDB) ProposalTicketVotes(proposalToken, ticket string) ([]*pitypes.TicketVote, error) {
+	if db == nil || db.dbP == nil {
+		return nil, errDef
+	}
+
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	matchers := []q.Matcher{q.Eq("Token", proposalToken)}
+	if ticket != "" {
+		matchers = append(matchers, q.Eq("Ticket", ticket))
+	}
+
+	votes := make([]*pitypes.TicketVote, 0)
+	err := db.dbP.Select(matchers...).Find(&votes)
+	if err != nil && err != storm.ErrNotFound {
+		log.Errorf("Failed to fetch ticket votes from Proposals DB: %v", err)
+		return nil, err
+	}
+
+	return votes, nil
+}
+
 // LastProposalsSync returns the last time a sync to update the proposals was run
 // but not necessarily the last time updates were synced in proposals.db.
 func (db *ProposalDB) LastProposalsSync() int64 {
@@ -302,12 +349,20 @@ func (db *ProposalDB) CheckProposalsUpdates() error {
 		return errDef
 	}
 
-	db.mtx.Lock()
+	// Politeia is queried without holding mtx so that the proposals can be
+	// read during the sync. Each update only locks mtx to write to the db.
+	db.syncMtx.Lock()
 	defer func() {
-		// Update the lastSync before the function exits.
+		// Update the lastSync and the search index, which includes any updates
+		// saved before a failure, before the function exits.
+		if err := db.indexProposals(); err != nil {
+			log.Errorf("indexing proposals failed: %v", err)
+		}
 
+		db.mtx.Lock()
 		db.lastSync = time.Now().UTC().Unix()
 		db.mtx.Unlock()
+		db.syncMtx.Unlock()
 	}()
 
 	// Retrieve and update all current proposals whose vote statuses is either
@@ -339,6 +394,24 @@ func (db *ProposalDB) CheckProposalsUpdates() error {
 
 	log.Infof("%d proposal records (politeia proposals-storm) were updated", numRecords)
 
+	// Archive the files of the new proposals and of the proposals whose
+	// version has changed.
+	numArchived, err := db.updateArchive()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%d proposal archives (politeia proposals-storm) were updated", numArchived)
+
+	// Retrieve and update the votes cast by the individual tickets on the
+	// proposals whose voting has started or finished.
+	numVotes, err := db.updateTicketVotes()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%d proposal ticket votes (politeia proposals-storm) were updated", numVotes)
+
 	return nil
 }
 
@@ -403,7 +476,9 @@ func (db *ProposalDB) updateInProgressProposals() (int, error) {
 			continue
 		}
 
+		db.mtx.Lock()
 		err = db.dbP.Update(proposal.Data)
+		db.mtx.Unlock()
 		if err != nil {
 			return 0, fmt.Errorf("Update for %s failed with error: %v ", val.TokenVal, err)
 		}
@@ -412,3 +487,238 @@ func (db *ProposalDB) updateInProgressProposals() (int, error) {
 	}
 	return count, nil
 }
+
+// updateTicketVotes fetches the votes cast by the individual tickets on the
+// proposals whose voting has started or finished, and saves them to the db.
+// A proposal's ticket votes are only fetched if its total votes count has
+// changed since the previous sync. It returns the number of ticket votes saved.
+func (db *ProposalDB) updateTicketVotes() (int, error) {
+	var proposals []*pitypes.ProposalInfo
+	err := db.dbP.Select(
+		q.Or(
+			q.Eq("VoteStatus", pitypes.VoteStatusType(piapi.PropVoteStatusStarted)),
+			q.Eq("VoteStatus", pitypes.VoteStatusType(piapi.PropVoteStatusFinished)),
+		),
+	).Find(&proposals)
+	// Return an error only if the said error is not 'not found' error.
+	if err != nil && err != storm.ErrNotFound {
+		return 0, err
+	}
+
+	var count int
+	for _, val := range proposals {
+		var syncedVotes int64
+		err = db.dbP.Get(ticketVotesSync, val.TokenVal, &syncedVotes)
+		if err != nil && err != storm.ErrNotFound {
+			return count, err
+		}
+		if err == nil && syncedVotes == val.TotalVotes {
+			// No new votes since the previous sync.
+			continue
+		}
+
+		results, err := piclient.RetrieveProposalVoteResults(db.client, db.APIURLpath, val.TokenVal)
+		if err != nil {
+			// The ticket votes will be fetched on the next sync.
+			log.Errorf("RetrieveProposalVoteResults failed: %v ", err)
+			continue
+		}
+
+		db.mtx.Lock()
+		err = db.saveTicketVotes(val.TokenVal, results)
+		if err == nil {
+			err = db.dbP.Set(ticketVotesSync, val.TokenVal, val.TotalVotes)
+		}
+		db.mtx.Unlock()
+		if err != nil {
+			return count, fmt.Errorf("saving ticket votes for %s failed: %v", val.TokenVal, err)
+		}
+
+		count += len(results.CastVotes)
+	}
+
+	return count, nil
+}
+
+// saveTicketVotes replaces the ticket votes saved for the proposal identified by
+// token with the cast votes in results. The choice of each ticket is resolved
+// from the vote bits of the vote options.
+func (db *ProposalDB) saveTicketVotes(token string, results *pitypes.VoteResults) error {
+	choices := make(map[uint64]string, len(results.StartVote.Vote.Options))
+	for _, option := range results.StartVote.Vote.Options {
+		choices[uint64(option.Bits)] = option.OptionID
+	}
+
+	tx, err := db.dbP.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.Select(q.Eq("Token", token)).Delete(&pitypes.TicketVote{})
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+
+	for _, v := range results.CastVotes {
+		vote := &pitypes.TicketVote{
+			Token:   token,
+			Ticket:  v.Ticket,
+			VoteBit: v.VoteBit,
+		}
+		// Vote bits are hex encoded.
+		if bits, err := strconv.ParseUint(v.VoteBit, 16, 64); err == nil {
+			vote.Choice = choices[bits]
+		}
+
+		if err = tx.Save(vote); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// updateArchive fetches and saves the files of the proposals that have not been
+// archived, or whose archived version differs from the current version. Only
+// the latest version of a proposal is archived, replacing any earlier version.
+// The previous archive of a proposal is kept if its files cannot be fetched.
+// It returns the number of proposals archived.
+func (db *ProposalDB) updateArchive() (int, error) {
+	var proposals []*pitypes.ProposalInfo
+	err := db.dbP.All(&proposals)
+	if err != nil && err != storm.ErrNotFound {
+		return 0, err
+	}
+
+	var count int
+	for _, val := range proposals {
+		var archive pitypes.ProposalArchive
+		err = db.dbP.One("Token", val.TokenVal, &archive)
+		if err != nil && err != storm.ErrNotFound {
+			return count, err
+		}
+		if err == nil && archive.Version == val.Version {
+			continue
+		}
+
+		files, err := piclient.RetrieveProposalFiles(db.client, db.APIURLpath, val.TokenVal)
+		if err != nil {
+			// The files will be fetched on the next sync.
+			log.Errorf("RetrieveProposalFiles failed: %v ", err)
+			continue
+		}
+
+		archive = pitypes.ProposalArchive{
+			Token:    val.TokenVal,
+			Version:  files.Data.Version,
+			Body:     proposalBody(files.Data.Files),
+			Files:    files.Data.Files,
+			Archived: uint64(time.Now().UTC().Unix()),
+		}
+		if archive.Version == "" {
+			archive.Version = val.Version
+		}
+
+		db.mtx.Lock()
+		err = db.dbP.Save(&archive)
+		db.mtx.Unlock()
+		if err != nil {
+			return count, fmt.Errorf("archiving proposal %s failed: %v", val.TokenVal, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// proposalBody returns the decoded markdown text of the proposal index file
+// among the files provided, or an empty string if it is missing or invalid.
+func proposalBody(files []pitypes.AttachmentFile) string {
+	for _, f := range files {
+		if f.Name != proposalIndexFile {
+			continue
+		}
+		body, err := base64.StdEncoding.DecodeString(f.Payload)
+		if err != nil {
+			log.Warnf("invalid %s payload: %v", proposalIndexFile, err)
+			return ""
+		}
+		return string(body)
+	}
+	return ""
+}
+
+// indexProposals rebuilds the search index from the proposals and proposal
+// archives saved in the db. The index is built without holding mtx, which is
+// only locked to replace the index. The caller must hold syncMtx, unless the
+// ProposalDB is not yet in use.
+func (db *ProposalDB) indexProposals() error {
+	var proposals []*pitypes.ProposalInfo
+	err := db.dbP.All(&proposals)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+
+	var archives []*pitypes.ProposalArchive
+	err = db.dbP.All(&archives)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+
+	bodies := make(map[string]string, len(archives))
+	for _, a := range archives {
+		bodies[a.Token] = a.Body
+	}
+
+	index := newSearchIndex(proposals, bodies)
+	db.mtx.Lock()
+	db.index = index
+	db.mtx.Unlock()
+	return nil
+}
+
+// ProposalArchive returns the archived files of the latest version of the
+// proposal identified by the provided token, or nil if they have not been
+// archived. The archive is available even when Politeia is not.
+func (db *ProposalDB) ProposalArchive(proposalToken string) (*pitypes.ProposalArchive, error) {
+	if db == nil || db.dbP == nil {
+		return nil, errDef
+	}
+
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	var archive pitypes.ProposalArchive
+	err := db.dbP.One("Token", proposalToken, &archive)
+	if err == storm.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &archive, nil
+}
+
+// SearchProposals returns the proposals whose title, archived body, author, or
+// status match every term of the query, with the best matches first, and the
+// total number of matches. A term may be prefixed with the name of the field
+// it must match, e.g. "author:alice" or "status:finished".
+func (db *ProposalDB) SearchProposals(query string, offset, rowsCount int) (
+	results []*pitypes.ProposalSearchResult, totalCount int, err error) {
+	if db == nil || db.dbP == nil {
+		return nil, 0, errDef
+	}
+
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	if db.index == nil {
+		return []*pitypes.ProposalSearchResult{}, 0, nil
+	}
+
+	results, totalCount = db.index.results(query, offset, rowsCount)
+	return
+}
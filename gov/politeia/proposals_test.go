@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/asdine/storm"
+	"github.com/fonero-project/fnodata/gov/politeia/piclient"
 	pitypes "github.com/fonero-project/fnodata/gov/politeia/types"
 	piapi "github.com/fonero-project/politeia/politeiawww/api/www/v1"
 )
@@ -219,6 +220,62 @@ func mockServer() *httptest.Server {
 			  }
 		   ]
 		}`
+		case piclient.DropURLRegex(piapi.RouteProposalDetails, mockedPayload.TokenVal):
+			resp = `{
+			"proposal":{
+				"version":"1",
+				"files":[
+					{
+						"name":"index.md",
+						"mime":"text/plain; charset=utf-8",
+						"digest":"e2b3b2e4f4de3fd3d2c4d1a0f2b4c6d8e0f2a4b6c8d0e2f4a6b8c0d2e4f6a8b0",
+						"payload":"IyBDaGFuZ2UgbGFuZ3VhZ2UKClJlcGxhY2UgUG9TIE1pbmluZyB3aXRoIFBvUyBWb3Rpbmcu"
+					}
+				]
+			}
+		}`
+		case piclient.DropURLRegex(piapi.RouteVoteResults, mockedPayload.TokenVal):
+			resp = `{
+			"startvote":{
+				"vote":{
+					"token":"522652954ea7998f3fca95b9c4ca8907820eb785877dcf7fba92307131818c75",
+					"options":[
+						{
+							"id":"no",
+							"description":"Don't approve proposal",
+							"bits":1
+						},
+						{
+							"id":"yes",
+							"description":"Approve proposal",
+							"bits":2
+						}
+					]
+				}
+			},
+			"startvotereply":{
+				"startblockheight":"287484",
+				"startblockhash":"0000000000000000016ed4b1bb3a3fd5b1a3e8ac5b7f4e6fb4b2e2a3a5d2f4c1",
+				"endheight":"289500"
+			},
+			"castvotes":[
+				{
+					"token":"522652954ea7998f3fca95b9c4ca8907820eb785877dcf7fba92307131818c75",
+					"ticket":"0010b1c8e1b4f9f1c0fe5b8a9bfa7e6f3a7cd9e3c6f8e5e0a7b8f4f2d6b1d7a8",
+					"votebit":"2"
+				},
+				{
+					"token":"522652954ea7998f3fca95b9c4ca8907820eb785877dcf7fba92307131818c75",
+					"ticket":"002f9b1c7e5b0a1c4e9d8f3a6b2c7d5e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c",
+					"votebit":"1"
+				},
+				{
+					"token":"522652954ea7998f3fca95b9c4ca8907820eb785877dcf7fba92307131818c75",
+					"ticket":"00a4c9e2b7f1d3a5c6e8b0d2f4a6c8e0b2d4f6a8c0e2b4d6f8a0c2e4b6d8f0a2",
+					"votebit":"2"
+				}
+			]
+		}`
 		}
 		w.Write([]byte(resp))
 	}))
@@ -369,6 +426,86 @@ func TestStuff(t *testing.T) {
 		}
 	})
 
+	// Testing the ticket votes retrieval
+	t.Run("Test_ProposalTicketVotes", func(t *testing.T) {
+		votes, err := newDBInstance.ProposalTicketVotes(mockedPayload.TokenVal, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(votes) != 3 {
+			t.Fatalf("expected to find three ticket votes but found %d", len(votes))
+		}
+
+		ticket := "002f9b1c7e5b0a1c4e9d8f3a6b2c7d5e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c"
+		votes, err = newDBInstance.ProposalTicketVotes(mockedPayload.TokenVal, ticket)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(votes) != 1 {
+			t.Fatalf("expected to find one ticket vote but found %d", len(votes))
+		}
+
+		if votes[0].Ticket != ticket || votes[0].Choice != "no" {
+			t.Fatalf("expected ticket %s to have voted 'no' but found '%s'",
+				ticket, votes[0].Choice)
+		}
+
+		votes, err = newDBInstance.ProposalTicketVotes(firstProposal.TokenVal, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(votes) != 0 {
+			t.Fatalf("expected to find no ticket votes but found %d", len(votes))
+		}
+	})
+
+	// Testing the retrieval of the archived proposal files
+	t.Run("Test_ProposalArchive", func(t *testing.T) {
+		archive, err := newDBInstance.ProposalArchive(mockedPayload.TokenVal)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if archive.Version != "1" || len(archive.Files) != 1 {
+			t.Fatalf("unexpected archive %+v", archive)
+		}
+
+		body := "# Change language\n\nReplace PoS Mining with PoS Voting."
+		if archive.Body != body {
+			t.Fatalf("expected the archived body to be %q but found %q", body, archive.Body)
+		}
+
+		// The files of the first proposal could not be fetched.
+		archive, err = newDBInstance.ProposalArchive(firstProposal.TokenVal)
+		if err != nil || archive != nil {
+			t.Fatalf("expected no archive but found %+v (%v)", archive, err)
+		}
+	})
+
+	// Testing the proposals full-text search
+	t.Run("Test_SearchProposals", func(t *testing.T) {
+		results, count, err := newDBInstance.SearchProposals("replace mining", 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 1 || len(results) != 1 || results[0].TokenVal != mockedPayload.TokenVal {
+			t.Fatalf("expected to find the second proposal but found %+v", results)
+		}
+
+		results, count, err = newDBInstance.SearchProposals("author:secret-coder", 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 1 || len(results) != 1 || results[0].TokenVal != firstProposal.TokenVal {
+			t.Fatalf("expected to find the initial proposal but found %+v", results)
+		}
+	})
+
 	// Testing proposal retrieval by RefID
 	t.Run("Test_ProposalByRefID", func(t *testing.T) {
 		proposal, err := newDBInstance.ProposalByRefID("initial-test-proposal")
@@ -0,0 +1,245 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package politeia
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	pitypes "github.com/fonero-project/fnodata/gov/politeia/types"
+)
+
+// searchField is a proposal field indexed for full-text search.
+type searchField int
+
+const (
+	titleField searchField = iota
+	bodyField
+	authorField
+	statusField
+	numSearchFields
+
+	// anyField matches a term in any of the indexed fields.
+	anyField searchField = -1
+)
+
+// searchFields maps the field names that may prefix a search term, as in
+// "author:alice", to the indexed fields.
+var searchFields = map[string]searchField{
+	"title":  titleField,
+	"body":   bodyField,
+	"author": authorField,
+	"status": statusField,
+}
+
+// searchWeights are the scores of a single occurrence of a term in each field.
+// Matches in the title outrank matches in the much longer body.
+var searchWeights = [numSearchFields]int{
+	titleField:  10,
+	bodyField:   1,
+	authorField: 5,
+	statusField: 3,
+}
+
+// snippetLength is the approximate length in bytes of the excerpt of the
+// proposal body returned with a search result.
+const snippetLength = 160
+
+// termCounts are the occurrences of a term in each field of a proposal.
+type termCounts [numSearchFields]int
+
+// searchTerm is a term of a search query and the field it must be found in.
+type searchTerm struct {
+	term  string
+	field searchField
+}
+
+// searchIndex is an in-memory inverted index of the terms in the title, body,
+// author, and status of the proposals, built from the proposals db.
+type searchIndex struct {
+	// postings maps each term to its occurrences in the proposals, by token.
+	postings  map[string]map[string]*termCounts
+	proposals map[string]*pitypes.ProposalInfo
+	bodies    map[string]string
+}
+
+// newSearchIndex indexes the proposals and the archived proposal bodies, keyed
+// by proposal token.
+func newSearchIndex(proposals []*pitypes.ProposalInfo, bodies map[string]string) *searchIndex {
+	idx := &searchIndex{
+		postings:  make(map[string]map[string]*termCounts),
+		proposals: make(map[string]*pitypes.ProposalInfo, len(proposals)),
+		bodies:    bodies,
+	}
+	for _, p := range proposals {
+		idx.proposals[p.TokenVal] = p
+		idx.addText(p.TokenVal, titleField, p.Name)
+		idx.addText(p.TokenVal, bodyField, bodies[p.TokenVal])
+		idx.addText(p.TokenVal, authorField, p.Username)
+		idx.addText(p.TokenVal, statusField, p.Status.String()+" "+p.VoteStatus.ShortDesc())
+	}
+	return idx
+}
+
+// addText indexes the terms of text in the field of the proposal identified by
+// token.
+func (idx *searchIndex) addText(token string, field searchField, text string) {
+	for _, term := range tokenize(text) {
+		counts, found := idx.postings[term]
+		if !found {
+			counts = make(map[string]*termCounts)
+			idx.postings[term] = counts
+		}
+		if counts[token] == nil {
+			counts[token] = new(termCounts)
+		}
+		counts[token][field]++
+	}
+}
+
+// tokenize splits text into lowercase terms of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// parseQuery splits a search query into its terms. A word prefixed with the
+// name of a field and a colon only matches that field.
+func parseQuery(query string) []searchTerm {
+	var terms []searchTerm
+	for _, word := range strings.Fields(query) {
+		field := anyField
+		if i := strings.Index(word, ":"); i > 0 {
+			if f, ok := searchFields[strings.ToLower(word[:i])]; ok {
+				field = f
+				word = word[i+1:]
+			}
+		}
+		for _, term := range tokenize(word) {
+			terms = append(terms, searchTerm{term: term, field: field})
+		}
+	}
+	return terms
+}
+
+// search returns the scores of the proposals, by token, that match every term
+// of the query.
+func (idx *searchIndex) search(query string) map[string]int {
+	scores := make(map[string]int)
+	terms := parseQuery(query)
+	for i, t := range terms {
+		termScores := make(map[string]int)
+		for token, counts := range idx.postings[t.term] {
+			var score int
+			for f, n := range counts {
+				if t.field == anyField || t.field == searchField(f) {
+					score += n * searchWeights[f]
+				}
+			}
+			if score == 0 {
+				continue
+			}
+			// After the first term, only proposals that matched every previous
+			// term remain.
+			if i == 0 {
+				termScores[token] = score
+			} else if prev, ok := scores[token]; ok {
+				termScores[token] = prev + score
+			}
+		}
+		scores = termScores
+		if len(scores) == 0 {
+			break
+		}
+	}
+	return scores
+}
+
+// results makes the search results for the proposals matching the query,
+// ordered by descending score and then by newest update, and the total number
+// of matches. Only the rowsCount results after offset are returned.
+func (idx *searchIndex) results(query string, offset, rowsCount int) ([]*pitypes.ProposalSearchResult, int) {
+	scores := idx.search(query)
+	results := make([]*pitypes.ProposalSearchResult, 0, len(scores))
+	for token, score := range scores {
+		results = append(results, &pitypes.ProposalSearchResult{
+			ProposalInfo: idx.proposals[token],
+			Score:        score,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Timestamp > results[j].Timestamp
+	})
+
+	totalCount := len(results)
+	if offset >= totalCount {
+		return []*pitypes.ProposalSearchResult{}, totalCount
+	}
+	results = results[offset:]
+	if rowsCount < len(results) {
+		results = results[:rowsCount]
+	}
+
+	terms := parseQuery(query)
+	for _, r := range results {
+		r.Snippet = snippet(idx.bodies[r.TokenVal], terms)
+	}
+	return results, totalCount
+}
+
+// snippet returns an excerpt of the body around the first occurrence of any of
+// the terms, or the start of the body if none of the terms occur in it.
+func snippet(body string, terms []searchTerm) string {
+	if body == "" {
+		return ""
+	}
+	lower := strings.ToLower(body)
+	start := -1
+	for _, t := range terms {
+		if t.field != anyField && t.field != bodyField {
+			continue
+		}
+		// Lowercasing may change the length of some runes, so the position is
+		// only approximate.
+		if i := strings.Index(lower, t.term); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	start -= snippetLength / 4
+	if start < 0 {
+		start = 0
+	} else if start >= len(body) {
+		start = len(body) - 1
+	}
+	end := start + snippetLength
+	if end > len(body) {
+		end = len(body)
+	}
+	// Keep the excerpt on rune boundaries.
+	for start > 0 && !utf8.RuneStart(body[start]) {
+		start--
+	}
+	for end < len(body) && !utf8.RuneStart(body[end]) {
+		end++
+	}
+
+	excerpt := strings.Join(strings.Fields(body[start:end]), " ")
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(body) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package politeia
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pitypes "github.com/fonero-project/fnodata/gov/politeia/types"
+)
+
+func TestSearchIndex(t *testing.T) {
+	proposals := []*pitypes.ProposalInfo{
+		{
+			Name:             "Fonero Bug Bounty Proposal",
+			Username:         "alice",
+			Status:           4,
+			Timestamp:        100,
+			CensorshipRecord: pitypes.CensorshipRecord{TokenVal: "a"},
+		},
+		{
+			Name:             "Marketing Campaign",
+			Username:         "bob",
+			Status:           4,
+			Timestamp:        200,
+			CensorshipRecord: pitypes.CensorshipRecord{TokenVal: "b"},
+		},
+		{
+			Name:             "Wallet Tutorial Campaign",
+			Username:         "carol",
+			Status:           4,
+			Timestamp:        300,
+			CensorshipRecord: pitypes.CensorshipRecord{TokenVal: "c"},
+		},
+	}
+	bodies := map[string]string{
+		"a": "# Bug Bounty\n\nA bounty program for bugs found by Alice's team.",
+		"b": "A marketing campaign run by Alice.",
+	}
+	idx := newSearchIndex(proposals, bodies)
+
+	tests := []struct {
+		query  string
+		scores map[string]int
+	}{
+		{"bounty", map[string]int{"a": 10 + 2}},
+		{"CAMPAIGN", map[string]int{"b": 10 + 1, "c": 10}},
+		{"alice", map[string]int{"a": 5 + 1, "b": 1}},
+		{"author:alice", map[string]int{"a": 5}},
+		{"title:campaign body:alice", map[string]int{"b": 10 + 1}},
+		{"campaign tutorial", map[string]int{"c": 20}},
+		{"bounty marketing", map[string]int{}},
+		{"unknown:campaign", map[string]int{}},
+		{"", map[string]int{}},
+	}
+	for _, test := range tests {
+		if scores := idx.search(test.query); !reflect.DeepEqual(scores, test.scores) {
+			t.Errorf("search %q: got %v, want %v", test.query, scores, test.scores)
+		}
+	}
+
+	// Equal scores are ordered newest first.
+	results, count := idx.results("campaign alice", 0, 10)
+	if count != 1 || len(results) != 1 || results[0].TokenVal != "b" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if results[0].Snippet != "A marketing campaign run by Alice." {
+		t.Errorf("unexpected snippet %q", results[0].Snippet)
+	}
+
+	results, count = idx.results("campaign", 1, 10)
+	if count != 2 || len(results) != 1 || results[0].TokenVal != "c" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if results, count = idx.results("campaign", 2, 10); count != 2 || len(results) != 0 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	body := strings.Repeat("lorem ipsum ", 20) + "needle " + strings.Repeat("dolor sit ", 20)
+	s := snippet(body, parseQuery("needle"))
+	if !strings.HasPrefix(s, "…") || !strings.HasSuffix(s, "…") ||
+		!strings.Contains(s, "needle") {
+		t.Errorf("unexpected snippet %q", s)
+	}
+
+	if s = snippet("short\n\nbody", parseQuery("missing")); s != "short body" {
+		t.Errorf("unexpected snippet %q", s)
+	}
+
+	if s = snippet(body, parseQuery("author:needle")); strings.HasPrefix(s, "…") {
+		t.Errorf("expected the snippet to start the body, got %q", s)
+	}
+}
@@ -62,6 +62,43 @@ type AttachmentFile struct {
 	Payload   string `json:"payload"`
 }
 
+// ProposalFiles defines the files of a proposal payload as returned by the
+// RouteProposalDetails route.
+type ProposalFiles struct {
+	Data struct {
+		Version string           `json:"version"`
+		Files   []AttachmentFile `json:"files"`
+	} `json:"proposal"`
+}
+
+// ProposalArchive is the db record of the files of the latest version of a
+// proposal, archived so that the proposal remains available when Politeia is
+// not. Earlier versions are replaced when a new version is archived. Body is
+// the decoded markdown of the proposal's index file.
+type ProposalArchive struct {
+	Token    string           `json:"token" storm:"id"`
+	Version  string           `json:"version"`
+	Body     string           `json:"body"`
+	Files    []AttachmentFile `json:"files"`
+	Archived uint64           `json:"archived"`
+}
+
+// ProposalSearchResult is a proposal matching a full-text search. Snippet is
+// an excerpt of the proposal body around the first match, if any.
+type ProposalSearchResult struct {
+	*ProposalInfo
+	Score   int    `json:"score"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// ProposalSearchResults is a page of the results of a full-text proposal
+// search and the total number of proposals matching the query.
+type ProposalSearchResults struct {
+	Query      string                  `json:"query"`
+	TotalCount int                     `json:"total_count"`
+	Results    []*ProposalSearchResult `json:"results"`
+}
+
 // ProposalVotes defines the proposal status(Votes infor for the public proposals).
 // https://github.com/fonero-project/politeia/blob/master/politeiawww/api/www/v1/api.md#proposal-vote-status
 type ProposalVotes struct {
@@ -93,6 +130,48 @@ type VoteOption struct {
 	Bits        int32  `json:"bits"`
 }
 
+// VoteResults defines the proposal vote details and the votes cast by the
+// individual tickets as returned by the RouteVoteResults route.
+// https://github.com/fonero-project/politeia/blob/master/politeiawww/api/www/v1/api.md#proposal-votes
+type VoteResults struct {
+	StartVote      StartVote      `json:"startvote"`
+	StartVoteReply StartVoteReply `json:"startvotereply"`
+	CastVotes      []CastVote     `json:"castvotes"`
+}
+
+// StartVote defines the vote parameters set when the proposal voting started.
+type StartVote struct {
+	Vote struct {
+		Token   string       `json:"token"`
+		Options []VoteOption `json:"options"`
+	} `json:"vote"`
+}
+
+// StartVoteReply defines the voting period set when the proposal voting
+// started.
+type StartVoteReply struct {
+	StartBlockHeight string `json:"startblockheight"`
+	StartBlockHash   string `json:"startblockhash"`
+	EndHeight        string `json:"endheight"`
+}
+
+// CastVote defines a vote cast by a ticket on a proposal.
+type CastVote struct {
+	Token   string `json:"token"`
+	Ticket  string `json:"ticket"`
+	VoteBit string `json:"votebit"`
+}
+
+// TicketVote is the db record of the vote cast by a single ticket on a
+// proposal. Choice is the ID of the vote option selected by VoteBit.
+type TicketVote struct {
+	ID      int    `json:"-" storm:"id,increment"`
+	Token   string `json:"token" storm:"index"`
+	Ticket  string `json:"ticket" storm:"index"`
+	VoteBit string `json:"votebit"`
+	Choice  string `json:"choice"`
+}
+
 // ProposalStatusType defines the various proposal statuses available as referenced
 // in https://github.com/fonero-project/politeia/blob/master/politeiawww/api/www/v1/v1.go
 type ProposalStatusType piapi.PropStatusT
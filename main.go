@@ -223,6 +223,36 @@ func _main(ctx context.Context) error {
 		return err
 	}
 
+	if cfg.PruneHistory > 0 {
+		log.Infof("Archival pruning enabled. Keeping full history for the "+
+			"last %d blocks.", cfg.PruneHistory)
+		chainDB.EnableHistoryPruning(cfg.PruneHistory)
+	}
+
+	if len(cfg.stakePools) > 0 {
+		log.Infof("Tracking %d configured stake pools.", len(cfg.stakePools))
+	}
+	chainDB.SetStakePools(cfg.stakePools)
+	if len(cfg.miningPools) > 0 {
+		log.Infof("Tracking %d configured mining pools.", len(cfg.miningPools))
+	}
+	chainDB.SetMiningPools(cfg.miningPools)
+	log.Infof("Loaded %d address labels (version %d).",
+		len(cfg.addressLabels.Labels), cfg.addressLabels.Version)
+	chainDB.SetAddressLabels(cfg.addressLabels)
+	go reloadListener(ctx, func() {
+		labels, err := reloadAddressLabels(cfg.AddressLabelsFile, activeChain,
+			chainDB.AddressLabels().Version)
+		if err != nil {
+			log.Errorf("Unable to reload address labels: %v", err)
+			return
+		}
+		chainDB.SetAddressLabels(labels)
+		log.Infof("Reloaded %d address labels (version %d).",
+			len(labels.Labels), labels.Version)
+	})
+	chainDB.SetTreasurySpends(cfg.treasurySpends)
+
 	// Wrap ChainDB with an RPC client. TODO: redefine or remove ChainDBRPC.
 	pgDB, err := fnopg.NewChainDBRPC(chainDB, fnodClient)
 	if err != nil {
@@ -500,6 +530,13 @@ func _main(ctx context.Context) error {
 	blockDataSavers = append(blockDataSavers, baseDB)
 
 	mempoolSavers := []mempool.MempoolDataSaver{baseDB.MPC} // mempool.MempoolDataCache
+	if cfg.MempoolHistory {
+		log.Infof("Recording mempool history. Keeping %d days of history.",
+			cfg.MPHistoryDays)
+		chainDB.EnableMempoolHistory(cfg.MPSnapInterval,
+			time.Duration(cfg.MPHistoryDays)*24*time.Hour)
+		mempoolSavers = append(mempoolSavers, chainDB)
+	}
 
 	// Allow Ctrl-C to halt startup here.
 	if shutdownRequested(ctx) {
@@ -743,6 +780,9 @@ func _main(ctx context.Context) error {
 		JsonIndent:        cfg.IndentJSON,
 		XcBot:             xcBot,
 		AgendasDBInstance: agendasInstance,
+		ProposalsDB:       proposalsInstance,
+		FeeEstimator:      mpm,
+		VoteTracker:       tracker,
 		MaxAddrs:          cfg.MaxCSVAddrs,
 		Charts:            charts,
 	})
@@ -852,6 +892,11 @@ func _main(ctx context.Context) error {
 		r.Get("/search", explore.Search)
 		r.Get("/charts", explore.Charts)
 		r.Get("/ticketpool", explore.Ticketpool)
+		r.Get("/stakepools", explore.StakePoolsPage)
+		r.With(explorer.StakePoolPathCtx).Get("/stakepool/{pool}", explore.StakePoolPage)
+		r.Get("/treasury", explore.TreasuryPage)
+		r.Get("/miners", explore.MinersPage)
+		r.Get("/labels", explore.LabelsPage)
 		r.Get("/stats", explore.StatsPage)
 		r.Get("/market", explore.MarketPage)
 		r.Get("/statistics", func(w http.ResponseWriter, r *http.Request) {
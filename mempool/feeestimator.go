@@ -0,0 +1,275 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"math"
+	"sync"
+
+	apitypes "github.com/fonero-project/fnodata/api/types"
+	exptypes "github.com/fonero-project/fnodata/explorer/types"
+)
+
+const (
+	// minFeeBucket is the lower edge, in FNO/kB, of the lowest fee rate bucket.
+	minFeeBucket = 0.0001
+	// feeBucketSpacing is the ratio of the upper and lower edges of each fee
+	// rate bucket.
+	feeBucketSpacing = 1.1
+	// numFeeBuckets is the number of fee rate buckets, covering fee rates up
+	// to about 1 FNO/kB. Higher fee rates are counted in the last bucket.
+	numFeeBuckets = 97
+
+	// maxConfirmBlocks is the number of blocks after which a transaction that
+	// is still in mempool is counted as failing to confirm for any target.
+	maxConfirmBlocks = 24
+	// maxFeeObservations is the number of recent confirmation observations
+	// kept for each transaction class.
+	maxFeeObservations = 5000
+	// minEstimateSamples is the smallest number of observations for a group of
+	// fee rate buckets to be evaluated.
+	minEstimateSamples = 10
+	// estimateSuccessRate is the fraction of transactions in a group of fee
+	// rate buckets that must have been mined within the target number of
+	// blocks for the group's fee rate to be sufficient.
+	estimateSuccessRate = 0.85
+)
+
+// FeeEstimateTargets are the confirmation targets, in blocks, for which fee
+// rates are estimated.
+var FeeEstimateTargets = []int{1, 2, 6}
+
+// feeTxClass distinguishes transaction types with separate fee markets.
+type feeTxClass int
+
+const (
+	feeClassRegular feeTxClass = iota
+	feeClassTicket
+	numFeeClasses
+)
+
+// txFeeClass returns the feeTxClass for the transaction type string, and false
+// for transaction types that are not tracked, such as votes.
+func txFeeClass(txType string) (feeTxClass, bool) {
+	switch txType {
+	case "Regular":
+		return feeClassRegular, true
+	case "Ticket":
+		return feeClassTicket, true
+	default:
+		return 0, false
+	}
+}
+
+// feeRateBucket returns the index of the fee rate bucket for a fee rate in
+// FNO/kB.
+func feeRateBucket(feeRate float64) int {
+	if feeRate < minFeeBucket {
+		return 0
+	}
+	b := int(math.Log(feeRate/minFeeBucket) / math.Log(feeBucketSpacing))
+	if b >= numFeeBuckets {
+		return numFeeBuckets - 1
+	}
+	return b
+}
+
+// feeBucketRate returns the lower edge of the fee rate bucket in FNO/kB.
+func feeBucketRate(bucket int) float64 {
+	rate := minFeeBucket * math.Pow(feeBucketSpacing, float64(bucket))
+	// Round to whole atoms/kB.
+	return math.Ceil(rate*1e8) / 1e8
+}
+
+// pendingFeeTx is a tracked transaction in mempool. seenHeight is the best
+// block height when the transaction was first seen, or -1 if the transaction
+// was already in mempool when tracking started.
+type pendingFeeTx struct {
+	class      feeTxClass
+	feeRate    float64
+	seenHeight int64
+}
+
+// feeObservation records the number of blocks a transaction with the given
+// fee rate waited in mempool before it was mined. blocks is 0 if the
+// transaction was not mined within maxConfirmBlocks.
+type feeObservation struct {
+	feeRate float64
+	blocks  int64
+}
+
+// feeObservations is a ring buffer of the most recent feeObservations.
+type feeObservations struct {
+	obs  []feeObservation
+	next int
+}
+
+func (f *feeObservations) add(o feeObservation) {
+	if len(f.obs) < maxFeeObservations {
+		f.obs = append(f.obs, o)
+		return
+	}
+	f.obs[f.next] = o
+	f.next = (f.next + 1) % maxFeeObservations
+}
+
+// feeEstimator estimates the fee rates required for transactions to be mined
+// within a target number of blocks, based on the number of blocks recent
+// transactions at each fee rate waited in mempool.
+type feeEstimator struct {
+	mtx          sync.Mutex
+	height       int64
+	pending      map[string]*pendingFeeTx
+	observations [numFeeClasses]feeObservations
+}
+
+func newFeeEstimator() *feeEstimator {
+	return &feeEstimator{
+		height:  -1,
+		pending: make(map[string]*pendingFeeTx),
+	}
+}
+
+// newTx tracks a transaction that just entered mempool.
+func (e *feeEstimator) newTx(tx *exptypes.MempoolTx) {
+	class, ok := txFeeClass(tx.Type)
+	if !ok || tx.Size == 0 {
+		return
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.height < 0 {
+		// Wait for the initial mempool collection.
+		return
+	}
+	if _, found := e.pending[tx.Hash]; found {
+		return
+	}
+	e.pending[tx.Hash] = &pendingFeeTx{
+		class:      class,
+		feeRate:    tx.Fees * 1000 / float64(tx.Size),
+		seenHeight: e.height,
+	}
+}
+
+// processMempool updates the estimator with the full contents of mempool
+// collected at the given best block height. Tracked transactions that are no
+// longer in mempool after a new block are considered mined in that block.
+func (e *feeEstimator) processMempool(height int64, txs []exptypes.MempoolTx) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	inMempool := make(map[string]struct{}, len(txs))
+	for i := range txs {
+		inMempool[txs[i].Hash] = struct{}{}
+	}
+
+	// Observations are only made when the chain has advanced. On startup and
+	// after a reorganization, only the tracked transactions are updated.
+	newBlock := e.height >= 0 && height > e.height
+
+	for hash, ptx := range e.pending {
+		_, stillPending := inMempool[hash]
+		if !stillPending {
+			delete(e.pending, hash)
+			if newBlock && ptx.seenHeight >= 0 {
+				e.observations[ptx.class].add(feeObservation{
+					feeRate: ptx.feeRate,
+					blocks:  height - ptx.seenHeight,
+				})
+			}
+			continue
+		}
+		if ptx.seenHeight >= 0 && height-ptx.seenHeight > maxConfirmBlocks {
+			// Count as not mined, and stop tracking.
+			delete(e.pending, hash)
+			e.observations[ptx.class].add(feeObservation{
+				feeRate: ptx.feeRate,
+			})
+		}
+	}
+
+	// Track transactions that were not signaled individually. When the
+	// estimator has just started, their arrival height is unknown.
+	seenHeight := e.height
+	if e.height < 0 {
+		seenHeight = -1
+	}
+	for i := range txs {
+		tx := &txs[i]
+		class, ok := txFeeClass(tx.Type)
+		if !ok || tx.Size == 0 {
+			continue
+		}
+		if _, found := e.pending[tx.Hash]; found {
+			continue
+		}
+		e.pending[tx.Hash] = &pendingFeeTx{
+			class:      class,
+			feeRate:    tx.Fees * 1000 / float64(tx.Size),
+			seenHeight: seenHeight,
+		}
+	}
+
+	e.height = height
+}
+
+// estimate computes the fee rate estimates for each of the targets for the
+// transaction class. The fee rate buckets are grouped, from the highest fee
+// rate down, until each group has at least minEstimateSamples observations.
+// The estimate is the lowest fee rate of the lowest group in which at least
+// estimateSuccessRate of the transactions were mined within the target, with
+// all higher groups also meeting that rate.
+func (e *feeEstimator) estimate(class feeTxClass, targets []int) []apitypes.FeeEstimate {
+	var counts [numFeeBuckets]int
+	var mined [numFeeBuckets][maxConfirmBlocks + 1]int
+	obs := e.observations[class].obs
+	for _, o := range obs {
+		b := feeRateBucket(o.feeRate)
+		counts[b]++
+		if o.blocks > 0 && o.blocks <= maxConfirmBlocks {
+			mined[b][o.blocks]++
+		}
+	}
+
+	estimates := make([]apitypes.FeeEstimate, 0, len(targets))
+	for _, target := range targets {
+		est := apitypes.FeeEstimate{
+			Blocks:     target,
+			SampleSize: len(obs),
+		}
+
+		var groupCount, groupMined int
+		for b := numFeeBuckets - 1; b >= 0; b-- {
+			groupCount += counts[b]
+			for blocks := 1; blocks <= target && blocks <= maxConfirmBlocks; blocks++ {
+				groupMined += mined[b][blocks]
+			}
+			if groupCount < minEstimateSamples {
+				continue
+			}
+			if float64(groupMined)/float64(groupCount) < estimateSuccessRate {
+				break
+			}
+			est.FeeRate = feeBucketRate(b)
+			groupCount, groupMined = 0, 0
+		}
+
+		estimates = append(estimates, est)
+	}
+	return estimates
+}
+
+// feeEstimates returns the fee rate estimates for regular and ticket purchase
+// transactions for each of the FeeEstimateTargets.
+func (e *feeEstimator) feeEstimates() *apitypes.FeeEstimates {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return &apitypes.FeeEstimates{
+		Height:  e.height,
+		Regular: e.estimate(feeClassRegular, FeeEstimateTargets),
+		Tickets: e.estimate(feeClassTicket, FeeEstimateTargets),
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"fmt"
+	"testing"
+
+	exptypes "github.com/fonero-project/fnodata/explorer/types"
+)
+
+func TestFeeRateBucket(t *testing.T) {
+	tests := []struct {
+		feeRate float64
+		want    int
+	}{
+		{0, 0},
+		{0.0001, 0},
+		{0.000109, 0},
+		{0.000111, 1},
+		{0.001, 24},
+		{100, numFeeBuckets - 1},
+	}
+	for _, tt := range tests {
+		if got := feeRateBucket(tt.feeRate); got != tt.want {
+			t.Errorf("feeRateBucket(%v) = %d, want %d", tt.feeRate, got, tt.want)
+		}
+	}
+}
+
+func TestFeeEstimator(t *testing.T) {
+	e := newFeeEstimator()
+
+	// Transactions in mempool at startup have unknown arrival times and are
+	// never observed.
+	startTx := exptypes.MempoolTx{Hash: "start", Type: "Regular", Fees: 0.0001, Size: 1000}
+	e.processMempool(100, []exptypes.MempoolTx{startTx})
+	if len(e.pending) != 1 || e.pending["start"].seenHeight != -1 {
+		t.Fatalf("startup transaction not tracked with unknown arrival")
+	}
+
+	// High fee transactions are mined in the next block, while low fee
+	// transactions wait 4 blocks.
+	height := int64(100)
+	var waiting []exptypes.MempoolTx
+	for i := 0; i < 20; i++ {
+		high := exptypes.MempoolTx{Hash: fmt.Sprintf("high%d", i), Type: "Regular", Fees: 0.001, Size: 1000}
+		low := exptypes.MempoolTx{Hash: fmt.Sprintf("low%d", i), Type: "Regular", Fees: 0.0001, Size: 1000}
+		e.newTx(&high)
+		e.newTx(&low)
+		waiting = append(waiting, low)
+
+		height++
+		e.processMempool(height, waiting)
+		if len(waiting) == 3 {
+			waiting = waiting[1:]
+		}
+	}
+
+	// Votes and revocations are not tracked.
+	vote := exptypes.MempoolTx{Hash: "vote", Type: "Vote", Size: 300}
+	e.newTx(&vote)
+	if _, found := e.pending["vote"]; found {
+		t.Errorf("vote tracked for fee estimation")
+	}
+
+	est := e.feeEstimates()
+	if est.Height != height {
+		t.Errorf("incorrect height %d, expected %d", est.Height, height)
+	}
+	if len(est.Regular) != len(FeeEstimateTargets) || len(est.Tickets) != len(FeeEstimateTargets) {
+		t.Fatalf("incorrect number of estimates")
+	}
+
+	// A 1 block target requires the high fee rate, while a 6 block target is
+	// met by the low fee rate.
+	if est.Regular[0].FeeRate != feeBucketRate(feeRateBucket(0.001)) {
+		t.Errorf("incorrect 1 block estimate %v", est.Regular[0].FeeRate)
+	}
+	if est.Regular[2].FeeRate != feeBucketRate(0) {
+		t.Errorf("incorrect 6 block estimate %v", est.Regular[2].FeeRate)
+	}
+
+	// No tickets were observed.
+	for _, tixEst := range est.Tickets {
+		if tixEst.FeeRate != 0 || tixEst.SampleSize != 0 {
+			t.Errorf("unexpected ticket estimate %v", tixEst)
+		}
+	}
+}
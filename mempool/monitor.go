@@ -17,6 +17,7 @@ import (
 	"github.com/fonero-project/fnod/chaincfg/chainhash"
 	"github.com/fonero-project/fnod/fnojson"
 	"github.com/fonero-project/fnod/rpcclient"
+	apitypes "github.com/fonero-project/fnodata/api/types"
 	exptypes "github.com/fonero-project/fnodata/explorer/types"
 	pstypes "github.com/fonero-project/fnodata/pubsub/types"
 	"github.com/fonero-project/fnodata/txhelpers"
@@ -28,6 +29,13 @@ type MempoolDataSaver interface {
 	StoreMPData(*StakeData, []exptypes.MempoolTx, *exptypes.MempoolInfo)
 }
 
+// MempoolTxSaver is an interface for storing each new transaction as it enters
+// mempool. A MempoolDataSaver that also implements MempoolTxSaver is sent the
+// new transactions between the full mempool updates.
+type MempoolTxSaver interface {
+	StoreMPTx(*exptypes.MempoolTx)
+}
+
 // MempoolAddressStore wraps txhelpers.MempoolAddressStore with a Mutex.
 type MempoolAddressStore struct {
 	mtx   sync.Mutex
@@ -55,6 +63,7 @@ type MempoolMonitor struct {
 	params     *chaincfg.Params
 	collector  *MempoolDataCollector
 	dataSavers []MempoolDataSaver
+	fees       *feeEstimator
 
 	// Incoming data
 	newTxIn <-chan *fnojson.TxRawResult
@@ -80,6 +89,7 @@ func NewMempoolMonitor(ctx context.Context, collector *MempoolDataCollector,
 		params:     params,
 		collector:  collector,
 		dataSavers: savers,
+		fees:       newFeeEstimator(),
 		newTxIn:    newTxInChan,
 		signalOuts: signalOuts,
 		wg:         wg,
@@ -342,6 +352,18 @@ func (p *MempoolMonitor) TxHandler(client *rpcclient.Client) {
 			p.inventory.Unlock()
 			p.mtx.RUnlock()
 
+			// Track the transaction's time in mempool for fee estimation.
+			p.fees.newTx(&tx)
+
+			// Store the new transaction with each saver that records
+			// individual transactions.
+			for _, saver := range p.dataSavers {
+				if txSaver, ok := saver.(MempoolTxSaver); ok {
+					txCopy := tx
+					go txSaver.StoreMPTx(&txCopy)
+				}
+			}
+
 			// Broadcast the new transaction.
 			log.Tracef("Signaling new tx to hub relays...")
 			p.hubSend(pstypes.SigNewTx, &tx, time.Second*10)
@@ -382,6 +404,9 @@ func (p *MempoolMonitor) Refresh() (*StakeData, []exptypes.MempoolTx, *exptypes.
 	sort.Sort(exptypes.MPTxsByTime(txs))
 	inventory := ParseTxns(txs, p.params, &stakeData.LatestBlock)
 
+	// Observe which transactions were mined for fee estimation.
+	p.fees.processMempool(stakeData.LatestBlock.Height, txs)
+
 	// Reset the counter for tickets since last report.
 	p.mtx.Lock()
 	newTickets := p.mpoolInfo.NumTicketsSinceStatsReport
@@ -436,6 +461,14 @@ func (p *MempoolMonitor) CollectAndStore() error {
 	return nil
 }
 
+// FeeEstimates returns the fee rates, in FNO/kB, at which regular and ticket
+// purchase transactions are expected to be mined within each of the
+// FeeEstimateTargets, based on the observed time recent transactions spent in
+// mempool.
+func (p *MempoolMonitor) FeeEstimates() *apitypes.FeeEstimates {
+	return p.fees.feeEstimates()
+}
+
 // UnconfirmedTxnsForAddress indexes (1) outpoints in mempool that pay to the
 // given address, (2) previous outpoint being consumed that paid to the address,
 // and (3) all relevant transactions. See txhelpers.AddressOutpoints for more
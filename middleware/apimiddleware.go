@@ -55,6 +55,8 @@ const (
 	ctxProposalToken
 	ctxXcToken
 	ctxStickWidth
+	ctxXpub
+	ctxStakePool
 )
 
 type DataSource interface {
@@ -635,6 +637,27 @@ func AddressPathCtx(next http.Handler) http.Handler {
 	})
 }
 
+// XpubPathCtx returns a http.HandlerFunc that embeds the value at the url part
+// {xpub} into the request context.
+func XpubPathCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		xpub := chi.URLParam(r, "xpub")
+		ctx := context.WithValue(r.Context(), ctxXpub, xpub)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetXpubCtx retrieves the ctxXpub data from the request context. If not set,
+// the return value is an empty string.
+func GetXpubCtx(r *http.Request) string {
+	xpub, ok := r.Context().Value(ctxXpub).(string)
+	if !ok {
+		apiLog.Trace("xpub not set")
+		return ""
+	}
+	return xpub
+}
+
 // ChartTypeCtx returns a http.HandlerFunc that embeds the value at the url
 // part {charttype} into the request context.
 func ChartTypeCtx(next http.Handler) http.Handler {
@@ -773,6 +796,27 @@ func GetAgendaIdCtx(r *http.Request) string {
 	return agendaId
 }
 
+// StakePoolCtx returns a http.HandlerFunc that embeds the value at the url
+// part {pool}, a stake pool name or address, into the request context.
+func StakePoolCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pool := chi.URLParam(r, "pool")
+		ctx := context.WithValue(r.Context(), ctxStakePool, pool)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetStakePoolCtx retrieves the ctxStakePool data from the request context.
+// If not set, the return value is an empty string.
+func GetStakePoolCtx(r *http.Request) string {
+	pool, ok := r.Context().Value(ctxStakePool).(string)
+	if !ok {
+		apiLog.Error("stake pool not parsed")
+		return ""
+	}
+	return pool
+}
+
 // BlockHashPathAndIndexCtx embeds the value at the url part {blockhash}, and
 // the corresponding block index, into a request context.
 func BlockHashPathAndIndexCtx(r *http.Request, source DataSource) context.Context {
@@ -17,6 +17,8 @@ const aDay = 86400 * 1000
 const aMonth = aDay * 30
 const atomsToFNO = 1e-8
 const windowScales = ['ticket-price', 'pow-difficulty']
+// Charts that are not served by /api/chart, and have no bin sizes.
+const apiCharts = { 'utxo-ages': '/api/utxo/ages' }
 var ticketPoolSizeTarget, premine, stakeValHeight, stakeShare
 var baseSubsidy, subsidyInterval, subsidyExponent
 var userBins = {}
@@ -135,6 +137,51 @@ function circulationFunc (gData, blocks) {
   return data
 }
 
+function supplyFunc (gData) {
+  return map(gData.x, (t, i) => {
+    return [
+      new Date(t * 1000),
+      gData.y[i] * atomsToFNO,
+      gData.z[i] * atomsToFNO,
+      gData.x1[i] * atomsToFNO,
+      gData.y1[i] * atomsToFNO,
+      gData.z1[i] * atomsToFNO
+    ]
+  })
+}
+
+function feeRatesFunc (gData) {
+  return map(gData.x, (t, i) => {
+    return [
+      new Date(t * 1000),
+      gData.y[i] * atomsToFNO,
+      gData.z[i] * atomsToFNO,
+      gData.x1[i] * atomsToFNO,
+      gData.y1[i] * atomsToFNO,
+      gData.z1[i] * atomsToFNO,
+      gData.x2[i] * atomsToFNO,
+      gData.y2[i] * atomsToFNO
+    ]
+  })
+}
+
+function utxoSetFunc (gData) {
+  return map(gData.x, (t, i) => {
+    return [new Date(t * 1000), gData.y[i], gData.z[i] * atomsToFNO]
+  })
+}
+
+// utxoAgesFunc converts the amounts in each UTXO age band to percentages of
+// the total unspent value on each day.
+function utxoAgesFunc (data) {
+  return map(data.days, (day) => {
+    let total = day.amounts.reduce((sum, amount) => sum + amount, 0)
+    return [new Date(day.time * 1000)].concat(map(day.amounts, (amount) => {
+      return total > 0 ? amount / total * 100 : 0
+    }))
+  })
+}
+
 function mapDygraphOptions (data, labelsVal, isDrawPoint, yLabel, xLabel, titleName, labelsMG, labelsMG2) {
   return merge({
     'file': data,
@@ -311,6 +358,81 @@ export default class extends Controller {
           undefined, true, false))
         break
 
+      case 'nulldata': // nulldata outputs graph
+        d = zipYvDate(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Nulldata Outputs'], false, 'Nulldata Outputs', 'Date',
+          undefined, true, false))
+        break
+
+      case 'missed-votes': // missed votes graph
+        d = zipYvDate(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Missed Votes'], false, 'Missed Votes', 'Date',
+          undefined, true, false))
+        break
+
+      case 'supply': // supply breakdown graph
+        d = supplyFunc(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Circulating', 'Staked', 'Project Fund', 'Burned', 'Immature'],
+          false, 'Supply (FNO)', 'Date', undefined, true, false))
+        gOptions.colors = ['#2970FF', '#2DD8A3', '#FD714B', '#8997A5', '#F2C94C']
+        break
+
+      case 'fee-rates': // regular transaction fee rates graph
+      case 'ticket-fee-rates': // ticket fee rates graph
+        d = feeRatesFunc(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Min', '10th Percentile', '25th Percentile', 'Median',
+          '75th Percentile', '90th Percentile', 'Max'], false, 'Fee Rate (FNO/kB)', 'Date', undefined, false, false))
+        gOptions.series = {
+          'Min': { strokePattern: [5, 3] },
+          'Max': { strokePattern: [5, 3] }
+        }
+        break
+
+      case 'active-addresses': // active addresses graph
+        d = zipYvDate(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Active Addresses'], false, 'Active Addresses', 'Date',
+          undefined, true, false))
+        break
+
+      case 'new-addresses': // new addresses graph
+        d = zipYvDate(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'New Addresses'], false, 'New Addresses', 'Date',
+          undefined, true, false))
+        break
+
+      case 'utxo-set': // unspent output count and value graph
+        d = utxoSetFunc(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Unspent Outputs', 'Unspent Value'], false, 'Unspent Outputs', 'Date',
+          undefined, true, false))
+        gOptions.y2label = 'Unspent Value (FNO)'
+        gOptions.series = {
+          'Unspent Value': {
+            axis: 'y2'
+          }
+        }
+        break
+
+      case 'avg-tx-value': // average transaction value graph
+        d = zipYvDate(data, atomsToFNO)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Average Transaction Value'], false, 'Average Value (FNO)', 'Date',
+          undefined, true, false))
+        break
+
+      case 'coin-days-destroyed': // coin-days destroyed graph
+        d = zipYvDate(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date', 'Coin-Days Destroyed'], false, 'Coin-Days Destroyed', 'Date',
+          undefined, true, false))
+        break
+
+      case 'utxo-ages': // UTXO age bands (HODL waves) graph
+        d = utxoAgesFunc(data)
+        assign(gOptions, mapDygraphOptions(d, ['Date'].concat(data.bands), false, 'Share of Unspent Value (%)', 'Date',
+          undefined, false, false))
+        gOptions.digitsAfterDecimal = 2
+        gOptions.stackedGraph = true
+        gOptions.fillGraph = true
+        break
+
       case 'duration-btw-blocks': // Duration between blocks graph
         d = zipYvDate(data)
         assign(gOptions, mapDygraphOptions(d, ['Block Height', 'Duration Between Block'], false, 'Duration Between Block (seconds)', 'Block Height',
@@ -337,8 +459,8 @@ export default class extends Controller {
     var selection = this.settings.chart = this.chartSelectTarget.value
     this.chartWrapperTarget.classList.add('loading')
     if (selectedChart !== selection) {
-      let url = '/api/chart/' + selection
-      if (usesWindowUnits(selection)) {
+      let url = apiCharts[selection] || '/api/chart/' + selection
+      if (usesWindowUnits(selection) || apiCharts[selection]) {
         this.binSelectorTarget.classList.add('d-hide')
       } else {
         this.binSelectorTarget.classList.remove('d-hide')
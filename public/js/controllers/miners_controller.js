@@ -0,0 +1,65 @@
+import { Controller } from 'stimulus'
+import { getDefault } from '../helpers/module_helper'
+import axios from 'axios'
+
+var chartLayout = {
+  showRangeSelector: true,
+  legend: 'follow',
+  labelsSeparateLines: true,
+  stackedGraph: true,
+  fillGraph: true
+}
+
+function poolLabel (pool) {
+  return pool || 'Unknown'
+}
+
+// sharesData converts the blocks mined by each pool in each ticket price
+// window to the percentage of the window's blocks.
+function sharesData (m) {
+  if (!m.height.length) return [[0, 0]]
+  return m.height.map((h, i) => {
+    var total = m.blocks.reduce((sum, blocks) => sum + blocks[i], 0)
+    return [h].concat(m.blocks.map(blocks => total ? blocks[i] * 100 / total : 0))
+  })
+}
+
+export default class extends Controller {
+  static get targets () {
+    return [
+      'shares'
+    ]
+  }
+
+  initialize () {
+    this.sharesChart = false
+  }
+
+  async connect () {
+    this.element.classList.add('loading')
+    this.Dygraph = await getDefault(
+      import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+    )
+    let response = await axios.get('/api/miners/chart')
+    let chart = response.data
+    let pools = chart.pools.length ? chart.pools : ['']
+    this.sharesChart = new this.Dygraph(
+      this.sharesTarget,
+      sharesData(chart),
+      {
+        ...chartLayout,
+        labels: ['Block Height'].concat(pools.map(poolLabel)),
+        ylabel: 'Share of Blocks (%)',
+        xlabel: 'Block Height',
+        title: 'Mining Pool Shares by Ticket Price Window',
+        valueRange: [0, 100]
+      }
+    )
+
+    this.element.classList.remove('loading')
+  }
+
+  disconnect () {
+    if (this.sharesChart) this.sharesChart.destroy()
+  }
+}
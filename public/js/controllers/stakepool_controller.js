@@ -0,0 +1,117 @@
+import { Controller } from 'stimulus'
+import { barChartPlotter } from '../helpers/chart_helper'
+import { getDefault } from '../helpers/module_helper'
+import axios from 'axios'
+
+var chartLayout = {
+  showRangeSelector: true,
+  legend: 'follow',
+  labelsSeparateLines: true,
+  labelsKMB: true
+}
+
+function liveTicketsData (d) {
+  if (!d.height.length) return [[0, 0]]
+  return d.height.map((h, i) => [h, d.live[i]])
+}
+
+function votesMissesData (d) {
+  if (!d.height.length) return [[0, 0, 0]]
+  return d.height.map((h, i) => [h, d.votes[i], d.misses[i]])
+}
+
+function missRateData (d) {
+  if (!d.height.length) return [[0, 0]]
+  return d.height.map((h, i) => [h, d.miss_rate[i] * 100])
+}
+
+export default class extends Controller {
+  static get targets () {
+    return [
+      'liveTickets',
+      'votesMisses',
+      'missRate'
+    ]
+  }
+
+  initialize () {
+    this.liveTicketsChart = false
+    this.votesMissesChart = false
+    this.missRateChart = false
+  }
+
+  async connect () {
+    this.poolName = this.data.get('name')
+    this.element.classList.add('loading')
+    this.Dygraph = await getDefault(
+      import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+    )
+    this.drawCharts()
+    let chartResponse = await axios.get('/api/stakepool/' + encodeURIComponent(this.poolName) + '/chart')
+    this.liveTicketsChart.updateOptions({
+      file: liveTicketsData(chartResponse.data)
+    })
+    this.votesMissesChart.updateOptions({
+      file: votesMissesData(chartResponse.data)
+    })
+    this.missRateChart.updateOptions({
+      file: missRateData(chartResponse.data)
+    })
+
+    this.element.classList.remove('loading')
+  }
+
+  disconnect () {
+    this.liveTicketsChart.destroy()
+    this.votesMissesChart.destroy()
+    this.missRateChart.destroy()
+  }
+
+  drawCharts () {
+    this.liveTicketsChart = this.drawChart(
+      this.liveTicketsTarget,
+      [[0, 0]],
+      {
+        labels: ['Block Height', 'Live Tickets'],
+        ylabel: 'Live Tickets',
+        title: 'Live Tickets',
+        colors: ['#2971ff'],
+        fillGraph: true
+      }
+    )
+    this.votesMissesChart = this.drawChart(
+      this.votesMissesTarget,
+      [[0, 0, 0]],
+      {
+        labels: ['Block Height', 'Votes', 'Misses'],
+        ylabel: 'Tickets per Window',
+        title: 'Votes and Misses by Ticket Price Window',
+        colors: ['rgb(0,153,0)', 'red'],
+        stackedGraph: true,
+        plotter: barChartPlotter
+      }
+    )
+    this.missRateChart = this.drawChart(
+      this.missRateTarget,
+      [[0, 0]],
+      {
+        labels: ['Block Height', 'Miss Rate'],
+        ylabel: 'Miss Rate (%)',
+        title: 'Miss Rate by Ticket Price Window',
+        colors: ['orange'],
+        valueRange: [0, 100]
+      }
+    )
+  }
+
+  drawChart (el, data, options) {
+    return new this.Dygraph(
+      el,
+      data,
+      {
+        ...chartLayout,
+        ...options
+      }
+    )
+  }
+}
@@ -0,0 +1,108 @@
+import { Controller } from 'stimulus'
+import { barChartPlotter } from '../helpers/chart_helper'
+import { getDefault } from '../helpers/module_helper'
+import axios from 'axios'
+
+var chartLayout = {
+  showRangeSelector: true,
+  legend: 'follow',
+  labelsSeparateLines: true,
+  labelsKMB: true,
+  digitsAfterDecimal: 1
+}
+
+function poolSizeData (d) {
+  if (!d.time.length) return [[new Date(0), 0]]
+  return d.time.map((t, i) => [new Date(t * 1000), d.pool_size[i]])
+}
+
+function flowsData (d) {
+  if (!d.time.length) return [[new Date(0), 0, 0, 0]]
+  return d.time.map((t, i) => [new Date(t * 1000), d.maturing[i], d.votes[i], d.expiring[i]])
+}
+
+export default class extends Controller {
+  static get targets () {
+    return [
+      'range',
+      'poolSize',
+      'flows'
+    ]
+  }
+
+  initialize () {
+    this.poolSizeChart = false
+    this.flowsChart = false
+  }
+
+  async connect () {
+    this.Dygraph = await getDefault(
+      import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+    )
+    this.drawCharts()
+    this.fetchForecast('')
+  }
+
+  disconnect () {
+    this.poolSizeChart.destroy()
+    this.flowsChart.destroy()
+  }
+
+  onRangeChange (e) {
+    var target = e.srcElement || e.target
+    this.rangeTargets.forEach((rangeTarget) => {
+      rangeTarget.classList.remove('btn-active')
+    })
+    target.classList.add('btn-active')
+    this.fetchForecast(target.name)
+  }
+
+  async fetchForecast (query) {
+    this.element.classList.add('loading')
+    let forecastResponse = await axios.get('/api/stake/pool/forecast' + query)
+    this.poolSizeChart.updateOptions({
+      file: poolSizeData(forecastResponse.data)
+    })
+    this.flowsChart.updateOptions({
+      file: flowsData(forecastResponse.data)
+    })
+    this.element.classList.remove('loading')
+  }
+
+  drawCharts () {
+    this.poolSizeChart = this.drawChart(
+      this.poolSizeTarget,
+      [[new Date(0), 0]],
+      {
+        labels: ['Date', 'Pool Size'],
+        ylabel: 'Expected Pool Size',
+        title: 'Projected Ticket Pool Size',
+        colors: ['#2971ff'],
+        fillGraph: true
+      }
+    )
+    this.flowsChart = this.drawChart(
+      this.flowsTarget,
+      [[new Date(0), 0, 0, 0]],
+      {
+        labels: ['Date', 'Maturing', 'Votes', 'Expiring'],
+        ylabel: 'Number of Tickets',
+        title: 'Projected Maturing, Voting, and Expiring Tickets',
+        colors: ['#006600', 'rgb(0,153,0)', 'red'],
+        stackedGraph: true,
+        plotter: barChartPlotter
+      }
+    )
+  }
+
+  drawChart (el, data, options) {
+    return new this.Dygraph(
+      el,
+      data,
+      {
+        ...chartLayout,
+        ...options
+      }
+    )
+  }
+}
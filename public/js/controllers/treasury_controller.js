@@ -0,0 +1,102 @@
+import { Controller } from 'stimulus'
+import { multiColumnBarPlotter } from '../helpers/chart_helper'
+import { getDefault } from '../helpers/module_helper'
+import axios from 'axios'
+
+const atomsToFNO = 1e-8
+
+var chartLayout = {
+  showRangeSelector: true,
+  legend: 'follow',
+  labelsSeparateLines: true,
+  labelsKMB: true
+}
+
+function flowsData (m) {
+  if (!m.time.length) return [[new Date(0), 0, 0, 0, 0]]
+  return m.time.map((t, i) => [
+    new Date(t * 1000),
+    m.subsidy[i] * atomsToFNO,
+    m.other_in[i] * atomsToFNO,
+    (m.spent[i] - m.proposals[i]) * atomsToFNO,
+    m.proposals[i] * atomsToFNO
+  ])
+}
+
+function balanceData (m) {
+  if (!m.time.length) return [[new Date(0), 0]]
+  return m.time.map((t, i) => [new Date(t * 1000), m.balance[i] * atomsToFNO])
+}
+
+export default class extends Controller {
+  static get targets () {
+    return [
+      'flows',
+      'balance'
+    ]
+  }
+
+  initialize () {
+    this.flowsChart = false
+    this.balanceChart = false
+  }
+
+  async connect () {
+    this.element.classList.add('loading')
+    this.Dygraph = await getDefault(
+      import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+    )
+    this.drawCharts()
+    let response = await axios.get('/api/treasury')
+    this.flowsChart.updateOptions({
+      file: flowsData(response.data.monthly)
+    })
+    this.balanceChart.updateOptions({
+      file: balanceData(response.data.monthly)
+    })
+
+    this.element.classList.remove('loading')
+  }
+
+  disconnect () {
+    this.flowsChart.destroy()
+    this.balanceChart.destroy()
+  }
+
+  drawCharts () {
+    this.flowsChart = this.drawChart(
+      this.flowsTarget,
+      [[new Date(0), 0, 0, 0, 0]],
+      {
+        labels: ['Month', 'Block Subsidy', 'Other Inflows', 'Other Spends', 'Proposal Spends'],
+        ylabel: 'FNO per Month',
+        title: 'Monthly Project Fund Flows',
+        colors: ['#2971ff', '#41bf53', 'orange', 'red'],
+        fillColors: ['rgb(148,184,255)', 'rgb(160,223,169)', 'rgb(255,210,128)', 'rgb(255,128,128)'],
+        plotter: multiColumnBarPlotter
+      }
+    )
+    this.balanceChart = this.drawChart(
+      this.balanceTarget,
+      [[new Date(0), 0]],
+      {
+        labels: ['Month', 'Balance'],
+        ylabel: 'Balance (FNO)',
+        title: 'Project Fund Balance at Month End',
+        colors: ['#2971ff'],
+        fillGraph: true
+      }
+    )
+  }
+
+  drawChart (el, data, options) {
+    return new this.Dygraph(
+      el,
+      data,
+      {
+        ...chartLayout,
+        ...options
+      }
+    )
+  }
+}
@@ -0,0 +1,71 @@
+import { Controller } from 'stimulus'
+import { getDefault } from '../helpers/module_helper'
+import axios from 'axios'
+
+var chartLayout = {
+  showRangeSelector: true,
+  legend: 'follow',
+  labelsSeparateLines: true,
+  stackedGraph: true,
+  fillGraph: true,
+  valueRange: [0, 100],
+  digitsAfterDecimal: 1,
+  ylabel: 'Percent',
+  xlabel: 'Stake Version Interval Start Height'
+}
+
+function sharesLabels (shares) {
+  return ['Block Height'].concat(shares.map((vs) => 'v' + vs.version))
+}
+
+function sharesData (heights, shares) {
+  if (!heights.length || !shares.length) return [[0, 0]]
+  return heights.map((h, i) => [h].concat(shares.map((vs) => vs.percent[i])))
+}
+
+export default class extends Controller {
+  static get targets () {
+    return [
+      'blockVersions',
+      'stakeVersions',
+      'voteVersions'
+    ]
+  }
+
+  initialize () {
+    this.charts = []
+  }
+
+  async connect () {
+    this.element.classList.add('loading')
+    this.Dygraph = await getDefault(
+      import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+    )
+    let versionsResponse = await axios.get('/api/stake/versions')
+    let d = versionsResponse.data
+    this.charts = [
+      this.drawChart(this.blockVersionsTarget, d.height, d.block_versions, 'Block Versions'),
+      this.drawChart(this.stakeVersionsTarget, d.height, d.stake_versions, 'Stake Versions'),
+      this.drawChart(this.voteVersionsTarget, d.height, d.vote_versions, 'Vote Versions')
+    ]
+    this.element.classList.remove('loading')
+  }
+
+  disconnect () {
+    this.charts.forEach((chart) => chart.destroy())
+    this.charts = []
+  }
+
+  drawChart (el, heights, shares, title) {
+    var labels = shares.length ? sharesLabels(shares) : ['Block Height', 'None']
+    return new this.Dygraph(
+      el,
+      sharesData(heights, shares),
+      {
+        ...chartLayout,
+        labels: labels,
+        title: title
+      }
+    )
+  }
+}
@@ -37,6 +37,11 @@ var shutdownSignal = make(chan struct{})
 // Conditional compilation is used to also include SIGTERM on Unix.
 var signals = []os.Signal{os.Interrupt}
 
+// reloadSignals defines the signals that are handled to reload the files that
+// may be edited while running, such as the address labels file. There are none
+// on Windows. Conditional compilation is used to include SIGUSR1 on Unix.
+var reloadSignals []os.Signal
+
 // withShutdownCancel creates a copy of a context that is cancelled whenever
 // shutdown is invoked through an interrupt signal or from an JSON-RPC stop
 // request.
@@ -83,3 +88,25 @@ func shutdownListener() {
 		log.Info("Shutdown signaled. Already shutting down...")
 	}
 }
+
+// reloadListener calls reload whenever one of the reloadSignals is received,
+// until the context is cancelled. This function is intended to be spawned in a
+// new goroutine.
+func reloadListener(ctx context.Context, reload func()) {
+	if len(reloadSignals) == 0 {
+		return
+	}
+	reloadChannel := make(chan os.Signal, 1)
+	signal.Notify(reloadChannel, reloadSignals...)
+	defer signal.Stop(reloadChannel)
+
+	for {
+		select {
+		case sig := <-reloadChannel:
+			log.Infof("Received signal (%s). Reloading...", sig)
+			reload()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
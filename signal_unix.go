@@ -14,4 +14,5 @@ import (
 
 func init() {
 	signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	reloadSignals = []os.Signal{syscall.SIGUSR1}
 }
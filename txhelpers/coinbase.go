@@ -0,0 +1,65 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/txscript"
+	"github.com/fonero-project/fnod/wire"
+)
+
+// minCoinbaseTextLen is the shortest run of printable characters in a coinbase
+// script that is taken to be text rather than a chance sequence of bytes from
+// the extra nonce or block height.
+const minCoinbaseTextLen = 4
+
+// CoinbaseText extracts the text that miners put in the signature script of a
+// coinbase transaction, such as a pool name. The runs of at least
+// minCoinbaseTextLen printable ASCII characters are joined with spaces.
+func CoinbaseText(sigScript []byte) string {
+	var runs []string
+	start := -1
+	for i := 0; i <= len(sigScript); i++ {
+		if i < len(sigScript) && sigScript[i] >= 0x20 && sigScript[i] < 0x7f {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 && i-start >= minCoinbaseTextLen {
+			runs = append(runs, strings.TrimSpace(string(sigScript[start:i])))
+		}
+		start = -1
+	}
+	return strings.Join(runs, " ")
+}
+
+// CoinbasePayoutAddresses returns the distinct addresses paid by the outputs
+// of a coinbase transaction, in output order. The project fund subsidy output
+// is skipped.
+func CoinbasePayoutAddresses(coinbase *wire.MsgTx, params *chaincfg.Params) []string {
+	var addresses []string
+	seen := make(map[string]bool)
+	for _, txOut := range coinbase.TxOut {
+		if bytes.Equal(txOut.PkScript, params.OrganizationPkScript) {
+			continue
+		}
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, params)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			address := addr.EncodeAddress()
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
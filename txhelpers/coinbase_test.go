@@ -0,0 +1,47 @@
+package txhelpers
+
+import (
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/fnoutil"
+)
+
+func TestCoinbaseText(t *testing.T) {
+	tests := []struct {
+		script []byte
+		text   string
+	}{
+		{[]byte{0x04, 0xff, 0x0a, 0x3c, 0x00}, ""},
+		{append([]byte{0x08, 0x01, 0x02}, "/SuperPool/"...), "/SuperPool/"},
+		{append(append([]byte("abc\x00pool "), 0x9f), " fnod"...), "pool fnod"},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if text := CoinbaseText(test.script); text != test.text {
+			t.Errorf("CoinbaseText(%x): got %q, want %q", test.script, text, test.text)
+		}
+	}
+}
+
+func TestCoinbasePayoutAddresses(t *testing.T) {
+	block, _ := LoadTestBlockAndSSTX(t)
+	params := &chaincfg.MainNetParams
+	coinbase := block.MsgBlock().Transactions[0]
+
+	addresses := CoinbasePayoutAddresses(coinbase, params)
+	if len(addresses) == 0 {
+		t.Fatal("no coinbase payout addresses")
+	}
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		if seen[address] {
+			t.Errorf("duplicate address %s", address)
+		}
+		seen[address] = true
+		addr, err := fnoutil.DecodeAddress(address)
+		if err != nil || !addr.IsForNet(params) {
+			t.Errorf("invalid address %s: %v", address, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/fonero-project/fnod/blockchain/stake"
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/txscript"
+	"github.com/fonero-project/fnod/wire"
+)
+
+// commitmentScriptLen is the length of a ticket commitment output script:
+// OP_RETURN, OP_DATA_30, a 20-byte address hash, an 8-byte amount, and the
+// 2-byte fee limits.
+const commitmentScriptLen = 32
+
+// TicketCommitment is a decoded commitment output of a ticket purchase. The
+// Amount, in atoms, is contributed by the owner of the reward Address, which
+// receives the same share of the vote or revocation. VoteFeeLimit and
+// RevokeFeeLimit are the largest fees, in atoms, that may be paid from the
+// commitment by a vote or revocation of the ticket.
+type TicketCommitment struct {
+	Index          uint32
+	Address        string
+	Amount         int64
+	VoteFeeLimit   int64
+	RevokeFeeLimit int64
+}
+
+// feeLimit converts an encoded commitment fee limit to atoms. Fees are not
+// allowed unless the flag is set, in which case the limit is 2^exp atoms.
+func feeLimit(limits, flag, expMask uint16, expShift uint) int64 {
+	if limits&flag == 0 {
+		return 0
+	}
+	exp := (limits & expMask) >> expShift
+	if exp >= 63 {
+		return math.MaxInt64
+	}
+	return int64(1) << exp
+}
+
+// DecodeTicketCommitment decodes the commitment output script of a ticket
+// purchase. The script must be from an odd numbered output of a ticket, since
+// other nulldata scripts of the same size would also decode.
+func DecodeTicketCommitment(pkScript []byte, params *chaincfg.Params) (*TicketCommitment, error) {
+	if len(pkScript) != commitmentScriptLen || pkScript[0] != txscript.OP_RETURN ||
+		pkScript[1] != txscript.OP_DATA_30 {
+		return nil, fmt.Errorf("not a ticket commitment script")
+	}
+	addr, err := stake.AddrFromSStxPkScrCommitment(pkScript, params)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := stake.AmountFromSStxPkScrCommitment(pkScript)
+	if err != nil {
+		return nil, err
+	}
+	limits := binary.LittleEndian.Uint16(pkScript[30:])
+	return &TicketCommitment{
+		Address: addr.EncodeAddress(),
+		Amount:  int64(amount),
+		VoteFeeLimit: feeLimit(limits, stake.SStxVoteFractionFlag,
+			stake.SStxVoteReturnFractionMask, 0),
+		RevokeFeeLimit: feeLimit(limits, stake.SStxRevFractionFlag,
+			stake.SStxRevReturnFractionMask, 8),
+	}, nil
+}
+
+// TicketCommitments decodes the commitment outputs of a ticket purchase, which
+// are the odd numbered outputs.
+func TicketCommitments(tx *wire.MsgTx, params *chaincfg.Params) ([]*TicketCommitment, error) {
+	if stake.DetermineTxType(tx) != stake.TxTypeSStx {
+		return nil, fmt.Errorf("not a ticket purchase")
+	}
+	var commitments []*TicketCommitment
+	for i := 1; i < len(tx.TxOut); i += 2 {
+		c, err := DecodeTicketCommitment(tx.TxOut[i].PkScript, params)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %v", i, err)
+		}
+		c.Index = uint32(i)
+		commitments = append(commitments, c)
+	}
+	return commitments, nil
+}
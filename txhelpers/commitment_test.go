@@ -0,0 +1,69 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/txscript"
+)
+
+func TestTicketCommitments(t *testing.T) {
+	_, sstxs := LoadTestBlockAndSSTX(t)
+	params := &chaincfg.MainNetParams
+
+	for _, sstx := range sstxs {
+		tx := sstx.MsgTx()
+		commitments, err := TicketCommitments(tx, params)
+		if err != nil {
+			t.Fatalf("TicketCommitments(%v): %v", sstx.Hash(), err)
+		}
+		if len(commitments) != len(tx.TxOut)/2 {
+			t.Fatalf("found %d commitments for %d outputs", len(commitments),
+				len(tx.TxOut))
+		}
+
+		// The contributions less the change cover the ticket price and fee.
+		var contributed int64
+		for i, c := range commitments {
+			if c.Index != uint32(2*i+1) || c.Address == "" || c.Amount <= 0 {
+				t.Errorf("invalid commitment %d of ticket %v: %+v", i, sstx.Hash(), c)
+			}
+			contributed += c.Amount - tx.TxOut[c.Index+1].Value
+		}
+		if contributed < tx.TxOut[0].Value {
+			t.Errorf("contributions of %d less than ticket price %d for %v",
+				contributed, tx.TxOut[0].Value, sstx.Hash())
+		}
+	}
+}
+
+func TestDecodeTicketCommitment(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	script := make([]byte, commitmentScriptLen)
+	script[0], script[1] = txscript.OP_RETURN, txscript.OP_DATA_30
+	binary.LittleEndian.PutUint64(script[22:], 1e8)
+	// Vote fees up to 2^1 atoms, revocation fees up to 2^24 atoms.
+	binary.LittleEndian.PutUint16(script[30:], 0x5841)
+
+	c, err := DecodeTicketCommitment(script, params)
+	if err != nil {
+		t.Fatalf("DecodeTicketCommitment: %v", err)
+	}
+	if c.Amount != 1e8 || c.VoteFeeLimit != 2 || c.RevokeFeeLimit != 1<<24 {
+		t.Errorf("decoded %+v", c)
+	}
+
+	// No vote fees allowed.
+	binary.LittleEndian.PutUint16(script[30:], 0x5801)
+	if c, err = DecodeTicketCommitment(script, params); err != nil || c.VoteFeeLimit != 0 {
+		t.Errorf("expected no vote fees, got %+v (%v)", c, err)
+	}
+
+	if _, err = DecodeTicketCommitment(script[:31], params); err == nil {
+		t.Errorf("expected an error for a short script")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"fmt"
+
+	"github.com/fonero-project/fnod/blockchain"
+	"github.com/fonero-project/fnod/chaincfg/chainhash"
+	"github.com/fonero-project/fnod/fnoutil"
+	"github.com/fonero-project/fnod/wire"
+)
+
+// MerkleProof proves the inclusion of a transaction in one of the transaction
+// trees of a block. Leaf is the hash of the transaction used in the merkle
+// tree, Index is the position of the transaction in the tree, and Path is the
+// list of sibling hashes from the leaf up to, but not including, the root.
+type MerkleProof struct {
+	Tree  int8
+	Index uint32
+	Leaf  chainhash.Hash
+	Root  chainhash.Hash
+	Path  []chainhash.Hash
+}
+
+// Verify checks that the proof is valid and that its root is the merkle root
+// of the proof's transaction tree in the given block header.
+func (p *MerkleProof) Verify(header *wire.BlockHeader) bool {
+	root := header.MerkleRoot
+	if p.Tree == wire.TxTreeStake {
+		root = header.StakeRoot
+	}
+	return p.Root == root && VerifyMerkleProof(p.Leaf, p.Root, p.Index, p.Path)
+}
+
+// VerifyMerkleProof checks that hashing the leaf at the given index with the
+// sibling hashes of the path yields the merkle root.
+func VerifyMerkleProof(leaf, root chainhash.Hash, index uint32, path []chainhash.Hash) bool {
+	hash := leaf
+	for _, sibling := range path {
+		var buf [chainhash.HashSize * 2]byte
+		if index&1 == 0 {
+			copy(buf[:chainhash.HashSize], hash[:])
+			copy(buf[chainhash.HashSize:], sibling[:])
+		} else {
+			copy(buf[:chainhash.HashSize], sibling[:])
+			copy(buf[chainhash.HashSize:], hash[:])
+		}
+		hash = chainhash.HashH(buf[:])
+		index >>= 1
+	}
+	return index == 0 && hash == root
+}
+
+// BlockTxMerkleProof builds the merkle proof of the inclusion of the
+// transaction with the given hash in the regular or stake transaction tree of
+// the block.
+func BlockTxMerkleProof(msgBlock *wire.MsgBlock, txHash *chainhash.Hash) (*MerkleProof, error) {
+	block := fnoutil.NewBlock(msgBlock)
+	trees := []struct {
+		tree int8
+		txs  []*fnoutil.Tx
+	}{
+		{wire.TxTreeRegular, block.Transactions()},
+		{wire.TxTreeStake, block.STransactions()},
+	}
+	for _, t := range trees {
+		for i, tx := range t.txs {
+			if !tx.Hash().IsEqual(txHash) {
+				continue
+			}
+			merkles := blockchain.BuildMerkleTreeStore(t.txs)
+			return &MerkleProof{
+				Tree:  t.tree,
+				Index: uint32(i),
+				Leaf:  *merkles[i],
+				Root:  *merkles[len(merkles)-1],
+				Path:  merklePath(merkles, i),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %v not found in block %v", txHash,
+		msgBlock.BlockHash())
+}
+
+// merklePath extracts the sibling hashes of the leaf at the given index from a
+// merkle tree store built by blockchain.BuildMerkleTreeStore. In the store,
+// each level of the tree follows the previous one, and a node without a right
+// sibling is hashed with itself.
+func merklePath(merkles []*chainhash.Hash, index int) []chainhash.Hash {
+	var path []chainhash.Hash
+	width := (len(merkles) + 1) / 2
+	var levelStart int
+	for width > 1 {
+		sibling := merkles[levelStart+(index^1)]
+		if sibling == nil {
+			sibling = merkles[levelStart+index]
+		}
+		path = append(path, *sibling)
+		levelStart += width
+		width /= 2
+		index /= 2
+	}
+	return path
+}
@@ -0,0 +1,74 @@
+package txhelpers
+
+import (
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg/chainhash"
+	"github.com/fonero-project/fnod/wire"
+)
+
+func TestBlockTxMerkleProof(t *testing.T) {
+	block, _ := LoadTestBlockAndSSTX(t)
+	msgBlock := block.MsgBlock()
+
+	checkProofs := func(txs []*wire.MsgTx, tree int8) {
+		for i, tx := range txs {
+			txHash := tx.TxHash()
+			proof, err := BlockTxMerkleProof(msgBlock, &txHash)
+			if err != nil {
+				t.Fatalf("BlockTxMerkleProof(%v): %v", txHash, err)
+			}
+			if proof.Tree != tree || proof.Index != uint32(i) {
+				t.Errorf("incorrect tree %d or index %d for tx %v", proof.Tree,
+					proof.Index, txHash)
+			}
+			if !proof.Verify(&msgBlock.Header) {
+				t.Errorf("proof for tx %v failed verification", txHash)
+			}
+
+			// A proof for the wrong position must fail.
+			if len(txs) > 1 && VerifyMerkleProof(proof.Leaf, proof.Root,
+				proof.Index^1, proof.Path) {
+				t.Errorf("proof for tx %v verified at the wrong index", txHash)
+			}
+		}
+	}
+	checkProofs(msgBlock.Transactions, wire.TxTreeRegular)
+	checkProofs(msgBlock.STransactions, wire.TxTreeStake)
+
+	var missing chainhash.Hash
+	if _, err := BlockTxMerkleProof(msgBlock, &missing); err == nil {
+		t.Errorf("expected error for transaction not in block")
+	}
+}
+
+func TestVerifyMerkleProof(t *testing.T) {
+	leaves := []chainhash.Hash{{1}, {2}, {3}}
+	pair := func(l, r chainhash.Hash) chainhash.Hash {
+		return chainhash.HashH(append(l[:], r[:]...))
+	}
+	// The last leaf of an odd level is paired with itself.
+	root := pair(pair(leaves[0], leaves[1]), pair(leaves[2], leaves[2]))
+
+	tests := []struct {
+		name  string
+		leaf  chainhash.Hash
+		index uint32
+		path  []chainhash.Hash
+		want  bool
+	}{
+		{"first", leaves[0], 0, []chainhash.Hash{leaves[1], pair(leaves[2], leaves[2])}, true},
+		{"second", leaves[1], 1, []chainhash.Hash{leaves[0], pair(leaves[2], leaves[2])}, true},
+		{"odd last", leaves[2], 2, []chainhash.Hash{leaves[2], pair(leaves[0], leaves[1])}, true},
+		{"wrong index", leaves[0], 1, []chainhash.Hash{leaves[1], pair(leaves[2], leaves[2])}, false},
+		{"index out of range", leaves[0], 4, []chainhash.Hash{leaves[1], pair(leaves[2], leaves[2])}, false},
+		{"short path", leaves[0], 0, []chainhash.Hash{leaves[1]}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyMerkleProof(tt.leaf, root, tt.index, tt.path); got != tt.want {
+				t.Errorf("VerifyMerkleProof() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
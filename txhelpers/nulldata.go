@@ -0,0 +1,41 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fonero-project/fnod/txscript"
+)
+
+// NullDataPayload extracts the data carried by a nulldata (OP_RETURN) script.
+// The data pushed after the OP_RETURN are concatenated. false is returned if
+// the script is not a nulldata script.
+func NullDataPayload(script []byte) ([]byte, bool) {
+	if len(script) == 0 || script[0] != txscript.OP_RETURN {
+		return nil, false
+	}
+	pushes, err := txscript.PushedData(script[1:])
+	if err != nil {
+		return nil, false
+	}
+	return bytes.Join(pushes, nil), true
+}
+
+// NullDataText returns the nulldata payload as text if it is valid UTF-8 with
+// no control characters other than whitespace. An empty string is returned
+// otherwise.
+func NullDataText(payload []byte) string {
+	if len(payload) == 0 || !utf8.Valid(payload) {
+		return ""
+	}
+	for _, r := range string(payload) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return ""
+		}
+	}
+	return string(payload)
+}
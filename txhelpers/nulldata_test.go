@@ -0,0 +1,66 @@
+package txhelpers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fonero-project/fnod/txscript"
+)
+
+func TestNullDataPayload(t *testing.T) {
+	hello := []byte("hello")
+	world := []byte(" world")
+	tests := []struct {
+		name    string
+		script  []byte
+		want    []byte
+		wantOK  bool
+		wantTxt string
+	}{
+		{
+			name:    "single push",
+			script:  append([]byte{txscript.OP_RETURN, byte(len(hello))}, hello...),
+			want:    hello,
+			wantOK:  true,
+			wantTxt: "hello",
+		},
+		{
+			name: "two pushes",
+			script: append(append([]byte{txscript.OP_RETURN, byte(len(hello))}, hello...),
+				append([]byte{byte(len(world))}, world...)...),
+			want:    []byte("hello world"),
+			wantOK:  true,
+			wantTxt: "hello world",
+		},
+		{
+			name:    "binary",
+			script:  []byte{txscript.OP_RETURN, 2, 0x00, 0xff},
+			want:    []byte{0x00, 0xff},
+			wantOK:  true,
+			wantTxt: "",
+		},
+		{
+			name:   "not nulldata",
+			script: []byte{txscript.OP_DUP, txscript.OP_HASH160},
+			wantOK: false,
+		},
+		{
+			name:   "empty",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NullDataPayload(tt.script)
+			if ok != tt.wantOK {
+				t.Fatalf("NullDataPayload() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("NullDataPayload() = %x, want %x", got, tt.want)
+			}
+			if txt := NullDataText(got); txt != tt.wantTxt {
+				t.Errorf("NullDataText() = %q, want %q", txt, tt.wantTxt)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/fnoutil"
+	"github.com/fonero-project/fnod/txscript"
+)
+
+// RedeemScript is a decoded P2SH redeem script. Address is the P2SH address
+// paying to the script. For a multisig script, RequiredSigs of the PubKeys are
+// needed to spend, and Addresses are the P2PKH addresses of the PubKeys. For
+// other scripts, Addresses are the addresses paid to by the script.
+type RedeemScript struct {
+	Address      string
+	Script       []byte
+	Class        string
+	RequiredSigs int
+	PubKeys      []string
+	Addresses    []string
+}
+
+// DecodeRedeemScript decodes a P2SH redeem script. Nonstandard scripts, and
+// scripts that may not be redeem scripts, such as P2SH and nulldata scripts,
+// are rejected.
+func DecodeRedeemScript(script []byte, params *chaincfg.Params) (*RedeemScript, error) {
+	class, addrs, reqSigs, err := txscript.ExtractPkScriptAddrs(
+		txscript.DefaultScriptVersion, script, params)
+	if err != nil {
+		return nil, err
+	}
+	switch class {
+	case txscript.NonStandardTy, txscript.ScriptHashTy, txscript.NullDataTy:
+		return nil, fmt.Errorf("not a standard redeem script (%v)", class)
+	}
+
+	p2sh, err := fnoutil.NewAddressScriptHash(script, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &RedeemScript{
+		Address:      p2sh.EncodeAddress(),
+		Script:       script,
+		Class:        class.String(),
+		RequiredSigs: reqSigs,
+	}
+	for _, addr := range addrs {
+		// Multisig scripts pay to public keys. Use their P2PKH addresses.
+		if pk, ok := addr.(*fnoutil.AddressSecpPubKey); ok {
+			rs.PubKeys = append(rs.PubKeys, hex.EncodeToString(pk.ScriptAddress()))
+			rs.Addresses = append(rs.Addresses, pk.AddressPubKeyHash().EncodeAddress())
+			continue
+		}
+		rs.Addresses = append(rs.Addresses, addr.EncodeAddress())
+	}
+	return rs, nil
+}
+
+// SigScriptRedeemScript decodes the redeem script of a signature script that
+// spends a P2SH output. The redeem script is the final data push of the
+// signature script, which must contain only pushes.
+func SigScriptRedeemScript(sigScript []byte, params *chaincfg.Params) (*RedeemScript, error) {
+	if len(sigScript) == 0 || !txscript.IsPushOnlyScript(sigScript) {
+		return nil, fmt.Errorf("not a push only signature script")
+	}
+	pushes, err := txscript.PushedData(sigScript)
+	if err != nil {
+		return nil, err
+	}
+	if len(pushes) == 0 || len(pushes[len(pushes)-1]) == 0 {
+		return nil, fmt.Errorf("no redeem script in signature script")
+	}
+	return DecodeRedeemScript(pushes[len(pushes)-1], params)
+}
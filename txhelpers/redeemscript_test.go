@@ -0,0 +1,77 @@
+package txhelpers
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/fnoutil"
+	"github.com/fonero-project/fnod/txscript"
+)
+
+func TestSigScriptRedeemScript(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	// Compressed public keys of the secp256k1 points G, 2G, and 3G.
+	pubKeyStrs := []string{
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+		"02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+	}
+	var pubKeys []*fnoutil.AddressSecpPubKey
+	for _, s := range pubKeyStrs {
+		b, _ := hex.DecodeString(s)
+		pk, err := fnoutil.NewAddressSecpPubKey(b, params)
+		if err != nil {
+			t.Fatalf("NewAddressSecpPubKey: %v", err)
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+
+	redeemScript, err := txscript.MultiSigScript(pubKeys, 2)
+	if err != nil {
+		t.Fatalf("MultiSigScript: %v", err)
+	}
+	sigScript, err := txscript.NewScriptBuilder().AddData(make([]byte, 71)).
+		AddData(make([]byte, 71)).AddData(redeemScript).Script()
+	if err != nil {
+		t.Fatalf("ScriptBuilder: %v", err)
+	}
+
+	rs, err := SigScriptRedeemScript(sigScript, params)
+	if err != nil {
+		t.Fatalf("SigScriptRedeemScript: %v", err)
+	}
+
+	p2sh, _ := fnoutil.NewAddressScriptHash(redeemScript, params)
+	if rs.Address != p2sh.EncodeAddress() {
+		t.Errorf("address %s, expected %s", rs.Address, p2sh.EncodeAddress())
+	}
+	if rs.Class != txscript.MultiSigTy.String() || rs.RequiredSigs != 2 {
+		t.Errorf("decoded %d-of-%s script, expected 2-of-3 multisig",
+			rs.RequiredSigs, rs.Class)
+	}
+	if len(rs.PubKeys) != 3 || len(rs.Addresses) != 3 {
+		t.Fatalf("decoded %d pubkeys and %d addresses, expected 3",
+			len(rs.PubKeys), len(rs.Addresses))
+	}
+	for i, pk := range pubKeys {
+		if rs.PubKeys[i] != pubKeyStrs[i] {
+			t.Errorf("pubkey %d is %s, expected %s", i, rs.PubKeys[i], pubKeyStrs[i])
+		}
+		if addr := pk.AddressPubKeyHash().EncodeAddress(); rs.Addresses[i] != addr {
+			t.Errorf("address %d is %s, expected %s", i, rs.Addresses[i], addr)
+		}
+	}
+
+	// A P2PKH signature script ends with a public key, not a redeem script.
+	p2pkhSigScript, _ := txscript.NewScriptBuilder().AddData(make([]byte, 71)).
+		AddData(pubKeys[0].ScriptAddress()).Script()
+	if _, err = SigScriptRedeemScript(p2pkhSigScript, params); err == nil {
+		t.Errorf("expected an error for a P2PKH signature script")
+	}
+
+	// Signature scripts may only push data.
+	if _, err = SigScriptRedeemScript([]byte{txscript.OP_DUP}, params); err == nil {
+		t.Errorf("expected an error for a non-push signature script")
+	}
+}
@@ -1140,16 +1140,25 @@ func GenesisTxHash(params *chaincfg.Params) chainhash.Hash {
 	return params.GenesisBlock.Transactions[0].TxHash()
 }
 
-// IsZeroHashP2PHKAddress checks if the given address is the dummy (zero pubkey
-// hash) address. See https://github.com/fonero-project/fnodata/issues/358 for details.
-func IsZeroHashP2PHKAddress(checkAddressString string, params *chaincfg.Params) bool {
+// ZeroHashP2PHKAddress returns the dummy (zero pubkey hash) address for the
+// given network. Coins sent to this address are provably unspendable.
+func ZeroHashP2PHKAddress(params *chaincfg.Params) (string, error) {
 	zeroed := [20]byte{}
 	// expecting DsQxuVRvS4eaJ42dhQEsCXauMWjvopWgrVg address for mainnet
 	address, err := fnoutil.NewAddressPubKeyHash(zeroed[:], params, 0)
+	if err != nil {
+		return "", err
+	}
+	return address.String(), nil
+}
+
+// IsZeroHashP2PHKAddress checks if the given address is the dummy (zero pubkey
+// hash) address. See https://github.com/fonero-project/fnodata/issues/358 for details.
+func IsZeroHashP2PHKAddress(checkAddressString string, params *chaincfg.Params) bool {
+	zeroAddress, err := ZeroHashP2PHKAddress(params)
 	if err != nil {
 		return false
 	}
-	zeroAddress := address.String()
 	return checkAddressString == zeroAddress
 }
 
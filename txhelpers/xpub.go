@@ -0,0 +1,75 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"fmt"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/hdkeychain"
+)
+
+const (
+	// ExternalBranch is the BIP0044 branch of an account's extended key used
+	// for receiving addresses.
+	ExternalBranch uint32 = 0
+	// InternalBranch is the BIP0044 branch of an account's extended key used
+	// for change addresses.
+	InternalBranch uint32 = 1
+)
+
+// DecodeXpub decodes an extended public key string, and verifies that it is a
+// public key for the given network. Extended private keys are rejected.
+func DecodeXpub(xpub string, params *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extended key: %v", err)
+	}
+	if key.IsPrivate() {
+		return nil, fmt.Errorf("extended private keys are not accepted")
+	}
+	if !key.IsForNet(params) {
+		return nil, fmt.Errorf("extended key is not for network %s", params.Name)
+	}
+	return key, nil
+}
+
+// BranchAddress is an address derived from a branch of an extended key, and
+// its child index in the branch.
+type BranchAddress struct {
+	Address string
+	Index   uint32
+}
+
+// XpubBranchAddresses derives the P2PKH addresses of the child indexes start
+// through start+count-1 of the given branch (e.g. ExternalBranch) of the
+// extended key. Indexes that do not yield a valid child key, which is
+// extremely unlikely, are skipped as specified by BIP0032.
+func XpubBranchAddresses(key *hdkeychain.ExtendedKey, branch, start, count uint32,
+	params *chaincfg.Params) ([]BranchAddress, error) {
+	branchKey, err := key.Child(branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive branch %d: %v", branch, err)
+	}
+
+	addrs := make([]BranchAddress, 0, count)
+	for i := start; i < start+count; i++ {
+		child, err := branchKey.Child(i)
+		if err == hdkeychain.ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive child %d/%d: %v", branch, i, err)
+		}
+		addr, err := child.Address(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode address %d/%d: %v", branch, i, err)
+		}
+		addrs = append(addrs, BranchAddress{
+			Address: addr.EncodeAddress(),
+			Index:   i,
+		})
+	}
+	return addrs, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2019, The Fonero developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fonero-project/fnod/chaincfg"
+	"github.com/fonero-project/fnod/hdkeychain"
+)
+
+// testAccountKeys returns the BIP0044-style account 0 extended private key and
+// extended public key derived from a fixed seed.
+func testAccountKeys(t *testing.T, params *chaincfg.Params) (priv, pub *hdkeychain.ExtendedKey) {
+	seed := bytes.Repeat([]byte{0x2a}, hdkeychain.RecommendedSeedLen)
+	master, err := hdkeychain.NewMaster(seed, params)
+	if err != nil {
+		t.Fatalf("NewMaster: %v", err)
+	}
+	priv, err = master.Child(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		t.Fatalf("Child: %v", err)
+	}
+	pub, err = priv.Neuter()
+	if err != nil {
+		t.Fatalf("Neuter: %v", err)
+	}
+	return priv, pub
+}
+
+func TestDecodeXpub(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	priv, pub := testAccountKeys(t, params)
+
+	xpub, err := pub.String()
+	if err != nil {
+		t.Fatalf("String: %v", err)
+	}
+	key, err := DecodeXpub(xpub, params)
+	if err != nil {
+		t.Fatalf("DecodeXpub: %v", err)
+	}
+	if key.IsPrivate() {
+		t.Error("decoded key is private")
+	}
+
+	xpriv, err := priv.String()
+	if err != nil {
+		t.Fatalf("String: %v", err)
+	}
+	if _, err = DecodeXpub(xpriv, params); err == nil {
+		t.Error("extended private key accepted")
+	}
+	if _, err = DecodeXpub(xpub, &chaincfg.SimNetParams); err == nil {
+		t.Error("extended key for another network accepted")
+	}
+	if _, err = DecodeXpub("notakey", params); err == nil {
+		t.Error("invalid extended key accepted")
+	}
+}
+
+func TestXpubBranchAddresses(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	_, pub := testAccountKeys(t, params)
+
+	for _, branch := range []uint32{ExternalBranch, InternalBranch} {
+		const start, count = 5, 10
+		addrs, err := XpubBranchAddresses(pub, branch, start, count, params)
+		if err != nil {
+			t.Fatalf("XpubBranchAddresses: %v", err)
+		}
+		if len(addrs) != count {
+			t.Fatalf("got %d addresses, want %d", len(addrs), count)
+		}
+
+		branchKey, err := pub.Child(branch)
+		if err != nil {
+			t.Fatalf("Child: %v", err)
+		}
+		for i, addr := range addrs {
+			if addr.Index != start+uint32(i) {
+				t.Errorf("address %d has index %d, want %d", i, addr.Index, start+uint32(i))
+			}
+			child, err := branchKey.Child(addr.Index)
+			if err != nil {
+				t.Fatalf("Child: %v", err)
+			}
+			want, err := child.Address(params)
+			if err != nil {
+				t.Fatalf("Address: %v", err)
+			}
+			if addr.Address != want.EncodeAddress() {
+				t.Errorf("address %d/%d: got %s, want %s", branch, addr.Index,
+					addr.Address, want.EncodeAddress())
+			}
+		}
+	}
+
+	ext, err := XpubBranchAddresses(pub, ExternalBranch, 0, 1, params)
+	if err != nil {
+		t.Fatalf("XpubBranchAddresses: %v", err)
+	}
+	internal, err := XpubBranchAddresses(pub, InternalBranch, 0, 1, params)
+	if err != nil {
+		t.Fatalf("XpubBranchAddresses: %v", err)
+	}
+	if ext[0].Address == internal[0].Address {
+		t.Error("external and internal branches derived the same address")
+	}
+}